@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lex00/aws-sam-translator-go/pkg/sam"
+	"github.com/lex00/aws-sam-translator-go/pkg/translator"
+	"github.com/spf13/cobra"
+)
+
+// CapabilitiesOptions holds the configuration for the capabilities subcommand.
+type CapabilitiesOptions struct {
+	OutputFormat string
+}
+
+// Capabilities lists what the translator supports.
+type Capabilities struct {
+	ResourceTypes     []string                   `json:"resourceTypes"`
+	EventTypes        []string                   `json:"eventTypes"`
+	ConnectorProfiles []sam.ConnectorProfilePair `json:"connectorProfiles"`
+}
+
+// newCapabilitiesCmd creates the capabilities subcommand, which lists the
+// supported SAM resource types, function event types and connector profiles.
+func newCapabilitiesCmd() *cobra.Command {
+	var opts CapabilitiesOptions
+
+	cmd := &cobra.Command{
+		Use:   "capabilities",
+		Short: "List supported SAM resource types, event types and connector profiles",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			exitCode := runCapabilities(&opts, cmd.OutOrStdout(), cmd.ErrOrStderr())
+			if exitCode != ExitSuccess {
+				os.Exit(exitCode)
+			}
+			return nil
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVar(&opts.OutputFormat, "output-format", "text", "Output format: text or json")
+
+	return cmd
+}
+
+// collectCapabilities reads the capabilities from the translator registries.
+func collectCapabilities() Capabilities {
+	return Capabilities{
+		ResourceTypes:     translator.SupportedResourceTypes(),
+		EventTypes:        sam.SupportedEventTypes(),
+		ConnectorProfiles: sam.NewConnectorProfiles().Pairs(),
+	}
+}
+
+// runCapabilities writes the capabilities to stdout in the requested format.
+func runCapabilities(opts *CapabilitiesOptions, stdout io.Writer, stderr io.Writer) int {
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+
+	caps := collectCapabilities()
+
+	switch opts.OutputFormat {
+	case "json":
+		output, err := json.MarshalIndent(caps, "", "  ")
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: failed to marshal capabilities: %v\n", err)
+			return ExitTransformError
+		}
+		fmt.Fprintln(stdout, string(output))
+	case "text", "":
+		fmt.Fprintln(stdout, "Resource types:")
+		for _, resourceType := range caps.ResourceTypes {
+			fmt.Fprintf(stdout, "  %s\n", resourceType)
+		}
+		fmt.Fprintln(stdout, "\nFunction event types:")
+		for _, eventType := range caps.EventTypes {
+			fmt.Fprintf(stdout, "  %s\n", eventType)
+		}
+		fmt.Fprintln(stdout, "\nConnector profiles:")
+		for _, pair := range caps.ConnectorProfiles {
+			fmt.Fprintf(stdout, "  %s -> %s\n", pair.Source, pair.Destination)
+		}
+	default:
+		fmt.Fprintf(stderr, "Error: unsupported output format %q (use text or json)\n", opts.OutputFormat)
+		return ExitInvalidArgs
+	}
+
+	return ExitSuccess
+}
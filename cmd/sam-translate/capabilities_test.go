@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRunCapabilities(t *testing.T) {
+	t.Run("text output lists known types", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		exitCode := runCapabilities(&CapabilitiesOptions{OutputFormat: "text"}, &stdout, &stderr)
+		if exitCode != ExitSuccess {
+			t.Fatalf("exitCode = %d, want %d (stderr: %s)", exitCode, ExitSuccess, stderr.String())
+		}
+
+		out := stdout.String()
+		for _, want := range []string{
+			"AWS::Serverless::Function",
+			"AWS::Serverless::Connector",
+			"  SQS\n",
+			"  HttpApi\n",
+			"AWS::Lambda::Function -> AWS::DynamoDB::Table",
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("expected output to contain %q, got:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		exitCode := runCapabilities(&CapabilitiesOptions{OutputFormat: "json"}, &stdout, &stderr)
+		if exitCode != ExitSuccess {
+			t.Fatalf("exitCode = %d, want %d (stderr: %s)", exitCode, ExitSuccess, stderr.String())
+		}
+
+		var caps Capabilities
+		if err := json.Unmarshal(stdout.Bytes(), &caps); err != nil {
+			t.Fatalf("failed to parse JSON output: %v", err)
+		}
+		if !containsValue(caps.ResourceTypes, "AWS::Serverless::StateMachine") {
+			t.Errorf("expected StateMachine in resource types, got %v", caps.ResourceTypes)
+		}
+		if !containsValue(caps.EventTypes, "Schedule") {
+			t.Errorf("expected Schedule in event types, got %v", caps.EventTypes)
+		}
+		if len(caps.ConnectorProfiles) == 0 {
+			t.Error("expected connector profiles")
+		}
+	})
+
+	t.Run("unknown format returns 2", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		exitCode := runCapabilities(&CapabilitiesOptions{OutputFormat: "yaml"}, &stdout, &stderr)
+		if exitCode != ExitInvalidArgs {
+			t.Errorf("exitCode = %d, want %d", exitCode, ExitInvalidArgs)
+		}
+	})
+}
+
+func containsValue(values []string, want string) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+
+	"github.com/lex00/aws-sam-translator-go/pkg/parser"
+	"github.com/lex00/aws-sam-translator-go/pkg/region"
+	"github.com/lex00/aws-sam-translator-go/pkg/translator"
+	"github.com/spf13/cobra"
+)
+
+// DiffOptions holds the configuration for the diff subcommand.
+type DiffOptions struct {
+	TemplateFile string
+	PythonOutput string
+	Region       string
+}
+
+// newDiffCmd creates the diff subcommand, which compares the Go translator
+// output against a reference CloudFormation template produced by the Python translator.
+func newDiffCmd() *cobra.Command {
+	var opts DiffOptions
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare translator output against Python translator output",
+		Long: `diff transforms a SAM template and compares the result against a reference
+CloudFormation JSON template produced by the Python aws-sam-translator.
+Exits with a non-zero status when differences are found.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			exitCode := runDiff(&opts, cmd.OutOrStdout(), cmd.ErrOrStderr())
+			if exitCode != ExitSuccess {
+				os.Exit(exitCode)
+			}
+			return nil
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&opts.TemplateFile, "template", "t", "", "Path to SAM template file (required)")
+	cmd.Flags().StringVar(&opts.PythonOutput, "python-output", "", "Path to reference CloudFormation JSON from the Python translator (required)")
+	cmd.Flags().StringVar(&opts.Region, "region", "", "AWS region for partition detection (default: $AWS_REGION, $AWS_DEFAULT_REGION, then us-east-1)")
+
+	_ = cmd.MarkFlagRequired("template")
+	_ = cmd.MarkFlagRequired("python-output")
+
+	return cmd
+}
+
+// runDiff transforms the template and writes the differences against the reference to stdout.
+// It returns ExitSuccess when the outputs match and ExitDiffFound when they differ.
+func runDiff(opts *DiffOptions, stdout io.Writer, stderr io.Writer) int {
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+
+	input, err := os.ReadFile(opts.TemplateFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: failed to read template file: %v\n", err)
+		return ExitIOError
+	}
+
+	referenceBytes, err := os.ReadFile(opts.PythonOutput)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: failed to read python output file: %v\n", err)
+		return ExitIOError
+	}
+
+	var reference map[string]interface{}
+	if err := json.Unmarshal(referenceBytes, &reference); err != nil {
+		fmt.Fprintf(stderr, "Error: failed to parse python output file: %v\n", err)
+		return ExitTransformError
+	}
+
+	regionName := resolveRegion(opts.Region)
+	tr := translator.NewWithOptions(translator.Options{
+		Region:    region.RegionOrDefault(regionName),
+		Partition: getPartitionForRegion(regionName),
+		// The Python translator does not check the Transform header
+		TransformHeader: translator.TransformHeaderIgnore,
+	})
+
+	outputBytes, err := tr.TransformBytes(input)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s\n", formatError(err))
+		return ExitTransformError
+	}
+
+	var output map[string]interface{}
+	if err := json.Unmarshal(outputBytes, &output); err != nil {
+		fmt.Fprintf(stderr, "Error: failed to parse translator output: %v\n", err)
+		return ExitTransformError
+	}
+
+	diffs := diffTemplates(reference, output)
+	if len(diffs) == 0 {
+		fmt.Fprintln(stdout, "No differences found.")
+		return ExitSuccess
+	}
+
+	for _, d := range diffs {
+		fmt.Fprintln(stdout, d)
+	}
+	fmt.Fprintf(stdout, "%d difference(s) found.\n", len(diffs))
+
+	return ExitDiffFound
+}
+
+// diffTemplates compares a reference template against translator output.
+// Resources are compared by logical ID first so missing and extra resources
+// are reported once rather than as a list of property differences.
+func diffTemplates(reference, output map[string]interface{}) []string {
+	var diffs []string
+
+	refResources, _ := reference["Resources"].(map[string]interface{})
+	outResources, _ := output["Resources"].(map[string]interface{})
+
+	for _, id := range sortedUnionKeys(refResources, outResources) {
+		refRes, inRef := refResources[id]
+		outRes, inOut := outResources[id]
+
+		switch {
+		case !inOut:
+			diffs = append(diffs, fmt.Sprintf("- missing resource: %s (%v)", id, resourceType(refRes)))
+		case !inRef:
+			diffs = append(diffs, fmt.Sprintf("+ extra resource: %s (%v)", id, resourceType(outRes)))
+		default:
+			diffs = append(diffs, diffValues("Resources."+id, refRes, outRes)...)
+		}
+	}
+
+	// Compare the remaining top-level sections generically
+	for _, key := range sortedUnionKeys(reference, output) {
+		if key == "Resources" {
+			continue
+		}
+		refVal, inRef := reference[key]
+		outVal, inOut := output[key]
+
+		switch {
+		case !inOut:
+			diffs = append(diffs, fmt.Sprintf("- missing: %s", key))
+		case !inRef:
+			diffs = append(diffs, fmt.Sprintf("+ extra: %s", key))
+		default:
+			diffs = append(diffs, diffValues(key, refVal, outVal)...)
+		}
+	}
+
+	return diffs
+}
+
+// writeInputDiff prints how the transform changed the template's Resources.
+func writeInputDiff(input, output []byte, stdout io.Writer) error {
+	inputTemplate, err := parser.New().ParseRaw(input)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var outputTemplate map[string]interface{}
+	if err := json.Unmarshal(output, &outputTemplate); err != nil {
+		return fmt.Errorf("failed to parse translator output: %w", err)
+	}
+
+	changes := diffResources(inputTemplate, outputTemplate)
+	if len(changes) == 0 {
+		fmt.Fprintln(stdout, "No resources changed.")
+		return nil
+	}
+
+	for _, c := range changes {
+		fmt.Fprintln(stdout, c)
+	}
+	fmt.Fprintf(stdout, "%d resource change(s).\n", len(changes))
+	return nil
+}
+
+// diffResources describes, by logical ID, the resources the transform added,
+// removed, or expanded into a different type, and property changes on
+// resources that kept their type.
+func diffResources(input, output map[string]interface{}) []string {
+	var changes []string
+
+	inResources, _ := input["Resources"].(map[string]interface{})
+	outResources, _ := output["Resources"].(map[string]interface{})
+
+	for _, id := range sortedUnionKeys(inResources, outResources) {
+		inRes, inInput := inResources[id]
+		outRes, inOutput := outResources[id]
+
+		switch {
+		case !inOutput:
+			changes = append(changes, fmt.Sprintf("- removed resource: %s (%v)", id, resourceType(inRes)))
+		case !inInput:
+			changes = append(changes, fmt.Sprintf("+ added resource: %s (%v)", id, resourceType(outRes)))
+		case !reflect.DeepEqual(resourceType(inRes), resourceType(outRes)):
+			changes = append(changes, fmt.Sprintf("~ transformed resource: %s (%v -> %v)", id, resourceType(inRes), resourceType(outRes)))
+		default:
+			changes = append(changes, diffValues("Resources."+id, inRes, outRes)...)
+		}
+	}
+
+	return changes
+}
+
+// diffValues recursively compares two JSON values and returns the differences found under path.
+func diffValues(path string, expected, actual interface{}) []string {
+	expectedMap, expectedIsMap := expected.(map[string]interface{})
+	actualMap, actualIsMap := actual.(map[string]interface{})
+	if expectedIsMap && actualIsMap {
+		var diffs []string
+		for _, key := range sortedUnionKeys(expectedMap, actualMap) {
+			childPath := path + "." + key
+			expectedVal, inExpected := expectedMap[key]
+			actualVal, inActual := actualMap[key]
+
+			switch {
+			case !inActual:
+				diffs = append(diffs, fmt.Sprintf("- missing: %s", childPath))
+			case !inExpected:
+				diffs = append(diffs, fmt.Sprintf("+ extra: %s", childPath))
+			default:
+				diffs = append(diffs, diffValues(childPath, expectedVal, actualVal)...)
+			}
+		}
+		return diffs
+	}
+
+	expectedList, expectedIsList := expected.([]interface{})
+	actualList, actualIsList := actual.([]interface{})
+	if expectedIsList && actualIsList && len(expectedList) == len(actualList) {
+		var diffs []string
+		for i := range expectedList {
+			diffs = append(diffs, diffValues(fmt.Sprintf("%s[%d]", path, i), expectedList[i], actualList[i])...)
+		}
+		return diffs
+	}
+
+	if reflect.DeepEqual(expected, actual) {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("~ changed: %s: expected %s, got %s", path, compactJSON(expected), compactJSON(actual))}
+}
+
+// sortedUnionKeys returns the sorted set of keys present in either map.
+func sortedUnionKeys(a, b map[string]interface{}) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	var keys []string
+	for _, m := range []map[string]interface{}{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// resourceType returns the Type of a raw resource, if present.
+func resourceType(resource interface{}) interface{} {
+	if m, ok := resource.(map[string]interface{}); ok {
+		return m["Type"]
+	}
+	return nil
+}
+
+// compactJSON renders a value as single-line JSON for diff output.
+func compactJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
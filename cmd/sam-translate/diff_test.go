@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lex00/aws-sam-translator-go/pkg/translator"
+)
+
+const diffTestTemplate = `AWSTemplateFormatVersion: '2010-09-09'
+Transform: AWS::Serverless-2016-10-31
+Resources:
+  MyFunction:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: index.handler
+      Runtime: nodejs18.x
+      CodeUri: s3://bucket/key
+      MemorySize: 256
+`
+
+// writeDiffFixtures writes the test template and a reference built from the
+// current translator output, after applying mutate to the reference.
+func writeDiffFixtures(t *testing.T, mutate func(map[string]interface{})) (string, string) {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	templateFile := filepath.Join(tmpDir, "template.yaml")
+	if err := os.WriteFile(templateFile, []byte(diffTestTemplate), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	output, err := translator.New().TransformBytes([]byte(diffTestTemplate))
+	if err != nil {
+		t.Fatalf("TransformBytes failed: %v", err)
+	}
+
+	var reference map[string]interface{}
+	if err := json.Unmarshal(output, &reference); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	if mutate != nil {
+		mutate(reference)
+	}
+
+	referenceBytes, err := json.Marshal(reference)
+	if err != nil {
+		t.Fatalf("failed to marshal reference: %v", err)
+	}
+	referenceFile := filepath.Join(tmpDir, "python.json")
+	if err := os.WriteFile(referenceFile, referenceBytes, 0644); err != nil {
+		t.Fatalf("failed to write reference: %v", err)
+	}
+
+	return templateFile, referenceFile
+}
+
+func TestRunDiff(t *testing.T) {
+	t.Run("matching reference returns 0", func(t *testing.T) {
+		templateFile, referenceFile := writeDiffFixtures(t, nil)
+
+		var stdout, stderr bytes.Buffer
+		exitCode := runDiff(&DiffOptions{TemplateFile: templateFile, PythonOutput: referenceFile}, &stdout, &stderr)
+		if exitCode != ExitSuccess {
+			t.Fatalf("exitCode = %d, want %d (stderr: %s)", exitCode, ExitSuccess, stderr.String())
+		}
+		if !strings.Contains(stdout.String(), "No differences found") {
+			t.Errorf("expected no differences message, got %q", stdout.String())
+		}
+	})
+
+	t.Run("mismatching reference returns 4", func(t *testing.T) {
+		templateFile, referenceFile := writeDiffFixtures(t, func(ref map[string]interface{}) {
+			resources := ref["Resources"].(map[string]interface{})
+			fn := resources["MyFunction"].(map[string]interface{})
+			fn["Properties"].(map[string]interface{})["MemorySize"] = 512
+			delete(resources, "MyFunctionRole")
+			resources["MyTopic"] = map[string]interface{}{"Type": "AWS::SNS::Topic"}
+		})
+
+		var stdout, stderr bytes.Buffer
+		exitCode := runDiff(&DiffOptions{TemplateFile: templateFile, PythonOutput: referenceFile}, &stdout, &stderr)
+		if exitCode != ExitDiffFound {
+			t.Fatalf("exitCode = %d, want %d (stderr: %s)", exitCode, ExitDiffFound, stderr.String())
+		}
+
+		out := stdout.String()
+		for _, want := range []string{
+			"~ changed: Resources.MyFunction.Properties.MemorySize: expected 512, got 256",
+			"- missing resource: MyTopic (AWS::SNS::Topic)",
+			"+ extra resource: MyFunctionRole (AWS::IAM::Role)",
+			"3 difference(s) found.",
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("expected output to contain %q, got:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("missing reference file returns 3", func(t *testing.T) {
+		templateFile, _ := writeDiffFixtures(t, nil)
+
+		var stdout, stderr bytes.Buffer
+		exitCode := runDiff(&DiffOptions{TemplateFile: templateFile, PythonOutput: "/nonexistent/python.json"}, &stdout, &stderr)
+		if exitCode != ExitIOError {
+			t.Errorf("exitCode = %d, want %d", exitCode, ExitIOError)
+		}
+	})
+
+	t.Run("missing template file returns 3", func(t *testing.T) {
+		_, referenceFile := writeDiffFixtures(t, nil)
+
+		var stdout, stderr bytes.Buffer
+		exitCode := runDiff(&DiffOptions{TemplateFile: "/nonexistent/template.yaml", PythonOutput: referenceFile}, &stdout, &stderr)
+		if exitCode != ExitIOError {
+			t.Errorf("exitCode = %d, want %d", exitCode, ExitIOError)
+		}
+	})
+}
+
+func TestDiffTemplatesSortedOutput(t *testing.T) {
+	reference := map[string]interface{}{
+		"Resources": map[string]interface{}{
+			"B": map[string]interface{}{"Type": "AWS::SNS::Topic"},
+			"A": map[string]interface{}{"Type": "AWS::SQS::Queue"},
+		},
+	}
+	output := map[string]interface{}{
+		"Resources": map[string]interface{}{},
+	}
+
+	diffs := diffTemplates(reference, output)
+	if len(diffs) != 2 {
+		t.Fatalf("expected 2 diffs, got %v", diffs)
+	}
+	if !strings.Contains(diffs[0], ": A ") || !strings.Contains(diffs[1], ": B ") {
+		t.Errorf("expected diffs sorted by logical ID, got %v", diffs)
+	}
+}
+
+func TestRunTransformDiffAgainstInput(t *testing.T) {
+	template := `AWSTemplateFormatVersion: '2010-09-09'
+Transform: AWS::Serverless-2016-10-31
+Resources:
+  MyQueue:
+    Type: AWS::SQS::Queue
+  MyFunction:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: index.handler
+      Runtime: nodejs18.x
+      CodeUri: s3://bucket/key
+      AutoPublishAlias: live
+      Events:
+        Queue:
+          Type: SQS
+          Properties:
+            Queue: !GetAtt MyQueue.Arn
+`
+	templateFile := filepath.Join(t.TempDir(), "template.yaml")
+	if err := os.WriteFile(templateFile, []byte(template), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	exitCode := runTransform(&Options{TemplateFile: templateFile, DiffAgainstInput: true}, &stdout, &stderr)
+	if exitCode != ExitSuccess {
+		t.Fatalf("exitCode = %d, want %d (stderr: %s)", exitCode, ExitSuccess, stderr.String())
+	}
+
+	out := stdout.String()
+	for _, want := range []string{
+		"~ transformed resource: MyFunction (AWS::Serverless::Function -> AWS::Lambda::Function)",
+		"+ added resource: MyFunctionRole (AWS::IAM::Role)",
+		"+ added resource: MyFunctionVersion (AWS::Lambda::Version)",
+		"+ added resource: MyFunctionAliaslive (AWS::Lambda::Alias)",
+		"+ added resource: MyFunctionQueue (AWS::Lambda::EventSourceMapping)",
+		"5 resource change(s).",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "MyQueue") {
+		t.Errorf("unchanged resources should not be reported, got:\n%s", out)
+	}
+
+	// With --stdout the template is written to stdout and the diff to stderr
+	stdout.Reset()
+	stderr.Reset()
+	exitCode = runTransform(&Options{TemplateFile: templateFile, DiffAgainstInput: true, Stdout: true}, &stdout, &stderr)
+	if exitCode != ExitSuccess {
+		t.Fatalf("exitCode = %d, want %d (stderr: %s)", exitCode, ExitSuccess, stderr.String())
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Errorf("expected stdout to be the template JSON, got %v:\n%s", err, stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "5 resource change(s).") {
+		t.Errorf("expected the diff on stderr, got:\n%s", stderr.String())
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lex00/aws-sam-translator-go/pkg/region"
+	"github.com/lex00/aws-sam-translator-go/pkg/translator"
+	"github.com/spf13/cobra"
+)
+
+// GraphOptions holds the configuration for the graph subcommand.
+type GraphOptions struct {
+	TemplateFile string
+	OutputFormat string
+	Region       string
+}
+
+// newGraphCmd creates the graph subcommand, which prints the dependency
+// graph of the resources a SAM template transforms into.
+func newGraphCmd() *cobra.Command {
+	var opts GraphOptions
+
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Print the dependency graph of the transformed resources",
+		Long: `graph transforms a SAM template and prints the dependencies between the
+resulting resources, taken from DependsOn, Ref, Fn::GetAtt and Fn::Sub, in
+Graphviz DOT or JSON format.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			exitCode := runGraph(&opts, cmd.OutOrStdout(), cmd.ErrOrStderr())
+			if exitCode != ExitSuccess {
+				os.Exit(exitCode)
+			}
+			return nil
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&opts.TemplateFile, "template", "t", "", "Path to SAM template file (required)")
+	cmd.Flags().StringVar(&opts.OutputFormat, "output-format", "dot", "Output format: dot or json")
+	cmd.Flags().StringVar(&opts.Region, "region", "", "AWS region for partition detection (default: $AWS_REGION, $AWS_DEFAULT_REGION, then us-east-1)")
+
+	_ = cmd.MarkFlagRequired("template")
+
+	return cmd
+}
+
+// runGraph transforms the template and writes its resource graph to stdout
+// in the requested format.
+func runGraph(opts *GraphOptions, stdout io.Writer, stderr io.Writer) int {
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+
+	if opts.OutputFormat != "dot" && opts.OutputFormat != "json" && opts.OutputFormat != "" {
+		fmt.Fprintf(stderr, "Error: unsupported output format %q (use dot or json)\n", opts.OutputFormat)
+		return ExitInvalidArgs
+	}
+
+	input, err := os.ReadFile(opts.TemplateFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: failed to read template file: %v\n", err)
+		return ExitIOError
+	}
+
+	regionName := resolveRegion(opts.Region)
+	tr := translator.NewWithOptions(translator.Options{
+		Region:    region.RegionOrDefault(regionName),
+		Partition: getPartitionForRegion(regionName),
+	})
+
+	graph, err := tr.Graph(input)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s\n", formatError(err))
+		return ExitTransformError
+	}
+
+	if opts.OutputFormat == "json" {
+		output, err := json.MarshalIndent(graph, "", "  ")
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: failed to marshal graph: %v\n", err)
+			return ExitTransformError
+		}
+		fmt.Fprintln(stdout, string(output))
+		return ExitSuccess
+	}
+
+	fmt.Fprint(stdout, graph.DOT())
+	return ExitSuccess
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lex00/aws-sam-translator-go/pkg/translator"
+)
+
+func writeGraphTemplate(t *testing.T) string {
+	t.Helper()
+
+	templateFile := filepath.Join(t.TempDir(), "template.yaml")
+	if err := os.WriteFile(templateFile, []byte(diffTestTemplate), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	return templateFile
+}
+
+func TestRunGraph(t *testing.T) {
+	t.Run("dot output", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		exitCode := runGraph(&GraphOptions{TemplateFile: writeGraphTemplate(t), OutputFormat: "dot"}, &stdout, &stderr)
+		if exitCode != ExitSuccess {
+			t.Fatalf("exitCode = %d, want %d (stderr: %s)", exitCode, ExitSuccess, stderr.String())
+		}
+		if !strings.Contains(stdout.String(), `"MyFunction" -> "MyFunctionRole" [label="GetAtt"];`) {
+			t.Errorf("expected function to role edge, got:\n%s", stdout.String())
+		}
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		exitCode := runGraph(&GraphOptions{TemplateFile: writeGraphTemplate(t), OutputFormat: "json"}, &stdout, &stderr)
+		if exitCode != ExitSuccess {
+			t.Fatalf("exitCode = %d, want %d (stderr: %s)", exitCode, ExitSuccess, stderr.String())
+		}
+
+		var graph translator.ResourceGraph
+		if err := json.Unmarshal(stdout.Bytes(), &graph); err != nil {
+			t.Fatalf("failed to parse JSON output: %v", err)
+		}
+		if len(graph.Nodes) != 2 {
+			t.Errorf("expected 2 nodes, got %+v", graph.Nodes)
+		}
+		if len(graph.Edges) != 1 || graph.Edges[0].From != "MyFunction" || graph.Edges[0].To != "MyFunctionRole" {
+			t.Errorf("expected a single function to role edge, got %+v", graph.Edges)
+		}
+	})
+
+	t.Run("unknown format returns 2", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		exitCode := runGraph(&GraphOptions{TemplateFile: writeGraphTemplate(t), OutputFormat: "svg"}, &stdout, &stderr)
+		if exitCode != ExitInvalidArgs {
+			t.Errorf("exitCode = %d, want %d", exitCode, ExitInvalidArgs)
+		}
+	})
+
+	t.Run("missing template returns 3", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		exitCode := runGraph(&GraphOptions{TemplateFile: "/nonexistent/template.yaml"}, &stdout, &stderr)
+		if exitCode != ExitIOError {
+			t.Errorf("exitCode = %d, want %d", exitCode, ExitIOError)
+		}
+	})
+}
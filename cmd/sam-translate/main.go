@@ -2,9 +2,12 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/lex00/aws-sam-translator-go/pkg/region"
@@ -19,6 +22,17 @@ const (
 	ExitInvalidArgs    = 2
 )
 
+// Exit codes specific to this CLI, which Python sam-translator does not have.
+const (
+	// ExitIOError is returned when the template cannot be read or the
+	// output cannot be written, so CI can tell a bad invocation from a bad template.
+	ExitIOError = 3
+
+	// ExitDiffFound is returned by the diff subcommand when outputs differ,
+	// so a difference is not mistaken for a failed transform.
+	ExitDiffFound = 4
+)
+
 // Options holds the CLI configuration.
 type Options struct {
 	TemplateFile   string
@@ -26,6 +40,13 @@ type Options struct {
 	Stdout         bool
 	Verbose        bool
 	Region         string
+
+	// DiffAgainstInput prints the resources the transform added, removed or
+	// changed, to stderr when Stdout is also set.
+	DiffAgainstInput bool
+
+	// ResourcesOnly writes only the transformed Resources map instead of the full template.
+	ResourcesOnly bool
 }
 
 func main() {
@@ -51,8 +72,8 @@ func newRootCmd() *cobra.Command {
 			}
 
 			// Validate that either output file or stdout is specified
-			if opts.OutputTemplate == "" && !opts.Stdout {
-				return fmt.Errorf("either --output-template or --stdout must be specified")
+			if opts.OutputTemplate == "" && !opts.Stdout && !opts.DiffAgainstInput {
+				return fmt.Errorf("either --output-template, --stdout or --diff-against-input must be specified")
 			}
 
 			// Run the transform
@@ -71,11 +92,17 @@ func newRootCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&opts.OutputTemplate, "output-template", "o", "", "Path to output CloudFormation template")
 	cmd.Flags().BoolVar(&opts.Stdout, "stdout", false, "Write output to stdout")
 	cmd.Flags().BoolVar(&opts.Verbose, "verbose", false, "Enable verbose logging")
-	cmd.Flags().StringVar(&opts.Region, "region", "", "AWS region for partition detection (default: us-east-1)")
+	cmd.Flags().StringVar(&opts.Region, "region", "", "AWS region for partition detection (default: $AWS_REGION, $AWS_DEFAULT_REGION, then us-east-1)")
+	cmd.Flags().BoolVar(&opts.DiffAgainstInput, "diff-against-input", false, "Print the resources the transform added, removed or changed (to stderr with --stdout)")
+	cmd.Flags().BoolVar(&opts.ResourcesOnly, "resources-only", false, "Write only the transformed Resources map instead of the full template")
 
 	// Mark template-file as required
 	_ = cmd.MarkFlagRequired("template-file")
 
+	cmd.AddCommand(newDiffCmd())
+	cmd.AddCommand(newCapabilitiesCmd())
+	cmd.AddCommand(newGraphCmd())
+
 	return cmd
 }
 
@@ -90,11 +117,13 @@ func runTransform(opts *Options, stdout io.Writer, stderr io.Writer) int {
 		stderr = os.Stderr
 	}
 
+	regionName := resolveRegion(opts.Region)
+
 	// Log verbose info
 	if opts.Verbose {
 		fmt.Fprintf(stderr, "Reading template from: %s\n", opts.TemplateFile)
-		if opts.Region != "" {
-			fmt.Fprintf(stderr, "Using region: %s\n", opts.Region)
+		if regionName != "" {
+			fmt.Fprintf(stderr, "Using region: %s\n", regionName)
 		}
 	}
 
@@ -102,7 +131,7 @@ func runTransform(opts *Options, stdout io.Writer, stderr io.Writer) int {
 	input, err := os.ReadFile(opts.TemplateFile)
 	if err != nil {
 		fmt.Fprintf(stderr, "Error: failed to read template file: %v\n", err)
-		return ExitTransformError
+		return ExitIOError
 	}
 
 	if opts.Verbose {
@@ -111,8 +140,8 @@ func runTransform(opts *Options, stdout io.Writer, stderr io.Writer) int {
 
 	// Create translator with options
 	translatorOpts := translator.Options{
-		Region:    region.RegionOrDefault(opts.Region),
-		Partition: getPartitionForRegion(opts.Region),
+		Region:    region.RegionOrDefault(regionName),
+		Partition: getPartitionForRegion(regionName),
 	}
 
 	if opts.Verbose {
@@ -134,16 +163,41 @@ func runTransform(opts *Options, stdout io.Writer, stderr io.Writer) int {
 		return ExitTransformError
 	}
 
+	for _, warning := range tr.Warnings() {
+		fmt.Fprintf(stderr, "Warning: %s\n", warning)
+	}
+
 	if opts.Verbose {
 		fmt.Fprintf(stderr, "Transformation successful. Output size: %d bytes\n", len(output))
 	}
 
+	if opts.DiffAgainstInput {
+		// With --stdout the template owns stdout, so the diff goes to stderr
+		diffOut := stdout
+		if opts.Stdout {
+			diffOut = stderr
+		}
+		if err := writeInputDiff(input, output, diffOut); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return ExitTransformError
+		}
+	}
+
+	// The diff above compares whole templates, so resources are extracted after it
+	if opts.ResourcesOnly {
+		output, err = extractResources(output)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return ExitTransformError
+		}
+	}
+
 	// Write output
 	if opts.Stdout {
 		_, err = stdout.Write(output)
 		if err != nil {
 			fmt.Fprintf(stderr, "Error: failed to write to stdout: %v\n", err)
-			return ExitTransformError
+			return ExitIOError
 		}
 		// Add newline for better terminal output
 		fmt.Fprintln(stdout)
@@ -153,16 +207,88 @@ func runTransform(opts *Options, stdout io.Writer, stderr io.Writer) int {
 		if opts.Verbose {
 			fmt.Fprintf(stderr, "Writing output to: %s\n", opts.OutputTemplate)
 		}
-		err = os.WriteFile(opts.OutputTemplate, output, 0644)
+		err = writeFileAtomic(opts.OutputTemplate, output)
 		if err != nil {
 			fmt.Fprintf(stderr, "Error: failed to write output file: %v\n", err)
-			return ExitTransformError
+			return ExitIOError
 		}
 	}
 
 	return ExitSuccess
 }
 
+// extractResources returns the Resources map of a JSON template, indented the
+// same way as the full template. Resource order is preserved.
+func extractResources(template []byte) ([]byte, error) {
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(template, &sections); err != nil {
+		return nil, fmt.Errorf("failed to parse transformed template: %w", err)
+	}
+	resources, ok := sections["Resources"]
+	if !ok {
+		resources = json.RawMessage("{}")
+	}
+
+	var compact, indented bytes.Buffer
+	if err := json.Compact(&compact, resources); err != nil {
+		return nil, fmt.Errorf("failed to parse transformed resources: %w", err)
+	}
+	if err := json.Indent(&indented, compact.Bytes(), "", "  "); err != nil {
+		return nil, fmt.Errorf("failed to format transformed resources: %w", err)
+	}
+	return indented.Bytes(), nil
+}
+
+// writeFileAtomic writes data to a temporary file in the destination
+// directory and renames it into place, so an interrupted write never leaves a
+// truncated output. An existing file keeps its permission bits; new files are
+// created with mode 0644.
+func writeFileAtomic(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, mode); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpName, path)
+}
+
+// resolveRegion returns the region from the --region flag, falling back to the
+// AWS_REGION and AWS_DEFAULT_REGION environment variables. It returns an empty
+// string when none is set.
+func resolveRegion(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	for _, name := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
 // getPartitionForRegion returns the AWS partition for the given region.
 func getPartitionForRegion(regionStr string) string {
 	if regionStr == "" {
@@ -217,6 +217,78 @@ Resources:
 		}
 	})
 
+	t.Run("resources only", func(t *testing.T) {
+		var stdout bytes.Buffer
+		opts := &Options{
+			TemplateFile:  inputFile,
+			Stdout:        true,
+			ResourcesOnly: true,
+		}
+
+		if exitCode := runTransform(opts, &stdout, nil); exitCode != ExitSuccess {
+			t.Fatalf("runTransform() returned %d, want %d", exitCode, ExitSuccess)
+		}
+
+		var result map[string]interface{}
+		if err := json.Unmarshal(bytes.TrimSpace(stdout.Bytes()), &result); err != nil {
+			t.Fatalf("stdout is not valid JSON: %v", err)
+		}
+		for _, section := range []string{"AWSTemplateFormatVersion", "Resources", "Transform"} {
+			if _, ok := result[section]; ok {
+				t.Errorf("expected only the Resources map, found %s", section)
+			}
+		}
+		fn, ok := result["MyFunction"].(map[string]interface{})
+		if !ok || fn["Type"] != "AWS::Lambda::Function" {
+			t.Errorf("expected MyFunction to be an AWS::Lambda::Function, got %v", result["MyFunction"])
+		}
+		if _, ok := result["MyFunctionRole"]; !ok {
+			t.Error("expected MyFunctionRole in the Resources map")
+		}
+	})
+
+	t.Run("overwrite preserves file mode", func(t *testing.T) {
+		outputFile := filepath.Join(tmpDir, "output-private.json")
+		if err := os.WriteFile(outputFile, []byte("stale"), 0600); err != nil {
+			t.Fatalf("failed to write existing output: %v", err)
+		}
+
+		opts := &Options{
+			TemplateFile:   inputFile,
+			OutputTemplate: outputFile,
+		}
+		if exitCode := runTransform(opts, nil, nil); exitCode != ExitSuccess {
+			t.Fatalf("runTransform() returned %d, want %d", exitCode, ExitSuccess)
+		}
+
+		info, err := os.Stat(outputFile)
+		if err != nil {
+			t.Fatalf("failed to stat output file: %v", err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("expected mode 0600 to be preserved, got %o", info.Mode().Perm())
+		}
+
+		output, err := os.ReadFile(outputFile)
+		if err != nil {
+			t.Fatalf("failed to read output file: %v", err)
+		}
+		var result map[string]interface{}
+		if err := json.Unmarshal(output, &result); err != nil {
+			t.Errorf("output is not complete JSON: %v", err)
+		}
+
+		entries, err := os.ReadDir(tmpDir)
+		if err != nil {
+			t.Fatalf("failed to list output directory: %v", err)
+		}
+		for _, entry := range entries {
+			if strings.Contains(entry.Name(), ".tmp-") {
+				t.Errorf("temporary file %s was left behind", entry.Name())
+			}
+		}
+	})
+
 	t.Run("transform with region", func(t *testing.T) {
 		outputFile := filepath.Join(tmpDir, "output-region.yaml")
 		opts := &Options{
@@ -293,15 +365,55 @@ Resources:
 		}
 	})
 
-	t.Run("file not found returns 1", func(t *testing.T) {
+	t.Run("file not found returns 3", func(t *testing.T) {
 		var stdout, stderr bytes.Buffer
 		opts := &Options{
 			TemplateFile: "/nonexistent/template.yaml",
 			Stdout:       true,
 		}
 		exitCode := runTransform(opts, &stdout, &stderr)
-		if exitCode != ExitTransformError {
-			t.Errorf("exitCode = %d, want %d", exitCode, ExitTransformError)
+		if exitCode != ExitIOError {
+			t.Errorf("exitCode = %d, want %d", exitCode, ExitIOError)
+		}
+		if exitCode == ExitTransformError {
+			t.Error("IO errors must be distinguishable from transform errors")
+		}
+	})
+
+	t.Run("unwritable output returns 3", func(t *testing.T) {
+		samTemplate := `AWSTemplateFormatVersion: '2010-09-09'
+Transform: AWS::Serverless-2016-10-31
+Resources:
+  MyFunction:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: index.handler
+      Runtime: nodejs18.x
+      CodeUri: s3://bucket/key
+`
+		inputFile := filepath.Join(tmpDir, "writable-input.yaml")
+		if err := os.WriteFile(inputFile, []byte(samTemplate), 0644); err != nil {
+			t.Fatalf("failed to write input file: %v", err)
+		}
+
+		var stdout, stderr bytes.Buffer
+		opts := &Options{
+			TemplateFile:   inputFile,
+			OutputTemplate: filepath.Join(tmpDir, "missing-dir", "output.json"),
+		}
+		exitCode := runTransform(opts, &stdout, &stderr)
+		if exitCode != ExitIOError {
+			t.Errorf("exitCode = %d, want %d", exitCode, ExitIOError)
+		}
+	})
+
+	t.Run("exit codes are distinct", func(t *testing.T) {
+		seen := make(map[int]bool)
+		for _, code := range []int{ExitSuccess, ExitTransformError, ExitInvalidArgs, ExitIOError, ExitDiffFound} {
+			if seen[code] {
+				t.Errorf("exit code %d is used more than once", code)
+			}
+			seen[code] = true
 		}
 	})
 }
@@ -530,6 +642,39 @@ func TestPartitionDetection(t *testing.T) {
 	}
 }
 
+// TestResolveRegion tests the region flag and environment variable precedence.
+func TestResolveRegion(t *testing.T) {
+	tests := []struct {
+		name          string
+		flag          string
+		awsRegion     string
+		defaultRegion string
+		wantRegion    string
+		wantPartition string
+	}{
+		{name: "nothing set", wantRegion: "", wantPartition: "aws"},
+		{name: "AWS_REGION", awsRegion: "cn-north-1", wantRegion: "cn-north-1", wantPartition: "aws-cn"},
+		{name: "AWS_DEFAULT_REGION", defaultRegion: "us-gov-west-1", wantRegion: "us-gov-west-1", wantPartition: "aws-us-gov"},
+		{name: "AWS_REGION before AWS_DEFAULT_REGION", awsRegion: "eu-west-1", defaultRegion: "us-gov-west-1", wantRegion: "eu-west-1", wantPartition: "aws"},
+		{name: "flag overrides env", flag: "us-west-2", awsRegion: "cn-north-1", wantRegion: "us-west-2", wantPartition: "aws"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AWS_REGION", tt.awsRegion)
+			t.Setenv("AWS_DEFAULT_REGION", tt.defaultRegion)
+
+			got := resolveRegion(tt.flag)
+			if got != tt.wantRegion {
+				t.Errorf("resolveRegion(%q) = %q, want %q", tt.flag, got, tt.wantRegion)
+			}
+			if partition := getPartitionForRegion(got); partition != tt.wantPartition {
+				t.Errorf("partition = %q, want %q", partition, tt.wantPartition)
+			}
+		})
+	}
+}
+
 // TestMissingTemplateFile tests that missing template file produces error.
 func TestMissingTemplateFile(t *testing.T) {
 	cmd := newRootCmd()
@@ -0,0 +1,38 @@
+package intrinsics
+
+import "strings"
+
+// NewRef returns a Ref intrinsic for a logical ID or parameter name.
+func NewRef(name string) map[string]interface{} {
+	return map[string]interface{}{"Ref": name}
+}
+
+// NewGetAtt returns an Fn::GetAtt intrinsic for an attribute of a resource.
+func NewGetAtt(logicalID, attribute string) map[string]interface{} {
+	return map[string]interface{}{"Fn::GetAtt": []interface{}{logicalID, attribute}}
+}
+
+// NewSub builds an Fn::Sub of template that substitutes value for ${name}.
+//
+// A plain string value is written into the template directly; if no variables
+// remain, the result is the plain string rather than an Fn::Sub. Any other value,
+// including an existing Ref, Fn::GetAtt or Fn::Sub, is placed unchanged in the
+// variable map, so intrinsics are never re-wrapped or flattened.
+func NewSub(template, name string, value interface{}) interface{} {
+	placeholder := "${" + name + "}"
+
+	if s, ok := value.(string); ok {
+		result := strings.ReplaceAll(template, placeholder, s)
+		if !variablePattern.MatchString(result) {
+			return result
+		}
+		return map[string]interface{}{"Fn::Sub": result}
+	}
+
+	return map[string]interface{}{
+		"Fn::Sub": []interface{}{
+			template,
+			map[string]interface{}{name: value},
+		},
+	}
+}
@@ -0,0 +1,80 @@
+package intrinsics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewRef(t *testing.T) {
+	expected := map[string]interface{}{"Ref": "MyBucket"}
+	if got := NewRef("MyBucket"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestNewGetAtt(t *testing.T) {
+	expected := map[string]interface{}{"Fn::GetAtt": []interface{}{"MyQueue", "Arn"}}
+	if got := NewGetAtt("MyQueue", "Arn"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestNewSub(t *testing.T) {
+	const bucketArn = "arn:${AWS::Partition}:s3:::${Bucket}"
+
+	tests := []struct {
+		name     string
+		template string
+		value    interface{}
+		expected interface{}
+	}{
+		{
+			name:     "plain string with remaining variables",
+			template: bucketArn,
+			value:    "my-bucket",
+			expected: map[string]interface{}{"Fn::Sub": "arn:${AWS::Partition}:s3:::my-bucket"},
+		},
+		{
+			name:     "plain string without remaining variables",
+			template: "arn:aws:s3:::${Bucket}",
+			value:    "my-bucket",
+			expected: "arn:aws:s3:::my-bucket",
+		},
+		{
+			name:     "ref",
+			template: bucketArn,
+			value:    NewRef("MyBucket"),
+			expected: map[string]interface{}{
+				"Fn::Sub": []interface{}{bucketArn, map[string]interface{}{"Bucket": map[string]interface{}{"Ref": "MyBucket"}}},
+			},
+		},
+		{
+			name:     "getatt",
+			template: bucketArn,
+			value:    NewGetAtt("MyBucket", "DomainName"),
+			expected: map[string]interface{}{
+				"Fn::Sub": []interface{}{bucketArn, map[string]interface{}{
+					"Bucket": map[string]interface{}{"Fn::GetAtt": []interface{}{"MyBucket", "DomainName"}},
+				}},
+			},
+		},
+		{
+			name:     "nested sub is kept in the variable map",
+			template: bucketArn,
+			value:    map[string]interface{}{"Fn::Sub": "${AWS::StackName}-bucket"},
+			expected: map[string]interface{}{
+				"Fn::Sub": []interface{}{bucketArn, map[string]interface{}{
+					"Bucket": map[string]interface{}{"Fn::Sub": "${AWS::StackName}-bucket"},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewSub(tt.template, "Bucket", tt.value); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
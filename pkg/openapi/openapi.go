@@ -2,6 +2,7 @@
 package openapi
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -14,6 +15,16 @@ type Generator struct {
 
 	// Version is the API version used in the info section.
 	Version string
+
+	// ValidateRequestParameters attaches the params-only request validator to
+	// Swagger 2.0 routes that declare required request parameters and no
+	// validated request model, so API Gateway rejects requests missing them.
+	// SAM does not do this, so it is off by default.
+	ValidateRequestParameters bool
+
+	// DefaultAuthorizer is the API's Auth.DefaultAuthorizer. Routes whose Auth
+	// does not name an authorizer inherit it.
+	DefaultAuthorizer string
 }
 
 // New creates a new OpenAPI Generator with default settings.
@@ -73,8 +84,52 @@ type Route struct {
 
 	// PayloadFormatVersion is the payload format version (1.0 or 2.0).
 	PayloadFormatVersion string
+
+	// TimeoutInMillis is the optional integration timeout (can be an intrinsic function).
+	TimeoutInMillis interface{}
+
+	// RequestModel is the optional model the request body must match.
+	RequestModel *RequestModel
+}
+
+// RequestModel binds a route's request body to one of the API's models.
+type RequestModel struct {
+	// Model is the name of the model in the API's Models.
+	Model string
+
+	// Required indicates if the request body is required.
+	Required bool
+
+	// ValidateBody enables request body validation against the model.
+	ValidateBody bool
+
+	// ValidateParameters enables validation of required request parameters.
+	ValidateParameters bool
+
+	// Validate is set when either validation flag was specified, which
+	// attaches a request validator to the method.
+	Validate bool
 }
 
+// ValidatorName returns the name of the request validator for the model's
+// validation flags, matching the validators SAM declares.
+func (m *RequestModel) ValidatorName() string {
+	switch {
+	case m.ValidateBody && m.ValidateParameters:
+		return "body-and-params"
+	case m.ValidateBody:
+		return "body-only"
+	case m.ValidateParameters:
+		return "params-only"
+	default:
+		return "no-validation"
+	}
+}
+
+// AuthorizerNone is the RouteAuth authorizer name that disables authorization
+// on a route, overriding any API-level default.
+const AuthorizerNone = "NONE"
+
 // RouteAuth contains authorization configuration for a route.
 type RouteAuth struct {
 	// Authorizer is the name of the authorizer to use.
@@ -108,11 +163,13 @@ func (g *Generator) GenerateSwagger(routes []Route) (map[string]interface{}, err
 		"paths": paths,
 	}
 
+	integrated := make(map[string]bool)
 	for _, route := range routes {
-		if err := g.addSwaggerRoute(paths, route); err != nil {
+		if err := g.addSwaggerRoute(paths, route, integrated); err != nil {
 			return nil, fmt.Errorf("failed to add route %s %s: %w", route.Method, route.Path, err)
 		}
 	}
+	g.addRequestValidators(spec, routes)
 
 	return spec, nil
 }
@@ -129,8 +186,9 @@ func (g *Generator) GenerateOpenAPI3(routes []Route) (map[string]interface{}, er
 		"paths": paths,
 	}
 
+	integrated := make(map[string]bool)
 	for _, route := range routes {
-		if err := g.addOpenAPI3Route(paths, route); err != nil {
+		if err := g.addOpenAPI3Route(paths, route, integrated); err != nil {
 			return nil, fmt.Errorf("failed to add route %s %s: %w", route.Method, route.Path, err)
 		}
 	}
@@ -140,6 +198,9 @@ func (g *Generator) GenerateOpenAPI3(routes []Route) (map[string]interface{}, er
 
 // MergeRoutes merges routes into an existing OpenAPI specification.
 // It detects whether the spec is Swagger 2.0 or OpenAPI 3.0 and uses the appropriate format.
+// Operations the spec already integrates are left as written. Routes that
+// conflict, such as two routes on the same path and method, are skipped and
+// reported together in the returned error; the other routes are merged.
 func (g *Generator) MergeRoutes(spec map[string]interface{}, routes []Route) error {
 	if spec == nil {
 		return fmt.Errorf("spec cannot be nil")
@@ -158,23 +219,39 @@ func (g *Generator) MergeRoutes(spec map[string]interface{}, routes []Route) err
 		spec["paths"] = paths
 	}
 
+	// A conflicting route is skipped and the rest are still merged, so the
+	// result does not depend on where the conflict falls in routes
+	var errs []error
+	integrated := make(map[string]bool)
 	for _, route := range routes {
 		var err error
 		if isOpenAPI3 {
-			err = g.addOpenAPI3Route(paths, route)
+			err = g.addOpenAPI3Route(paths, route, integrated)
 		} else {
-			err = g.addSwaggerRoute(paths, route)
+			err = g.addSwaggerRoute(paths, route, integrated)
 		}
 		if err != nil {
-			return fmt.Errorf("failed to merge route %s %s: %w", route.Method, route.Path, err)
+			errs = append(errs, fmt.Errorf("failed to merge route %s %s: %w", route.Method, route.Path, err))
 		}
 	}
 
-	return nil
+	// Operations left as written do not use the routes' validators
+	var merged []Route
+	for _, route := range routes {
+		if integrated[routeKey(route)] {
+			merged = append(merged, route)
+		}
+	}
+	g.addRequestValidators(spec, merged)
+
+	return errors.Join(errs...)
 }
 
-// addSwaggerRoute adds a route to a Swagger 2.0 paths object.
-func (g *Generator) addSwaggerRoute(paths map[string]interface{}, route Route) error {
+// addSwaggerRoute adds a route to a Swagger 2.0 paths object. integrated
+// records each route seen so far by routeKey, true when this generator added
+// its integration, to detect duplicate routes.
+func (g *Generator) addSwaggerRoute(paths map[string]interface{}, route Route, integrated map[string]bool) error {
+	route.Auth = g.routeAuth(route.Auth)
 	method := strings.ToLower(route.Method)
 	if method == "" {
 		return fmt.Errorf("method is required")
@@ -183,6 +260,12 @@ func (g *Generator) addSwaggerRoute(paths map[string]interface{}, route Route) e
 		return fmt.Errorf("path is required")
 	}
 
+	key := routeKey(route)
+	if _, seen := integrated[key]; seen {
+		return fmt.Errorf("API method %q defined multiple times for path %q", method, route.Path)
+	}
+	integrated[key] = true
+
 	// Get or create path item
 	pathItem, ok := paths[route.Path].(map[string]interface{})
 	if !ok {
@@ -190,6 +273,27 @@ func (g *Generator) addSwaggerRoute(paths map[string]interface{}, route Route) e
 		paths[route.Path] = pathItem
 	}
 
+	// An operation already declared in the spec keeps its own fields, such as
+	// responses, and only gains the integration; one that is already
+	// integrated is left as written.
+	if existing, ok := pathItem[method].(map[string]interface{}); ok {
+		if _, hasIntegration := existing["x-amazon-apigateway-integration"]; hasIntegration {
+			integrated[key] = false
+			return nil
+		}
+		if err := g.addRequestModel(existing, route, false); err != nil {
+			return err
+		}
+		g.addRequestValidator(existing, route)
+		existing["x-amazon-apigateway-integration"] = g.buildSwaggerIntegration(route)
+		if route.Auth != nil && route.Auth.Authorizer != "" {
+			if _, hasSecurity := existing["security"]; !hasSecurity {
+				existing["security"] = routeSecurity(route.Auth)
+			}
+		}
+		return nil
+	}
+
 	// Build operation
 	operation := make(map[string]interface{})
 
@@ -210,8 +314,13 @@ func (g *Generator) addSwaggerRoute(paths map[string]interface{}, route Route) e
 		operation["produces"] = route.Produces
 	}
 
-	// Add parameters from path
+	// Add parameters from path and the event's RequestParameters
 	params := g.extractPathParameters(route.Path)
+	params = appendRequestParameters(params, route.RequestParameters, false)
+	if route.RequestModel != nil {
+		params = append(params, requestModelParameter(route.RequestModel))
+	}
+	g.addRequestValidator(operation, route)
 	if len(params) > 0 {
 		operation["parameters"] = params
 	}
@@ -228,7 +337,10 @@ func (g *Generator) addSwaggerRoute(paths map[string]interface{}, route Route) e
 
 	// Add security if auth is configured
 	if route.Auth != nil {
-		if route.Auth.Authorizer != "" {
+		if route.Auth.Authorizer == AuthorizerNone {
+			// An empty requirement list overrides any API-level security
+			operation["security"] = []interface{}{}
+		} else if route.Auth.Authorizer != "" {
 			security := map[string]interface{}{
 				route.Auth.Authorizer: route.Auth.Scopes,
 			}
@@ -239,7 +351,7 @@ func (g *Generator) addSwaggerRoute(paths map[string]interface{}, route Route) e
 		}
 		if route.Auth.ApiKeyRequired {
 			// Add API key security
-			if existing, ok := operation["security"].([]interface{}); ok {
+			if existing, ok := operation["security"].([]interface{}); ok && len(existing) > 0 {
 				existing = append(existing, map[string]interface{}{
 					"api_key": []interface{}{},
 				})
@@ -258,8 +370,10 @@ func (g *Generator) addSwaggerRoute(paths map[string]interface{}, route Route) e
 	return nil
 }
 
-// addOpenAPI3Route adds a route to an OpenAPI 3.0 paths object.
-func (g *Generator) addOpenAPI3Route(paths map[string]interface{}, route Route) error {
+// addOpenAPI3Route adds a route to an OpenAPI 3.0 paths object. integrated
+// is as for addSwaggerRoute.
+func (g *Generator) addOpenAPI3Route(paths map[string]interface{}, route Route, integrated map[string]bool) error {
+	route.Auth = g.routeAuth(route.Auth)
 	method := strings.ToLower(route.Method)
 	if method == "" {
 		return fmt.Errorf("method is required")
@@ -268,6 +382,12 @@ func (g *Generator) addOpenAPI3Route(paths map[string]interface{}, route Route)
 		return fmt.Errorf("path is required")
 	}
 
+	key := routeKey(route)
+	if _, seen := integrated[key]; seen {
+		return fmt.Errorf("API method %q defined multiple times for path %q", method, route.Path)
+	}
+	integrated[key] = true
+
 	// Get or create path item
 	pathItem, ok := paths[route.Path].(map[string]interface{})
 	if !ok {
@@ -275,6 +395,26 @@ func (g *Generator) addOpenAPI3Route(paths map[string]interface{}, route Route)
 		paths[route.Path] = pathItem
 	}
 
+	// An operation already declared in the spec keeps its own fields and only
+	// gains the integration; one that is already integrated is left as written.
+	if existing, ok := pathItem[method].(map[string]interface{}); ok {
+		if _, hasIntegration := existing["x-amazon-apigateway-integration"]; hasIntegration {
+			integrated[key] = false
+			return nil
+		}
+		if err := g.addRequestModel(existing, route, true); err != nil {
+			return err
+		}
+		g.addRequestValidator(existing, route)
+		existing["x-amazon-apigateway-integration"] = g.buildOpenAPI3Integration(route)
+		if route.Auth != nil && route.Auth.Authorizer != "" {
+			if _, hasSecurity := existing["security"]; !hasSecurity {
+				existing["security"] = routeSecurity(route.Auth)
+			}
+		}
+		return nil
+	}
+
 	// Build operation
 	operation := make(map[string]interface{})
 
@@ -289,11 +429,16 @@ func (g *Generator) addOpenAPI3Route(paths map[string]interface{}, route Route)
 		operation["description"] = route.Description
 	}
 
-	// Add parameters from path
+	// Add parameters from path and the event's RequestParameters
 	params := g.extractPathParametersOpenAPI3(route.Path)
+	params = appendRequestParameters(params, route.RequestParameters, true)
 	if len(params) > 0 {
 		operation["parameters"] = params
 	}
+	if err := g.addRequestModel(operation, route, true); err != nil {
+		return err
+	}
+	g.addRequestValidator(operation, route)
 
 	// Add default responses
 	operation["responses"] = map[string]interface{}{
@@ -306,22 +451,215 @@ func (g *Generator) addOpenAPI3Route(paths map[string]interface{}, route Route)
 	operation["x-amazon-apigateway-integration"] = g.buildOpenAPI3Integration(route)
 
 	// Add security if auth is configured
-	if route.Auth != nil {
-		if route.Auth.Authorizer != "" {
-			security := map[string]interface{}{
-				route.Auth.Authorizer: route.Auth.Scopes,
+	if route.Auth != nil && route.Auth.Authorizer != "" {
+		operation["security"] = routeSecurity(route.Auth)
+	}
+
+	pathItem[method] = operation
+	return nil
+}
+
+// routeKey identifies a route's operation by path and method.
+func routeKey(route Route) string {
+	return route.Path + " " + strings.ToLower(route.Method)
+}
+
+// requestModelParameter returns the Swagger 2.0 body parameter for a
+// request model.
+func requestModelParameter(model *RequestModel) map[string]interface{} {
+	modelName := strings.ToLower(model.Model)
+	return map[string]interface{}{
+		"in":       "body",
+		"name":     modelName,
+		"required": model.Required,
+		"schema":   map[string]interface{}{"$ref": "#/definitions/" + modelName},
+	}
+}
+
+// requestModelBody returns the OpenAPI 3.0 requestBody for a request model.
+func requestModelBody(model *RequestModel) map[string]interface{} {
+	return map[string]interface{}{
+		"required": model.Required,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{"$ref": "#/components/schemas/" + strings.ToLower(model.Model)},
+			},
+		},
+	}
+}
+
+// addRequestModel adds a route's RequestModel to an operation as a Swagger
+// 2.0 body parameter or an OpenAPI 3.0 requestBody. An operation that already declares a request body cannot take
+// the model and is an error.
+func (g *Generator) addRequestModel(operation map[string]interface{}, route Route, isOpenAPI3 bool) error {
+	if route.RequestModel == nil {
+		return nil
+	}
+
+	if isOpenAPI3 {
+		if _, ok := operation["requestBody"]; ok {
+			return fmt.Errorf("RequestModel cannot be applied on path %s, method %s: the operation already declares a requestBody", route.Path, strings.ToLower(route.Method))
+		}
+		operation["requestBody"] = requestModelBody(route.RequestModel)
+	} else {
+		var params []interface{}
+		switch existing := operation["parameters"].(type) {
+		case nil:
+		case []interface{}:
+			params = existing
+		case []map[string]interface{}:
+			for _, param := range existing {
+				params = append(params, param)
 			}
-			if route.Auth.Scopes == nil {
-				security[route.Auth.Authorizer] = []interface{}{}
+		default:
+			return fmt.Errorf("RequestModel cannot be applied on path %s, method %s: parameters is not a list", route.Path, strings.ToLower(route.Method))
+		}
+		for _, param := range params {
+			if p, ok := param.(map[string]interface{}); ok && p["in"] == "body" {
+				return fmt.Errorf("RequestModel cannot be applied on path %s, method %s: the operation already declares a body parameter", route.Path, strings.ToLower(route.Method))
 			}
-			operation["security"] = []interface{}{security}
 		}
+		operation["parameters"] = append(params, requestModelParameter(route.RequestModel))
 	}
-
-	pathItem[method] = operation
 	return nil
 }
 
+// addRequestValidator attaches the route's request validator to an operation
+// that does not already name one.
+func (g *Generator) addRequestValidator(operation map[string]interface{}, route Route) {
+	if validator := g.requestValidator(route); validator != "" {
+		if _, ok := operation["x-amazon-apigateway-request-validator"]; !ok {
+			operation["x-amazon-apigateway-request-validator"] = validator
+		}
+	}
+}
+
+// routeAuth returns auth with the generator's DefaultAuthorizer filled in
+// when auth does not name an authorizer. The caller's RouteAuth is not
+// modified.
+func (g *Generator) routeAuth(auth *RouteAuth) *RouteAuth {
+	if g.DefaultAuthorizer == "" || (auth != nil && auth.Authorizer != "") {
+		return auth
+	}
+	inherited := RouteAuth{}
+	if auth != nil {
+		inherited = *auth
+	}
+	inherited.Authorizer = g.DefaultAuthorizer
+	return &inherited
+}
+
+// routeSecurity builds the security requirements for a route authorizer.
+// AuthorizerNone yields an empty list, which overrides any API-level security.
+func routeSecurity(auth *RouteAuth) []interface{} {
+	if auth.Authorizer == AuthorizerNone {
+		return []interface{}{}
+	}
+	if auth.Scopes == nil {
+		return []interface{}{map[string]interface{}{auth.Authorizer: []interface{}{}}}
+	}
+	return []interface{}{map[string]interface{}{auth.Authorizer: auth.Scopes}}
+}
+
+// requestParameterLocations maps the location segment of a method request
+// parameter name to its Swagger 2.0 "in" value.
+var requestParameterLocations = map[string]string{
+	"querystring": "query",
+	"header":      "header",
+	"path":        "path",
+}
+
+// appendRequestParameters adds a parameter for each request parameter named
+// method.request.{querystring|header|path}.{name}, typed as a string in the
+// Swagger 2.0 or OpenAPI 3.0 form. Parameters already present, such as path
+// parameters taken from the route path, are kept, and path parameters are
+// always required.
+func appendRequestParameters(params []map[string]interface{}, requestParams map[string]RequestParameter, openAPI3 bool) []map[string]interface{} {
+	names := make([]string, 0, len(requestParams))
+	for name := range requestParams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, fullName := range names {
+		location, name, ok := splitRequestParameter(fullName)
+		if !ok {
+			continue
+		}
+
+		duplicate := false
+		for _, param := range params {
+			if param["in"] == location && param["name"] == name {
+				duplicate = true
+				break
+			}
+		}
+		if duplicate {
+			continue
+		}
+
+		param := map[string]interface{}{
+			"name":     name,
+			"in":       location,
+			"required": requestParams[fullName].Required || location == "path",
+		}
+		if openAPI3 {
+			param["schema"] = map[string]interface{}{"type": "string"}
+		} else {
+			param["type"] = "string"
+		}
+		params = append(params, param)
+	}
+
+	return params
+}
+
+// requestValidator returns the name of the request validator for a route,
+// or "" for none. A validated request model picks the validator;
+// otherwise required request parameters use params-only when
+// ValidateRequestParameters is set.
+func (g *Generator) requestValidator(route Route) string {
+	if route.RequestModel != nil && route.RequestModel.Validate {
+		return route.RequestModel.ValidatorName()
+	}
+	if !g.ValidateRequestParameters {
+		return ""
+	}
+	for name, param := range route.RequestParameters {
+		if _, _, ok := splitRequestParameter(name); ok && param.Required {
+			return "params-only"
+		}
+	}
+	return ""
+}
+
+// splitRequestParameter splits a method request parameter name into its
+// Swagger 2.0 location and parameter name.
+func splitRequestParameter(fullName string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(fullName, "method.request.")
+	if !ok {
+		return "", "", false
+	}
+	locationKey, name, ok := strings.Cut(rest, ".")
+	if !ok || name == "" {
+		return "", "", false
+	}
+	location, ok := requestParameterLocations[locationKey]
+	return location, name, ok
+}
+
+// cacheKeyParameters returns the sorted names of the request parameters used as cache keys.
+func cacheKeyParameters(requestParams map[string]RequestParameter) []string {
+	var keys []string
+	for name, param := range requestParams {
+		if _, _, ok := splitRequestParameter(name); ok && param.Caching {
+			keys = append(keys, name)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // buildSwaggerIntegration builds the x-amazon-apigateway-integration for Swagger 2.0.
 func (g *Generator) buildSwaggerIntegration(route Route) map[string]interface{} {
 	integration := map[string]interface{}{
@@ -333,6 +671,10 @@ func (g *Generator) buildSwaggerIntegration(route Route) map[string]interface{}
 	// Add passthroughBehavior for proxy integrations
 	integration["passthroughBehavior"] = "when_no_match"
 
+	if keys := cacheKeyParameters(route.RequestParameters); len(keys) > 0 {
+		integration["cacheKeyParameters"] = keys
+	}
+
 	return integration
 }
 
@@ -351,6 +693,10 @@ func (g *Generator) buildOpenAPI3Integration(route Route) map[string]interface{}
 	}
 	integration["payloadFormatVersion"] = payloadVersion
 
+	if route.TimeoutInMillis != nil {
+		integration["timeoutInMillis"] = route.TimeoutInMillis
+	}
+
 	return integration
 }
 
@@ -561,6 +907,66 @@ func (g *Generator) buildCorsOptionsMethod(cors *CorsConfig) map[string]interfac
 	}
 }
 
+// addRequestValidators declares the request validators used by routes.
+func (g *Generator) addRequestValidators(spec map[string]interface{}, routes []Route) {
+	for _, route := range routes {
+		name := g.requestValidator(route)
+		if name == "" {
+			continue
+		}
+
+		validators, ok := spec["x-amazon-apigateway-request-validators"].(map[string]interface{})
+		if !ok {
+			validators = make(map[string]interface{})
+			spec["x-amazon-apigateway-request-validators"] = validators
+		}
+		validateBody, validateParameters := false, true
+		if route.RequestModel != nil && route.RequestModel.Validate {
+			validateBody, validateParameters = route.RequestModel.ValidateBody, route.RequestModel.ValidateParameters
+		}
+		validators[name] = map[string]interface{}{
+			"validateRequestBody":       validateBody,
+			"validateRequestParameters": validateParameters,
+		}
+	}
+}
+
+// AddModels adds an API's models to the spec as Swagger 2.0 definitions or
+// OpenAPI 3.0 component schemas. Model names are lowercased, matching the
+// references routes make to them. API Gateway creates a model for each
+// definition when it imports the body, so no AWS::ApiGateway::Model resources
+// are needed.
+func (g *Generator) AddModels(spec map[string]interface{}, models map[string]interface{}) {
+	if spec == nil || len(models) == 0 {
+		return
+	}
+
+	var definitions map[string]interface{}
+	if _, ok := spec["openapi"]; ok {
+		components, ok := spec["components"].(map[string]interface{})
+		if !ok {
+			components = make(map[string]interface{})
+			spec["components"] = components
+		}
+		definitions, ok = components["schemas"].(map[string]interface{})
+		if !ok {
+			definitions = make(map[string]interface{})
+			components["schemas"] = definitions
+		}
+	} else {
+		var ok bool
+		definitions, ok = spec["definitions"].(map[string]interface{})
+		if !ok {
+			definitions = make(map[string]interface{})
+			spec["definitions"] = definitions
+		}
+	}
+
+	for name, schema := range models {
+		definitions[strings.ToLower(name)] = schema
+	}
+}
+
 // AddSecurityDefinitions adds security definitions to the spec.
 func (g *Generator) AddSecurityDefinitions(spec map[string]interface{}, authorizers map[string]interface{}) error {
 	if spec == nil || len(authorizers) == 0 {
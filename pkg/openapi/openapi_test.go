@@ -1,6 +1,9 @@
 package openapi
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -277,6 +280,235 @@ func TestMergeRoutesOpenAPI3(t *testing.T) {
 	}
 }
 
+func TestMergeRoutesOpenAPI3ExistingOperation(t *testing.T) {
+	g := New()
+
+	existingSpec := map[string]interface{}{
+		"openapi": "3.0.1",
+		"paths": map[string]interface{}{
+			"/items": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":   "List items",
+					"responses": map[string]interface{}{},
+				},
+			},
+		},
+	}
+
+	route := Route{
+		Path:              "/items",
+		Method:            "GET",
+		FunctionLogicalID: "ListItemsFunction",
+	}
+
+	if err := g.MergeRoutes(existingSpec, []Route{route}); err != nil {
+		t.Fatalf("MergeRoutes failed: %v", err)
+	}
+
+	getMethod := existingSpec["paths"].(map[string]interface{})["/items"].(map[string]interface{})["get"].(map[string]interface{})
+	if getMethod["summary"] != "List items" {
+		t.Errorf("expected existing summary to be preserved, got %v", getMethod["summary"])
+	}
+	if _, ok := getMethod["x-amazon-apigateway-integration"]; !ok {
+		t.Error("expected integration to be added to the existing operation")
+	}
+
+	// An operation that is already integrated is left as written
+	kept := getMethod["x-amazon-apigateway-integration"]
+	route.FunctionLogicalID = "OtherFunction"
+	if err := g.MergeRoutes(existingSpec, []Route{route}); err != nil {
+		t.Fatalf("MergeRoutes failed: %v", err)
+	}
+	if !reflect.DeepEqual(getMethod["x-amazon-apigateway-integration"], kept) {
+		t.Errorf("expected existing integration to be kept, got %v", getMethod["x-amazon-apigateway-integration"])
+	}
+}
+
+func TestMergeRoutesSwaggerExistingOperation(t *testing.T) {
+	g := New()
+
+	responses := map[string]interface{}{
+		"200": map[string]interface{}{"description": "Item list"},
+		"404": map[string]interface{}{"description": "Not found"},
+	}
+	existingSpec := map[string]interface{}{
+		"swagger": "2.0",
+		"paths": map[string]interface{}{
+			"/items": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":   "List items",
+					"responses": responses,
+				},
+			},
+		},
+	}
+
+	route := Route{
+		Path:              "/items",
+		Method:            "GET",
+		FunctionLogicalID: "ListItemsFunction",
+		Auth:              &RouteAuth{Authorizer: "MyAuthorizer"},
+	}
+
+	if err := g.MergeRoutes(existingSpec, []Route{route}); err != nil {
+		t.Fatalf("MergeRoutes failed: %v", err)
+	}
+
+	getMethod := existingSpec["paths"].(map[string]interface{})["/items"].(map[string]interface{})["get"].(map[string]interface{})
+	if getMethod["summary"] != "List items" {
+		t.Errorf("expected existing summary to be preserved, got %v", getMethod["summary"])
+	}
+	if !reflect.DeepEqual(getMethod["responses"], responses) {
+		t.Errorf("expected existing responses to be preserved, got %v", getMethod["responses"])
+	}
+	integration, ok := getMethod["x-amazon-apigateway-integration"].(map[string]interface{})
+	if !ok || integration["type"] != "aws_proxy" {
+		t.Errorf("expected aws_proxy integration to be added to the existing operation, got %v", getMethod["x-amazon-apigateway-integration"])
+	}
+	if _, ok := getMethod["security"]; !ok {
+		t.Error("expected route security to be added to the existing operation")
+	}
+
+	// An operation that is already integrated is left as written
+	kept := getMethod["x-amazon-apigateway-integration"]
+	route.FunctionLogicalID = "OtherFunction"
+	if err := g.MergeRoutes(existingSpec, []Route{route}); err != nil {
+		t.Fatalf("MergeRoutes failed: %v", err)
+	}
+	if !reflect.DeepEqual(getMethod["x-amazon-apigateway-integration"], kept) {
+		t.Errorf("expected existing integration to be kept, got %v", getMethod["x-amazon-apigateway-integration"])
+	}
+}
+
+func TestMergeRoutesRequestModel(t *testing.T) {
+	model := &RequestModel{Model: "User", Required: true, ValidateBody: true, Validate: true}
+	openAPI3Body := map[string]interface{}{
+		"required": true,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{"$ref": "#/components/schemas/user"},
+			},
+		},
+	}
+	swaggerParam := map[string]interface{}{
+		"in":       "body",
+		"name":     "user",
+		"required": true,
+		"schema":   map[string]interface{}{"$ref": "#/definitions/user"},
+	}
+	queryParam := map[string]interface{}{"in": "query", "name": "dryRun", "type": "string"}
+
+	tests := []struct {
+		name      string
+		version   string
+		operation map[string]interface{}
+		field     string
+		want      interface{}
+		wantErr   string
+	}{
+		{
+			name:    "new OpenAPI 3 operation",
+			version: "openapi",
+			field:   "requestBody",
+			want:    openAPI3Body,
+		},
+		{
+			name:      "existing OpenAPI 3 operation",
+			version:   "openapi",
+			operation: map[string]interface{}{"summary": "Create user"},
+			field:     "requestBody",
+			want:      openAPI3Body,
+		},
+		{
+			name:      "existing Swagger operation",
+			version:   "swagger",
+			operation: map[string]interface{}{"parameters": []interface{}{queryParam}},
+			field:     "parameters",
+			want:      []interface{}{queryParam, swaggerParam},
+		},
+		{
+			name:      "OpenAPI 3 operation with its own requestBody",
+			version:   "openapi",
+			operation: map[string]interface{}{"requestBody": map[string]interface{}{}},
+			wantErr:   "RequestModel cannot be applied on path /users, method post: the operation already declares a requestBody",
+		},
+		{
+			name:      "Swagger operation with its own body parameter",
+			version:   "swagger",
+			operation: map[string]interface{}{"parameters": []interface{}{map[string]interface{}{"in": "body", "name": "payload"}}},
+			wantErr:   "RequestModel cannot be applied on path /users, method post: the operation already declares a body parameter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathItem := map[string]interface{}{}
+			if tt.operation != nil {
+				pathItem["post"] = tt.operation
+			}
+			spec := map[string]interface{}{
+				tt.version: "3.0.1",
+				"paths":    map[string]interface{}{"/users": pathItem},
+			}
+
+			err := New().MergeRoutes(spec, []Route{{Path: "/users", Method: "POST", FunctionLogicalID: "CreateUser", RequestModel: model}})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MergeRoutes failed: %v", err)
+			}
+
+			post := pathItem["post"].(map[string]interface{})
+			if !reflect.DeepEqual(post[tt.field], tt.want) {
+				t.Errorf("expected %s %v, got %v", tt.field, tt.want, post[tt.field])
+			}
+			if post["x-amazon-apigateway-request-validator"] != "body-only" {
+				t.Errorf("expected body-only request validator, got %v", post["x-amazon-apigateway-request-validator"])
+			}
+			if _, ok := spec["x-amazon-apigateway-request-validators"].(map[string]interface{})["body-only"]; !ok {
+				t.Errorf("expected body-only validator to be declared, got %v", spec["x-amazon-apigateway-request-validators"])
+			}
+		})
+	}
+}
+
+func TestMergeRoutesSkipsOnlyConflictingRoute(t *testing.T) {
+	g := New()
+
+	existingSpec := map[string]interface{}{
+		"swagger": "2.0",
+		"paths":   map[string]interface{}{},
+	}
+
+	// Two routes on GET /b conflict; the first is merged
+	routes := []Route{
+		{Path: "/a", Method: "GET", FunctionLogicalID: "AFunction"},
+		{Path: "/b", Method: "GET", FunctionLogicalID: "BFunction"},
+		{Path: "/b", Method: "GET", FunctionLogicalID: "OtherFunction"},
+		{Path: "/c", Method: "GET", FunctionLogicalID: "CFunction"},
+	}
+
+	err := g.MergeRoutes(existingSpec, routes)
+	if err == nil || !strings.Contains(err.Error(), `failed to merge route GET /b: API method "get" defined multiple times for path "/b"`) {
+		t.Fatalf("expected conflict error for GET /b, got %v", err)
+	}
+
+	paths := existingSpec["paths"].(map[string]interface{})
+	for _, path := range []string{"/a", "/c"} {
+		if _, ok := paths[path]; !ok {
+			t.Errorf("expected %s to be merged despite the conflict", path)
+		}
+	}
+	integration := paths["/b"].(map[string]interface{})["get"].(map[string]interface{})["x-amazon-apigateway-integration"]
+	if !strings.Contains(fmt.Sprint(integration), "BFunction") {
+		t.Errorf("expected the first /b route to be kept, got %v", integration)
+	}
+}
+
 func TestRouteWithAuth(t *testing.T) {
 	g := New()
 
@@ -316,6 +548,51 @@ func TestRouteWithAuth(t *testing.T) {
 	}
 }
 
+func TestRouteInheritsDefaultAuthorizer(t *testing.T) {
+	g := New()
+	g.DefaultAuthorizer = "MyLambdaAuth"
+
+	explicit := &RouteAuth{Authorizer: "OtherAuth"}
+	routes := []Route{
+		{Path: "/default", Method: "GET", FunctionLogicalID: "Fn"},
+		{Path: "/apikey", Method: "GET", FunctionLogicalID: "Fn", Auth: &RouteAuth{ApiKeyRequired: true}},
+		{Path: "/explicit", Method: "GET", FunctionLogicalID: "Fn", Auth: explicit},
+		{Path: "/open", Method: "GET", FunctionLogicalID: "Fn", Auth: &RouteAuth{Authorizer: AuthorizerNone}},
+	}
+
+	spec, err := g.GenerateSwagger(routes)
+	if err != nil {
+		t.Fatalf("GenerateSwagger failed: %v", err)
+	}
+
+	paths := spec["paths"].(map[string]interface{})
+	security := func(path string) interface{} {
+		return paths[path].(map[string]interface{})["get"].(map[string]interface{})["security"]
+	}
+
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{"/default", []interface{}{map[string]interface{}{"MyLambdaAuth": []interface{}{}}}},
+		{"/apikey", []interface{}{
+			map[string]interface{}{"MyLambdaAuth": []interface{}{}},
+			map[string]interface{}{"api_key": []interface{}{}},
+		}},
+		{"/explicit", []interface{}{map[string]interface{}{"OtherAuth": []interface{}{}}}},
+		{"/open", []interface{}{}},
+	}
+	for _, tt := range tests {
+		if got := security(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s security = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	if routes[1].Auth.Authorizer != "" {
+		t.Errorf("route Auth was modified: %+v", routes[1].Auth)
+	}
+}
+
 func TestRouteWithApiKey(t *testing.T) {
 	g := New()
 
@@ -626,6 +903,131 @@ func TestExtractPathParameters(t *testing.T) {
 	}
 }
 
+func TestPathParametersRequiredAndTyped(t *testing.T) {
+	routes := []Route{
+		{
+			Path:   "/users/{userId}",
+			Method: "GET",
+			RequestParameters: map[string]RequestParameter{
+				"method.request.path.userId":        {},
+				"method.request.querystring.expand": {},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		generate func(*Generator, []Route) (map[string]interface{}, error)
+		typeOf   func(map[string]interface{}) interface{}
+	}{
+		{
+			name:     "Swagger 2.0",
+			generate: (*Generator).GenerateSwagger,
+			typeOf:   func(p map[string]interface{}) interface{} { return p["type"] },
+		},
+		{
+			name:     "OpenAPI 3.0",
+			generate: (*Generator).GenerateOpenAPI3,
+			typeOf: func(p map[string]interface{}) interface{} {
+				schema, _ := p["schema"].(map[string]interface{})
+				return schema["type"]
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := tt.generate(New(), routes)
+			if err != nil {
+				t.Fatalf("generate failed: %v", err)
+			}
+
+			operation := spec["paths"].(map[string]interface{})["/users/{userId}"].(map[string]interface{})["get"].(map[string]interface{})
+			params := operation["parameters"].([]map[string]interface{})
+			if len(params) != 2 {
+				t.Fatalf("expected path and query parameters, got %v", params)
+			}
+
+			expected := []struct {
+				name, in string
+				required bool
+			}{
+				{"userId", "path", true},
+				{"expand", "query", false},
+			}
+			for i, want := range expected {
+				param := params[i]
+				if param["name"] != want.name || param["in"] != want.in || param["required"] != want.required {
+					t.Errorf("expected %s in %s with required %v, got %v", want.name, want.in, want.required, param)
+				}
+				if tt.typeOf(param) != "string" {
+					t.Errorf("expected %s to be typed string, got %v", want.name, param)
+				}
+			}
+		})
+	}
+}
+
+func TestRequestParameterValidator(t *testing.T) {
+	routes := []Route{
+		{
+			Path:   "/items",
+			Method: "GET",
+			RequestParameters: map[string]RequestParameter{
+				"method.request.header.Authorization": {Required: true},
+			},
+		},
+		{
+			Path:   "/items",
+			Method: "POST",
+			RequestParameters: map[string]RequestParameter{
+				"method.request.header.Authorization": {Required: true},
+			},
+			RequestModel: &RequestModel{Model: "Item", ValidateBody: true, Validate: true},
+		},
+		{
+			Path:   "/items",
+			Method: "DELETE",
+			RequestParameters: map[string]RequestParameter{
+				"method.request.querystring.force": {},
+			},
+		},
+	}
+
+	validatorFor := func(spec map[string]interface{}, method string) interface{} {
+		operation := spec["paths"].(map[string]interface{})["/items"].(map[string]interface{})[method].(map[string]interface{})
+		return operation["x-amazon-apigateway-request-validator"]
+	}
+
+	spec, err := New().GenerateSwagger(routes)
+	if err != nil {
+		t.Fatalf("GenerateSwagger failed: %v", err)
+	}
+	if validator := validatorFor(spec, "get"); validator != nil {
+		t.Errorf("expected no validator by default, got %v", validator)
+	}
+
+	g := New()
+	g.ValidateRequestParameters = true
+	spec, err = g.GenerateSwagger(routes)
+	if err != nil {
+		t.Fatalf("GenerateSwagger failed: %v", err)
+	}
+
+	expected := map[string]interface{}{"get": "params-only", "post": "body-only", "delete": nil}
+	for method, want := range expected {
+		if validator := validatorFor(spec, method); validator != want {
+			t.Errorf("expected %s validator %v, got %v", method, want, validator)
+		}
+	}
+
+	validators := spec["x-amazon-apigateway-request-validators"].(map[string]interface{})
+	paramsOnly, ok := validators["params-only"].(map[string]interface{})
+	if !ok || paramsOnly["validateRequestParameters"] != true || paramsOnly["validateRequestBody"] != false {
+		t.Errorf("expected params-only validator declaration, got %v", validators)
+	}
+}
+
 func TestGenerateSwaggerWithFunctionArn(t *testing.T) {
 	g := New()
 
@@ -230,6 +230,14 @@ func validateGetAtt(value interface{}) error {
 				Message:   "Fn::GetAtt array must have exactly 2 elements",
 			}
 		}
+	case []string:
+		// The YAML parser splits the !GetAtt short form into a []string
+		if len(v) != 2 {
+			return &IntrinsicValidationError{
+				Intrinsic: "Fn::GetAtt",
+				Message:   "Fn::GetAtt array must have exactly 2 elements",
+			}
+		}
 	default:
 		return &IntrinsicValidationError{
 			Intrinsic: "Fn::GetAtt",
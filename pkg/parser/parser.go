@@ -71,6 +71,25 @@ func (p *Parser) Parse(data []byte) (*types.Template, error) {
 	return p.ParseYAML(data)
 }
 
+// ParseRaw automatically detects the format (YAML or JSON) and returns the raw
+// map structure without converting to Template.
+func (p *Parser) ParseRaw(data []byte) (map[string]interface{}, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		return p.ParseRawJSON(data)
+	}
+	rawData, err := p.ParseRawYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	return rawData, nil
+}
+
+// ParseMap converts an already-decoded template map to a Template.
+func (p *Parser) ParseMap(data map[string]interface{}) (*types.Template, error) {
+	return p.mapToTemplate(data)
+}
+
 // ParseRawYAML parses YAML and returns the raw map structure without converting to Template.
 func (p *Parser) ParseRawYAML(data []byte) (map[string]interface{}, error) {
 	if p.TrackLocations {
@@ -249,6 +268,9 @@ func (p *Parser) parseResources(data map[string]interface{}) (map[string]types.R
 			if v, ok := m["UpdatePolicy"].(map[string]interface{}); ok {
 				resource.UpdatePolicy = v
 			}
+			if v, ok := m["Connectors"].(map[string]interface{}); ok {
+				resource.Connectors = v
+			}
 
 			resources[name] = resource
 		}
@@ -689,6 +689,12 @@ func TestValidateIntrinsicStructure(t *testing.T) {
 			value:     "Resource.Attr",
 			wantErr:   false,
 		},
+		{
+			name:      "valid Fn::GetAtt short form",
+			intrinsic: "Fn::GetAtt",
+			value:     []string{"Resource", "Attr"},
+			wantErr:   false,
+		},
 		{
 			name:      "invalid Fn::GetAtt - wrong array length",
 			intrinsic: "Fn::GetAtt",
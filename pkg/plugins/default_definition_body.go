@@ -1,6 +1,8 @@
 package plugins
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/lex00/aws-sam-translator-go/pkg/openapi"
@@ -9,7 +11,11 @@ import (
 
 // DefaultDefinitionBodyPlugin handles empty DefinitionBody for API Gateway resources.
 // It collects routes from function events and generates OpenAPI specifications.
-type DefaultDefinitionBodyPlugin struct{}
+type DefaultDefinitionBodyPlugin struct {
+	// ValidateRequestParameters attaches the params-only request validator to
+	// Api routes with required request parameters.
+	ValidateRequestParameters bool
+}
 
 // NewDefaultDefinitionBodyPlugin creates a new DefaultDefinitionBodyPlugin.
 func NewDefaultDefinitionBodyPlugin() *DefaultDefinitionBodyPlugin {
@@ -26,6 +32,13 @@ func (p *DefaultDefinitionBodyPlugin) Priority() int {
 	return 500
 }
 
+// apiKey identifies the API a route belongs to. Routes are keyed by API type
+// as well as logical ID so REST and HTTP events never share an API.
+type apiKey struct {
+	logicalID string
+	isHttpApi bool
+}
+
 // apiRoutes tracks routes for each API resource.
 type apiRoutes struct {
 	isHttpApi bool
@@ -57,24 +70,15 @@ func (p *DefaultDefinitionBodyPlugin) BeforeTransform(template *types.Template)
 
 			// Get routes for this API
 			routes := []openapi.Route{}
-			if collected, ok := routesByApi[logicalID]; ok {
+			if collected, ok := routesByApi[apiKey{logicalID, isHttpApi}]; ok {
 				routes = collected.routes
 			}
 
-			// Also check for implicit API references
-			implicitApiID := "ServerlessRestApi"
-			if isHttpApi {
-				implicitApiID = "ServerlessHttpApi"
-			}
-			if logicalID == implicitApiID {
-				if collected, ok := routesByApi[""]; ok {
-					routes = append(routes, collected.routes...)
-				}
-			}
-
 			// Generate the OpenAPI spec
 			generator := openapi.New()
 			generator.Title = apiName
+			generator.DefaultAuthorizer = defaultAuthorizer(resource.Properties)
+			generator.ValidateRequestParameters = p.ValidateRequestParameters
 
 			var spec map[string]interface{}
 			var err error
@@ -88,25 +92,25 @@ func (p *DefaultDefinitionBodyPlugin) BeforeTransform(template *types.Template)
 			}
 
 			if err != nil {
-				// Fall back to minimal spec on error
+				// HttpApi route conflicts are reported; Api falls back to a minimal spec
 				if isHttpApi {
-					spec = map[string]interface{}{
-						"openapi": "3.0.1",
-						"info": map[string]interface{}{
-							"title":   apiName,
-							"version": "1.0",
-						},
-						"paths": map[string]interface{}{},
-					}
-				} else {
-					spec = map[string]interface{}{
-						"swagger": "2.0",
-						"info": map[string]interface{}{
-							"title":   apiName,
-							"version": "1.0",
-						},
-						"paths": map[string]interface{}{},
-					}
+					return fmt.Errorf("resource '%s': %w", logicalID, err)
+				}
+				spec = map[string]interface{}{
+					"swagger": "2.0",
+					"info": map[string]interface{}{
+						"title":   apiName,
+						"version": "1.0",
+					},
+					"paths": map[string]interface{}{},
+				}
+			}
+
+			if !isHttpApi {
+				models, _ := resource.Properties["Models"].(map[string]interface{})
+				generator.AddModels(spec, models)
+				if err := validateRequestModels(logicalID, routes, spec); err != nil {
+					return err
 				}
 			}
 
@@ -119,29 +123,31 @@ func (p *DefaultDefinitionBodyPlugin) BeforeTransform(template *types.Template)
 				continue
 			}
 
+			isHttpApi := resource.Type == "AWS::Serverless::HttpApi"
 			routes := []openapi.Route{}
-			if collected, ok := routesByApi[logicalID]; ok {
+			if collected, ok := routesByApi[apiKey{logicalID, isHttpApi}]; ok {
 				routes = collected.routes
 			}
 
-			// Also check for implicit API references
-			isHttpApi := resource.Type == "AWS::Serverless::HttpApi"
-			implicitApiID := "ServerlessRestApi"
-			if isHttpApi {
-				implicitApiID = "ServerlessHttpApi"
-			}
-			if logicalID == implicitApiID {
-				if collected, ok := routesByApi[""]; ok {
-					routes = append(routes, collected.routes...)
-				}
-			}
-
 			if len(routes) > 0 {
 				generator := openapi.New()
-				if err := generator.MergeRoutes(defBody, routes); err == nil {
-					resource.Properties["DefinitionBody"] = defBody
-					template.Resources[logicalID] = resource
+				generator.DefaultAuthorizer = defaultAuthorizer(resource.Properties)
+				generator.ValidateRequestParameters = p.ValidateRequestParameters
+				if !isHttpApi {
+					models, _ := resource.Properties["Models"].(map[string]interface{})
+					generator.AddModels(defBody, models)
+					if err := validateRequestModels(logicalID, routes, defBody); err != nil {
+						return err
+					}
+				}
+
+				// Operations the body already integrates are kept; only
+				// conflicting routes, such as duplicate events, are errors
+				if err := generator.MergeRoutes(defBody, routes); err != nil {
+					return fmt.Errorf("resource '%s': %w", logicalID, err)
 				}
+				resource.Properties["DefinitionBody"] = defBody
+				template.Resources[logicalID] = resource
 			}
 		}
 	}
@@ -150,8 +156,8 @@ func (p *DefaultDefinitionBodyPlugin) BeforeTransform(template *types.Template)
 }
 
 // collectRoutes extracts routes from function events.
-func (p *DefaultDefinitionBodyPlugin) collectRoutes(template *types.Template) map[string]*apiRoutes {
-	routesByApi := make(map[string]*apiRoutes)
+func (p *DefaultDefinitionBodyPlugin) collectRoutes(template *types.Template) map[apiKey]*apiRoutes {
+	routesByApi := make(map[apiKey]*apiRoutes)
 
 	for funcLogicalID, resource := range template.Resources {
 		if resource.Type != "AWS::Serverless::Function" {
@@ -230,6 +236,9 @@ func (p *DefaultDefinitionBodyPlugin) collectRoutes(template *types.Template) ma
 				} else {
 					route.PayloadFormatVersion = "2.0"
 				}
+				if timeout, ok := props["TimeoutInMillis"]; ok {
+					route.TimeoutInMillis = timeout
+				}
 			}
 
 			// Check for auth settings
@@ -251,20 +260,123 @@ func (p *DefaultDefinitionBodyPlugin) collectRoutes(template *types.Template) ma
 				route.Auth = routeAuth
 			}
 
+			if !isHttpApi {
+				route.RequestParameters = parseRequestParameters(props["RequestParameters"])
+				route.RequestModel = parseRequestModel(props["RequestModel"])
+			}
+
+			// Events without an API reference belong to the implicit API of their type
+			if apiRef == "" {
+				apiRef = "ServerlessRestApi"
+				if isHttpApi {
+					apiRef = "ServerlessHttpApi"
+				}
+			}
+
 			// Add route to the appropriate API
-			if _, exists := routesByApi[apiRef]; !exists {
-				routesByApi[apiRef] = &apiRoutes{
+			key := apiKey{apiRef, isHttpApi}
+			if _, exists := routesByApi[key]; !exists {
+				routesByApi[key] = &apiRoutes{
 					isHttpApi: isHttpApi,
 					routes:    []openapi.Route{},
 				}
 			}
-			routesByApi[apiRef].routes = append(routesByApi[apiRef].routes, route)
+			routesByApi[key].routes = append(routesByApi[key].routes, route)
 		}
 	}
 
+	// Resources and events are maps, so sort routes for a deterministic spec
+	for _, collected := range routesByApi {
+		sort.Slice(collected.routes, func(i, j int) bool {
+			a, b := collected.routes[i], collected.routes[j]
+			if a.Path != b.Path {
+				return a.Path < b.Path
+			}
+			if a.Method != b.Method {
+				return a.Method < b.Method
+			}
+			return a.FunctionLogicalID < b.FunctionLogicalID
+		})
+	}
+
 	return routesByApi
 }
 
+// parseRequestParameters converts an Api event's RequestParameters, a list of
+// parameter names or single-entry maps of name to Required/Caching settings,
+// into route request parameters.
+func parseRequestParameters(value interface{}) map[string]openapi.RequestParameter {
+	items, ok := value.([]interface{})
+	if !ok || len(items) == 0 {
+		return nil
+	}
+
+	params := make(map[string]openapi.RequestParameter, len(items))
+	for _, item := range items {
+		switch v := item.(type) {
+		case string:
+			params[v] = openapi.RequestParameter{}
+		case map[string]interface{}:
+			for name, settings := range v {
+				param := openapi.RequestParameter{}
+				if settingsMap, ok := settings.(map[string]interface{}); ok {
+					param.Required, _ = settingsMap["Required"].(bool)
+					param.Caching, _ = settingsMap["Caching"].(bool)
+				}
+				params[name] = param
+			}
+		}
+	}
+	return params
+}
+
+// parseRequestModel converts an Api event's RequestModel into a route request model.
+func parseRequestModel(value interface{}) *openapi.RequestModel {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	name, ok := m["Model"].(string)
+	if !ok || name == "" {
+		return nil
+	}
+
+	model := &openapi.RequestModel{Model: name}
+	model.Required, _ = m["Required"].(bool)
+	_, hasValidateBody := m["ValidateBody"]
+	_, hasValidateParameters := m["ValidateParameters"]
+	model.ValidateBody, _ = m["ValidateBody"].(bool)
+	model.ValidateParameters, _ = m["ValidateParameters"].(bool)
+	model.Validate = hasValidateBody || hasValidateParameters
+	return model
+}
+
+// validateRequestModels checks that every route's RequestModel names a model
+// defined in the spec, either from the API's Models or the spec itself.
+func validateRequestModels(apiLogicalID string, routes []openapi.Route, spec map[string]interface{}) error {
+	definitions, _ := spec["definitions"].(map[string]interface{})
+	if components, ok := spec["components"].(map[string]interface{}); ok {
+		definitions, _ = components["schemas"].(map[string]interface{})
+	}
+	for _, route := range routes {
+		if route.RequestModel == nil {
+			continue
+		}
+		if _, ok := definitions[strings.ToLower(route.RequestModel.Model)]; !ok {
+			return fmt.Errorf("resource '%s': RequestModel '%s' on %s %s is not defined in the Models of API '%s'",
+				route.FunctionLogicalID, route.RequestModel.Model, route.Method, route.Path, apiLogicalID)
+		}
+	}
+	return nil
+}
+
+// defaultAuthorizer returns the Auth.DefaultAuthorizer of an API's properties.
+func defaultAuthorizer(props map[string]interface{}) string {
+	auth, _ := props["Auth"].(map[string]interface{})
+	name, _ := auth["DefaultAuthorizer"].(string)
+	return name
+}
+
 // extractRef extracts a logical ID from a Ref intrinsic or returns the string value.
 func (p *DefaultDefinitionBodyPlugin) extractRef(val interface{}) string {
 	if str, ok := val.(string); ok {
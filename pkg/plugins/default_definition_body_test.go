@@ -1,6 +1,8 @@
 package plugins
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/lex00/aws-sam-translator-go/pkg/types"
@@ -321,6 +323,7 @@ func TestDefaultDefinitionBodyPlugin_HttpApiRoutesWithPayloadFormat(t *testing.T
 								"Path":                 "/items",
 								"Method":               "GET",
 								"PayloadFormatVersion": "1.0",
+								"TimeoutInMillis":      5000,
 							},
 						},
 					},
@@ -350,8 +353,279 @@ func TestDefaultDefinitionBodyPlugin_HttpApiRoutesWithPayloadFormat(t *testing.T
 		t.Fatalf("Expected paths to be set")
 	}
 
-	if _, ok := paths["/items"]; !ok {
-		t.Errorf("Expected /items path to be set")
+	itemsPath, ok := paths["/items"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected /items path to be set")
+	}
+
+	integration := itemsPath["get"].(map[string]interface{})["x-amazon-apigateway-integration"].(map[string]interface{})
+	if integration["payloadFormatVersion"] != "1.0" {
+		t.Errorf("Expected payloadFormatVersion 1.0, got %v", integration["payloadFormatVersion"])
+	}
+	if integration["timeoutInMillis"] != 5000 {
+		t.Errorf("Expected timeoutInMillis 5000, got %v", integration["timeoutInMillis"])
+	}
+}
+
+func TestDefaultDefinitionBodyPlugin_ApiRequestParametersAndAuth(t *testing.T) {
+	plugin := NewDefaultDefinitionBodyPlugin()
+
+	template := &types.Template{
+		Resources: map[string]types.Resource{
+			"ServerlessRestApi": {
+				Type:       "AWS::Serverless::Api",
+				Properties: map[string]interface{}{"StageName": "Prod"},
+			},
+			"MyFunction": {
+				Type: "AWS::Serverless::Function",
+				Properties: map[string]interface{}{
+					"Handler": "index.handler",
+					"Runtime": "python3.9",
+					"Events": map[string]interface{}{
+						"GetItem": map[string]interface{}{
+							"Type": "Api",
+							"Properties": map[string]interface{}{
+								"Path":   "/items/{id}",
+								"Method": "GET",
+								"RequestParameters": []interface{}{
+									"method.request.header.Authorization",
+									map[string]interface{}{
+										"method.request.querystring.page": map[string]interface{}{
+											"Required": true,
+											"Caching":  true,
+										},
+									},
+								},
+								"Auth": map[string]interface{}{"Authorizer": "MyLambdaAuth"},
+							},
+						},
+						"Health": map[string]interface{}{
+							"Type": "Api",
+							"Properties": map[string]interface{}{
+								"Path":   "/health",
+								"Method": "GET",
+								"Auth":   map[string]interface{}{"Authorizer": "NONE"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if err := plugin.BeforeTransform(template); err != nil {
+		t.Fatalf("BeforeTransform failed: %v", err)
+	}
+
+	paths := template.Resources["ServerlessRestApi"].Properties["DefinitionBody"].(map[string]interface{})["paths"].(map[string]interface{})
+	getItem := paths["/items/{id}"].(map[string]interface{})["get"].(map[string]interface{})
+
+	expectedParams := []map[string]interface{}{
+		{"name": "id", "in": "path", "required": true, "type": "string"},
+		{"name": "Authorization", "in": "header", "required": false, "type": "string"},
+		{"name": "page", "in": "query", "required": true, "type": "string"},
+	}
+	if !reflect.DeepEqual(getItem["parameters"], expectedParams) {
+		t.Errorf("expected parameters %v, got %v", expectedParams, getItem["parameters"])
+	}
+
+	integration := getItem["x-amazon-apigateway-integration"].(map[string]interface{})
+	expectedCacheKeys := []string{"method.request.querystring.page"}
+	if !reflect.DeepEqual(integration["cacheKeyParameters"], expectedCacheKeys) {
+		t.Errorf("expected cacheKeyParameters %v, got %v", expectedCacheKeys, integration["cacheKeyParameters"])
+	}
+
+	expectedSecurity := []interface{}{map[string]interface{}{"MyLambdaAuth": []interface{}{}}}
+	if !reflect.DeepEqual(getItem["security"], expectedSecurity) {
+		t.Errorf("expected security %v, got %v", expectedSecurity, getItem["security"])
+	}
+
+	health := paths["/health"].(map[string]interface{})["get"].(map[string]interface{})
+	if security, ok := health["security"].([]interface{}); !ok || len(security) != 0 {
+		t.Errorf("expected NONE authorizer to produce an empty security list, got %v", health["security"])
+	}
+}
+
+func TestDefaultDefinitionBodyPlugin_ApiModelsAndRequestValidator(t *testing.T) {
+	plugin := NewDefaultDefinitionBodyPlugin()
+
+	userSchema := map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"name"},
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{"type": "string"},
+		},
+	}
+	template := &types.Template{
+		Resources: map[string]types.Resource{
+			"MyApi": {
+				Type: "AWS::Serverless::Api",
+				Properties: map[string]interface{}{
+					"StageName": "Prod",
+					"Models":    map[string]interface{}{"User": userSchema},
+				},
+			},
+			"MyFunction": {
+				Type: "AWS::Serverless::Function",
+				Properties: map[string]interface{}{
+					"Handler": "index.handler",
+					"Runtime": "python3.9",
+					"Events": map[string]interface{}{
+						"CreateUser": map[string]interface{}{
+							"Type": "Api",
+							"Properties": map[string]interface{}{
+								"RestApiId": map[string]interface{}{"Ref": "MyApi"},
+								"Path":      "/users",
+								"Method":    "POST",
+								"RequestModel": map[string]interface{}{
+									"Model":        "User",
+									"Required":     true,
+									"ValidateBody": true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if err := plugin.BeforeTransform(template); err != nil {
+		t.Fatalf("BeforeTransform failed: %v", err)
+	}
+
+	defBody := template.Resources["MyApi"].Properties["DefinitionBody"].(map[string]interface{})
+	definitions, ok := defBody["definitions"].(map[string]interface{})
+	if !ok || !reflect.DeepEqual(definitions["user"], userSchema) {
+		t.Fatalf("expected definitions entry for user model, got %v", defBody["definitions"])
+	}
+
+	post := defBody["paths"].(map[string]interface{})["/users"].(map[string]interface{})["post"].(map[string]interface{})
+	expectedParams := []map[string]interface{}{{
+		"in":       "body",
+		"name":     "user",
+		"required": true,
+		"schema":   map[string]interface{}{"$ref": "#/definitions/user"},
+	}}
+	if !reflect.DeepEqual(post["parameters"], expectedParams) {
+		t.Errorf("expected body parameter %v, got %v", expectedParams, post["parameters"])
+	}
+	if post["x-amazon-apigateway-request-validator"] != "body-only" {
+		t.Errorf("expected body-only request validator, got %v", post["x-amazon-apigateway-request-validator"])
+	}
+
+	expectedValidators := map[string]interface{}{
+		"body-only": map[string]interface{}{
+			"validateRequestBody":       true,
+			"validateRequestParameters": false,
+		},
+	}
+	if !reflect.DeepEqual(defBody["x-amazon-apigateway-request-validators"], expectedValidators) {
+		t.Errorf("expected request validators %v, got %v", expectedValidators, defBody["x-amazon-apigateway-request-validators"])
+	}
+}
+
+func TestDefaultDefinitionBodyPlugin_ApiRequestModelOpenAPI3DefinitionBody(t *testing.T) {
+	plugin := NewDefaultDefinitionBodyPlugin()
+
+	userSchema := map[string]interface{}{"type": "object"}
+	template := &types.Template{
+		Resources: map[string]types.Resource{
+			"MyApi": {
+				Type: "AWS::Serverless::Api",
+				Properties: map[string]interface{}{
+					"StageName": "Prod",
+					"Models":    map[string]interface{}{"User": userSchema},
+					"DefinitionBody": map[string]interface{}{
+						"openapi": "3.0.1",
+						"paths": map[string]interface{}{
+							"/users": map[string]interface{}{
+								"post": map[string]interface{}{"summary": "Create user"},
+							},
+						},
+					},
+				},
+			},
+			"MyFunction": {
+				Type: "AWS::Serverless::Function",
+				Properties: map[string]interface{}{
+					"Handler": "index.handler",
+					"Runtime": "python3.9",
+					"Events": map[string]interface{}{
+						"CreateUser": map[string]interface{}{
+							"Type": "Api",
+							"Properties": map[string]interface{}{
+								"RestApiId": map[string]interface{}{"Ref": "MyApi"},
+								"Path":      "/users",
+								"Method":    "POST",
+								"RequestModel": map[string]interface{}{
+									"Model":        "User",
+									"ValidateBody": true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if err := plugin.BeforeTransform(template); err != nil {
+		t.Fatalf("BeforeTransform failed: %v", err)
+	}
+
+	defBody := template.Resources["MyApi"].Properties["DefinitionBody"].(map[string]interface{})
+	post := defBody["paths"].(map[string]interface{})["/users"].(map[string]interface{})["post"].(map[string]interface{})
+	expectedBody := map[string]interface{}{
+		"required": false,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{"$ref": "#/components/schemas/user"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(post["requestBody"], expectedBody) {
+		t.Errorf("expected requestBody %v, got %v", expectedBody, post["requestBody"])
+	}
+	if post["x-amazon-apigateway-request-validator"] != "body-only" {
+		t.Errorf("expected body-only request validator, got %v", post["x-amazon-apigateway-request-validator"])
+	}
+	schemas := defBody["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	if !reflect.DeepEqual(schemas["user"], userSchema) {
+		t.Errorf("expected user component schema, got %v", schemas)
+	}
+}
+
+func TestDefaultDefinitionBodyPlugin_ApiRequestModelNotDefined(t *testing.T) {
+	plugin := NewDefaultDefinitionBodyPlugin()
+
+	template := &types.Template{
+		Resources: map[string]types.Resource{
+			"ServerlessRestApi": {
+				Type:       "AWS::Serverless::Api",
+				Properties: map[string]interface{}{"StageName": "Prod"},
+			},
+			"MyFunction": {
+				Type: "AWS::Serverless::Function",
+				Properties: map[string]interface{}{
+					"Events": map[string]interface{}{
+						"CreateUser": map[string]interface{}{
+							"Type": "Api",
+							"Properties": map[string]interface{}{
+								"Path":         "/users",
+								"Method":       "POST",
+								"RequestModel": map[string]interface{}{"Model": "User"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err := plugin.BeforeTransform(template)
+	if err == nil || !strings.Contains(err.Error(), "RequestModel 'User'") {
+		t.Errorf("expected undefined RequestModel error, got %v", err)
 	}
 }
 
@@ -429,6 +703,195 @@ func TestDefaultDefinitionBodyPlugin_MergesRoutesIntoExistingSpec(t *testing.T)
 	}
 }
 
+func TestDefaultDefinitionBodyPlugin_MergeConflictReported(t *testing.T) {
+	event := func(path string) map[string]interface{} {
+		return map[string]interface{}{
+			"Type": "Api",
+			"Properties": map[string]interface{}{
+				"Path":      path,
+				"Method":    "get",
+				"RestApiId": map[string]interface{}{"Ref": "MyApi"},
+			},
+		}
+	}
+
+	// Repeat to catch map iteration order deciding which routes are merged
+	for i := 0; i < 20; i++ {
+		template := &types.Template{
+			Resources: map[string]types.Resource{
+				"MyApi": {
+					Type: "AWS::Serverless::Api",
+					Properties: map[string]interface{}{
+						"StageName": "prod",
+						"DefinitionBody": map[string]interface{}{
+							"swagger": "2.0",
+							"paths":   map[string]interface{}{},
+						},
+					},
+				},
+				"MyFunction": {
+					Type: "AWS::Serverless::Function",
+					Properties: map[string]interface{}{
+						"Handler": "index.handler",
+						"Runtime": "python3.9",
+						"Events": map[string]interface{}{
+							"A":     event("/a"),
+							"B":     event("/b"),
+							"Again": event("/b"),
+							"C":     event("/c"),
+						},
+					},
+				},
+			},
+		}
+
+		err := NewDefaultDefinitionBodyPlugin().BeforeTransform(template)
+		if err == nil || !strings.Contains(err.Error(), "resource 'MyApi'") || !strings.Contains(err.Error(), "GET /b") {
+			t.Fatalf("expected conflict error for GET /b on MyApi, got %v", err)
+		}
+
+		paths := template.Resources["MyApi"].Properties["DefinitionBody"].(map[string]interface{})["paths"].(map[string]interface{})
+		for _, path := range []string{"/a", "/c"} {
+			if _, ok := paths[path]; !ok {
+				t.Fatalf("expected %s to be merged despite the conflict", path)
+			}
+		}
+	}
+}
+
+func TestDefaultDefinitionBodyPlugin_ExplicitApiDefaultAuthorizer(t *testing.T) {
+	plugin := NewDefaultDefinitionBodyPlugin()
+
+	template := &types.Template{
+		Resources: map[string]types.Resource{
+			"MyApi": {
+				Type: "AWS::Serverless::Api",
+				Properties: map[string]interface{}{
+					"StageName": "prod",
+					"Auth": map[string]interface{}{
+						"DefaultAuthorizer": "MyLambdaAuth",
+						"Authorizers": map[string]interface{}{
+							"MyLambdaAuth": map[string]interface{}{
+								"FunctionArn": map[string]interface{}{"Fn::GetAtt": []interface{}{"AuthFunction", "Arn"}},
+							},
+						},
+					},
+					"DefinitionBody": map[string]interface{}{
+						"swagger": "2.0",
+						"info":    map[string]interface{}{"title": "My API"},
+						"paths":   map[string]interface{}{},
+					},
+				},
+			},
+			"MyFunction": {
+				Type: "AWS::Serverless::Function",
+				Properties: map[string]interface{}{
+					"Handler": "index.handler",
+					"Runtime": "python3.9",
+					"Events": map[string]interface{}{
+						"GetItems": map[string]interface{}{
+							"Type": "Api",
+							"Properties": map[string]interface{}{
+								"Path":      "/items",
+								"Method":    "get",
+								"RestApiId": map[string]interface{}{"Ref": "MyApi"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if err := plugin.BeforeTransform(template); err != nil {
+		t.Fatalf("BeforeTransform failed: %v", err)
+	}
+
+	defBody := template.Resources["MyApi"].Properties["DefinitionBody"].(map[string]interface{})
+	operation := defBody["paths"].(map[string]interface{})["/items"].(map[string]interface{})["get"].(map[string]interface{})
+
+	want := []interface{}{map[string]interface{}{"MyLambdaAuth": []interface{}{}}}
+	if !reflect.DeepEqual(operation["security"], want) {
+		t.Errorf("Expected security %v, got %v", want, operation["security"])
+	}
+}
+
+func TestDefaultDefinitionBodyPlugin_MergesRoutesIntoExistingHttpApiSpec(t *testing.T) {
+	newTemplate := func() *types.Template {
+		return &types.Template{
+			Resources: map[string]types.Resource{
+				"MyHttpApi": {
+					Type: "AWS::Serverless::HttpApi",
+					Properties: map[string]interface{}{
+						"DefinitionBody": map[string]interface{}{
+							"openapi": "3.0.1",
+							"info":    map[string]interface{}{"title": "My HTTP API"},
+							"paths": map[string]interface{}{
+								"/health": map[string]interface{}{
+									"get": map[string]interface{}{"responses": map[string]interface{}{}},
+								},
+								"/items": map[string]interface{}{
+									"get": map[string]interface{}{"summary": "List items"},
+								},
+							},
+						},
+					},
+				},
+				"MyFunction": {
+					Type: "AWS::Serverless::Function",
+					Properties: map[string]interface{}{
+						"Events": map[string]interface{}{
+							"ListItems": map[string]interface{}{
+								"Type": "HttpApi",
+								"Properties": map[string]interface{}{
+									"ApiId":  map[string]interface{}{"Ref": "MyHttpApi"},
+									"Path":   "/items",
+									"Method": "get",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	template := newTemplate()
+	if err := NewDefaultDefinitionBodyPlugin().BeforeTransform(template); err != nil {
+		t.Fatalf("BeforeTransform failed: %v", err)
+	}
+
+	defBody := template.Resources["MyHttpApi"].Properties["DefinitionBody"].(map[string]interface{})
+	paths := defBody["paths"].(map[string]interface{})
+	if _, ok := paths["/health"]; !ok {
+		t.Error("expected /health path to be preserved")
+	}
+
+	getItems := paths["/items"].(map[string]interface{})["get"].(map[string]interface{})
+	if getItems["summary"] != "List items" {
+		t.Errorf("expected existing operation fields to be preserved, got %v", getItems)
+	}
+	integration, ok := getItems["x-amazon-apigateway-integration"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected x-amazon-apigateway-integration on GET /items")
+	}
+	if integration["payloadFormatVersion"] != "2.0" {
+		t.Errorf("expected payloadFormatVersion 2.0, got %v", integration["payloadFormatVersion"])
+	}
+
+	// A route that is already integrated in the provided body is left as written
+	template = newTemplate()
+	items := template.Resources["MyHttpApi"].Properties["DefinitionBody"].(map[string]interface{})["paths"].(map[string]interface{})["/items"].(map[string]interface{})
+	userIntegration := map[string]interface{}{"type": "http_proxy"}
+	items["get"].(map[string]interface{})["x-amazon-apigateway-integration"] = userIntegration
+	if err := NewDefaultDefinitionBodyPlugin().BeforeTransform(template); err != nil {
+		t.Fatalf("BeforeTransform failed: %v", err)
+	}
+	if !reflect.DeepEqual(items["get"].(map[string]interface{})["x-amazon-apigateway-integration"], userIntegration) {
+		t.Errorf("expected the provided integration to be kept, got %v", items["get"])
+	}
+}
+
 func TestDefaultDefinitionBodyPlugin_AfterTransform(t *testing.T) {
 	plugin := NewDefaultDefinitionBodyPlugin()
 	template := &types.Template{}
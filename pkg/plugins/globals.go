@@ -63,7 +63,7 @@ func (p *GlobalsPlugin) applyFunctionGlobals(template *types.Template, globals m
 			if resource.Properties == nil {
 				resource.Properties = make(map[string]interface{})
 			}
-			mergeProperties(resource.Properties, globals)
+			mergeProperties(resource.Properties, globals, "Tags")
 		}
 	}
 }
@@ -75,7 +75,7 @@ func (p *GlobalsPlugin) applyApiGlobals(template *types.Template, globals map[st
 			if resource.Properties == nil {
 				resource.Properties = make(map[string]interface{})
 			}
-			mergeProperties(resource.Properties, globals)
+			mergeProperties(resource.Properties, globals, "Cors")
 		}
 	}
 }
@@ -87,7 +87,7 @@ func (p *GlobalsPlugin) applyHttpApiGlobals(template *types.Template, globals ma
 			if resource.Properties == nil {
 				resource.Properties = make(map[string]interface{})
 			}
-			mergeProperties(resource.Properties, globals)
+			mergeProperties(resource.Properties, globals, "CorsConfiguration")
 		}
 	}
 }
@@ -105,14 +105,47 @@ func (p *GlobalsPlugin) applySimpleTableGlobals(template *types.Template, global
 }
 
 // mergeProperties merges global properties into resource properties.
-// Resource-specific properties take precedence over global properties.
-func mergeProperties(resourceProps, globalProps map[string]interface{}) {
+// Resource-specific properties take precedence over global properties. For
+// the fieldMerged keys, a map set in both is merged field by field instead:
+// fields set on the resource win and the global fills the rest.
+func mergeProperties(resourceProps, globalProps map[string]interface{}, fieldMerged ...string) {
 	for key, value := range globalProps {
 		// Only set if not already defined in resource
-		if _, exists := resourceProps[key]; !exists {
+		existing, exists := resourceProps[key]
+		if !exists {
 			resourceProps[key] = deepCopy(value)
+			continue
 		}
+
+		for _, mergedKey := range fieldMerged {
+			if key == mergedKey {
+				resourceProps[key] = mergeFields(existing, value)
+			}
+		}
+	}
+}
+
+// mergeFields returns the resource value with any fields it does not set
+// filled from the global value, when both are maps. Otherwise, such as for a
+// Cors origin string, the resource value is returned unchanged.
+func mergeFields(resourceValue, globalValue interface{}) interface{} {
+	resourceMap, ok := resourceValue.(map[string]interface{})
+	if !ok {
+		return resourceValue
+	}
+	globalMap, ok := globalValue.(map[string]interface{})
+	if !ok {
+		return resourceValue
+	}
+
+	merged, ok := deepCopy(globalMap).(map[string]interface{})
+	if !ok {
+		return resourceValue
+	}
+	for field, value := range resourceMap {
+		merged[field] = value
 	}
+	return merged
 }
 
 // deepCopy creates a deep copy of a value.
@@ -1,6 +1,7 @@
 package plugins
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/lex00/aws-sam-translator-go/pkg/types"
@@ -115,6 +116,87 @@ func TestGlobalsPlugin_ApplyApiGlobals(t *testing.T) {
 	}
 }
 
+func TestGlobalsPlugin_MergeCors(t *testing.T) {
+	tests := []struct {
+		name         string
+		section      string
+		resourceType string
+		property     string
+		globalCors   interface{}
+		resourceCors interface{}
+		expected     interface{}
+	}{
+		{
+			name:         "Api Cors fields are merged",
+			section:      "Api",
+			resourceType: "AWS::Serverless::Api",
+			property:     "Cors",
+			globalCors:   map[string]interface{}{"AllowOrigin": "'https://example.com'", "MaxAge": "'600'"},
+			resourceCors: map[string]interface{}{"AllowMethods": "'GET,POST'", "MaxAge": "'60'"},
+			expected: map[string]interface{}{
+				"AllowOrigin":  "'https://example.com'",
+				"AllowMethods": "'GET,POST'",
+				"MaxAge":       "'60'",
+			},
+		},
+		{
+			name:         "HttpApi CorsConfiguration fields are merged",
+			section:      "HttpApi",
+			resourceType: "AWS::Serverless::HttpApi",
+			property:     "CorsConfiguration",
+			globalCors:   map[string]interface{}{"AllowOrigins": []interface{}{"https://example.com"}},
+			resourceCors: map[string]interface{}{"AllowMethods": []interface{}{"GET"}},
+			expected: map[string]interface{}{
+				"AllowOrigins": []interface{}{"https://example.com"},
+				"AllowMethods": []interface{}{"GET"},
+			},
+		},
+		{
+			name:         "Function Tags are merged",
+			section:      "Function",
+			resourceType: "AWS::Serverless::Function",
+			property:     "Tags",
+			globalCors:   map[string]interface{}{"Team": "backend", "Stage": "dev"},
+			resourceCors: map[string]interface{}{"Stage": "prod"},
+			expected:     map[string]interface{}{"Team": "backend", "Stage": "prod"},
+		},
+		{
+			name:         "resource Cors origin string wins",
+			section:      "Api",
+			resourceType: "AWS::Serverless::Api",
+			property:     "Cors",
+			globalCors:   map[string]interface{}{"AllowOrigin": "'https://example.com'"},
+			resourceCors: "'*'",
+			expected:     "'*'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := &types.Template{
+				Globals: map[string]interface{}{
+					tt.section: map[string]interface{}{tt.property: tt.globalCors},
+				},
+				Resources: map[string]types.Resource{
+					"MyApi": {
+						Type:       tt.resourceType,
+						Properties: map[string]interface{}{tt.property: tt.resourceCors},
+					},
+				},
+			}
+
+			if err := NewGlobalsPlugin().BeforeTransform(template); err != nil {
+				t.Fatalf("BeforeTransform failed: %v", err)
+			}
+
+			got := template.Resources["MyApi"].Properties[tt.property]
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %s %v, got %v", tt.property, tt.expected, got)
+			}
+		})
+	}
+}
+
 func TestGlobalsPlugin_NoGlobals(t *testing.T) {
 	plugin := NewGlobalsPlugin()
 
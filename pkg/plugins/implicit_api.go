@@ -77,11 +77,18 @@ func (p *ImplicitRestApiPlugin) BeforeTransform(template *types.Template) error
 				template.Resources = make(map[string]types.Resource)
 			}
 
+			props := map[string]interface{}{
+				"StageName": "Prod",
+			}
+			// The globals plugin has already run, so apply Api globals to the
+			// implicit API here
+			if globals, ok := template.Globals["Api"].(map[string]interface{}); ok {
+				mergeProperties(props, globals)
+			}
+
 			template.Resources["ServerlessRestApi"] = types.Resource{
-				Type: "AWS::Serverless::Api",
-				Properties: map[string]interface{}{
-					"StageName": "Prod",
-				},
+				Type:       "AWS::Serverless::Api",
+				Properties: props,
 			}
 		}
 	}
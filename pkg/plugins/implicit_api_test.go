@@ -64,6 +64,43 @@ func TestImplicitRestApiPlugin_CreatesImplicitApi(t *testing.T) {
 	}
 }
 
+func TestImplicitRestApiPlugin_AppliesGlobals(t *testing.T) {
+	plugin := NewImplicitRestApiPlugin()
+
+	template := &types.Template{
+		Globals: map[string]interface{}{
+			"Api": map[string]interface{}{
+				"TracingEnabled": true,
+				"StageName":      "Global",
+			},
+		},
+		Resources: map[string]types.Resource{
+			"MyFunction": {
+				Type: "AWS::Serverless::Function",
+				Properties: map[string]interface{}{
+					"Events": map[string]interface{}{
+						"Get": map[string]interface{}{
+							"Type": "Api",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if err := plugin.BeforeTransform(template); err != nil {
+		t.Fatalf("BeforeTransform failed: %v", err)
+	}
+
+	api := template.Resources["ServerlessRestApi"]
+	if api.Properties["TracingEnabled"] != true {
+		t.Errorf("Expected TracingEnabled from Globals, got %v", api.Properties["TracingEnabled"])
+	}
+	if api.Properties["StageName"] != "Prod" {
+		t.Errorf("Expected StageName 'Prod', got %v", api.Properties["StageName"])
+	}
+}
+
 func TestImplicitRestApiPlugin_SkipsIfRestApiIdSpecified(t *testing.T) {
 	plugin := NewImplicitRestApiPlugin()
 
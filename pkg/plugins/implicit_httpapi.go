@@ -77,11 +77,18 @@ func (p *ImplicitHttpApiPlugin) BeforeTransform(template *types.Template) error
 				template.Resources = make(map[string]types.Resource)
 			}
 
+			props := map[string]interface{}{
+				"StageName": "$default",
+			}
+			// The globals plugin has already run, so apply HttpApi globals to the
+			// implicit API here
+			if globals, ok := template.Globals["HttpApi"].(map[string]interface{}); ok {
+				mergeProperties(props, globals)
+			}
+
 			template.Resources["ServerlessHttpApi"] = types.Resource{
-				Type: "AWS::Serverless::HttpApi",
-				Properties: map[string]interface{}{
-					"StageName": "$default",
-				},
+				Type:       "AWS::Serverless::HttpApi",
+				Properties: props,
 			}
 		}
 	}
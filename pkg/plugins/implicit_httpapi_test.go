@@ -64,6 +64,43 @@ func TestImplicitHttpApiPlugin_CreatesImplicitHttpApi(t *testing.T) {
 	}
 }
 
+func TestImplicitHttpApiPlugin_AppliesGlobals(t *testing.T) {
+	plugin := NewImplicitHttpApiPlugin()
+
+	template := &types.Template{
+		Globals: map[string]interface{}{
+			"HttpApi": map[string]interface{}{
+				"FailOnWarnings": true,
+				"StageName":      "Global",
+			},
+		},
+		Resources: map[string]types.Resource{
+			"MyFunction": {
+				Type: "AWS::Serverless::Function",
+				Properties: map[string]interface{}{
+					"Events": map[string]interface{}{
+						"Get": map[string]interface{}{
+							"Type": "HttpApi",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if err := plugin.BeforeTransform(template); err != nil {
+		t.Fatalf("BeforeTransform failed: %v", err)
+	}
+
+	api := template.Resources["ServerlessHttpApi"]
+	if api.Properties["FailOnWarnings"] != true {
+		t.Errorf("Expected FailOnWarnings from Globals, got %v", api.Properties["FailOnWarnings"])
+	}
+	if api.Properties["StageName"] != "$default" {
+		t.Errorf("Expected StageName '$default', got %v", api.Properties["StageName"])
+	}
+}
+
 func TestImplicitHttpApiPlugin_SkipsIfApiIdSpecified(t *testing.T) {
 	plugin := NewImplicitHttpApiPlugin()
 
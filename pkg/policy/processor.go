@@ -5,6 +5,7 @@ import (
 	"embed"
 	"encoding/json"
 	"fmt"
+	"sync"
 )
 
 //go:embed templates.json
@@ -34,14 +35,33 @@ type Processor struct {
 	version   string
 }
 
-// New creates a new policy Processor with embedded templates.
+// embedded holds the embedded templates, parsed once per process by New.
+// Processors never modify their templates, so they share the parsed map.
+var embedded struct {
+	once      sync.Once
+	processor *Processor
+	err       error
+}
+
+// New creates a new policy Processor with embedded templates. The embedded
+// templates are parsed on the first call and shared by later processors.
 func New() (*Processor, error) {
-	data, err := templatesFS.ReadFile("templates.json")
-	if err != nil {
-		return nil, fmt.Errorf("failed to read embedded templates: %w", err)
+	embedded.once.Do(func() {
+		data, err := templatesFS.ReadFile("templates.json")
+		if err != nil {
+			embedded.err = fmt.Errorf("failed to read embedded templates: %w", err)
+			return
+		}
+		embedded.processor, embedded.err = NewFromBytes(data)
+	})
+	if embedded.err != nil {
+		return nil, embedded.err
 	}
 
-	return NewFromBytes(data)
+	return &Processor{
+		templates: embedded.processor.templates,
+		version:   embedded.processor.version,
+	}, nil
 }
 
 // NewFromBytes creates a new policy Processor from JSON bytes.
@@ -2,6 +2,8 @@ package policy
 
 import (
 	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -15,6 +17,23 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNew_SharesEmbeddedTemplates(t *testing.T) {
+	first, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	second, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if first == second {
+		t.Error("New() returned the same Processor twice")
+	}
+	if reflect.ValueOf(first.templates).Pointer() != reflect.ValueOf(second.templates).Pointer() {
+		t.Error("New() parsed the embedded templates again")
+	}
+}
+
 func TestProcessor_Version(t *testing.T) {
 	p, err := New()
 	if err != nil {
@@ -231,6 +250,63 @@ func TestProcessor_Expand_ParameterSubstitution(t *testing.T) {
 	}
 }
 
+func TestProcessor_Expand_PseudoParameterArns(t *testing.T) {
+	p, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	result, err := p.Expand("SSMParameterReadPolicy", map[string]interface{}{
+		"ParameterName": "my/parameter",
+	})
+	if err != nil {
+		t.Fatalf("Expand() error = %v", err)
+	}
+
+	statements := result["Statement"].([]interface{})
+	resource := statements[1].(map[string]interface{})["Resource"].(map[string]interface{})
+	fnSub := resource["Fn::Sub"].([]interface{})
+
+	expectedArn := "arn:${AWS::Partition}:ssm:${AWS::Region}:${AWS::AccountId}:parameter/${parameterName}"
+	if fnSub[0] != expectedArn {
+		t.Errorf("expected ARN %q, got %v", expectedArn, fnSub[0])
+	}
+	if varMap := fnSub[1].(map[string]interface{}); varMap["parameterName"] != "my/parameter" {
+		t.Errorf("expected parameterName to be substituted, got %v", varMap["parameterName"])
+	}
+}
+
+func TestProcessor_TemplateArnsUsePartition(t *testing.T) {
+	p, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// ARNs are built from pseudo parameters so templates work in every partition
+	var walk func(name string, value interface{})
+	walk = func(name string, value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for _, item := range v {
+				walk(name, item)
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(name, item)
+			}
+		case string:
+			if strings.Contains(v, "arn:") && !strings.HasPrefix(v, "arn:${AWS::Partition}:") {
+				t.Errorf("template %s builds ARN %q without ${AWS::Partition}", name, v)
+			}
+		}
+	}
+
+	for _, name := range p.TemplateNames() {
+		template, _ := p.GetTemplate(name)
+		walk(name, template.Definition)
+	}
+}
+
 func TestNewFromBytes_InvalidJSON(t *testing.T) {
 	_, err := NewFromBytes([]byte("invalid json"))
 	if err == nil {
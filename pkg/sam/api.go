@@ -8,6 +8,9 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+
+	"github.com/lex00/aws-sam-translator-go/pkg/intrinsics"
+	"github.com/lex00/aws-sam-translator-go/pkg/model/iam"
 )
 
 // Api represents an AWS::Serverless::Api resource.
@@ -221,6 +224,10 @@ func (t *ApiTransformer) Transform(logicalID string, api *Api) (map[string]inter
 		return nil, fmt.Errorf("StageName is required for AWS::Serverless::Api")
 	}
 
+	if err := t.validateCacheAndCompression(api); err != nil {
+		return nil, err
+	}
+
 	resources := make(map[string]interface{})
 
 	// Build RestApi resource
@@ -285,21 +292,20 @@ func (t *ApiTransformer) Transform(logicalID string, api *Api) (map[string]inter
 		restApiProps["ApiKeySourceType"] = api.ApiKeySourceType
 	}
 
-	// Build Tags
-	if len(api.Tags) > 0 {
-		tags := make([]map[string]interface{}, 0, len(api.Tags))
-		// Sort keys for deterministic output
-		keys := make([]string, 0, len(api.Tags))
-		for k := range api.Tags {
-			keys = append(keys, k)
-		}
-		sort.Strings(keys)
-		for _, k := range keys {
-			tags = append(tags, map[string]interface{}{
-				"Key":   k,
-				"Value": api.Tags[k],
-			})
+	// Set resource policy
+	if api.Auth != nil && api.Auth.ResourcePolicy != nil {
+		policy, err := t.buildResourcePolicy(api.Auth.ResourcePolicy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build resource policy: %w", err)
 		}
+		if policy != nil {
+			restApiProps["Policy"] = policy
+		}
+	}
+
+	// Build Tags
+	tags := apiTagList(api.Tags)
+	if len(tags) > 0 {
 		restApiProps["Tags"] = tags
 	}
 
@@ -370,12 +376,9 @@ func (t *ApiTransformer) Transform(logicalID string, api *Api) (map[string]inter
 	if len(api.MethodSettings) > 0 {
 		methodSettings := make([]map[string]interface{}, 0, len(api.MethodSettings))
 		for _, ms := range api.MethodSettings {
-			setting := make(map[string]interface{})
-			if ms.HttpMethod != "" {
-				setting["HttpMethod"] = ms.HttpMethod
-			}
-			if ms.ResourcePath != "" {
-				setting["ResourcePath"] = ms.ResourcePath
+			setting := map[string]interface{}{
+				"HttpMethod":   methodSettingHttpMethod(ms.HttpMethod),
+				"ResourcePath": methodSettingResourcePath(ms.ResourcePath),
 			}
 			if ms.CachingEnabled {
 				setting["CachingEnabled"] = true
@@ -421,6 +424,11 @@ func (t *ApiTransformer) Transform(logicalID string, api *Api) (map[string]inter
 		stageProps["CanarySetting"] = canarySetting
 	}
 
+	// Stages accept tags as well; apply the same list as the RestApi
+	if len(tags) > 0 {
+		stageProps["Tags"] = tags
+	}
+
 	resources[stageLogicalID] = map[string]interface{}{
 		"Type":       "AWS::ApiGateway::Stage",
 		"Properties": stageProps,
@@ -433,9 +441,375 @@ func (t *ApiTransformer) Transform(logicalID string, api *Api) (map[string]inter
 		}
 	}
 
+	// Build custom domain resources
+	if api.Domain != nil {
+		for k, v := range t.buildDomainResources(logicalID, api.Domain, stageLogicalID) {
+			resources[k] = v
+		}
+	}
+
 	return resources, nil
 }
 
+// buildDomainResources builds the custom domain name, base path mappings and
+// optional Route 53 records for a RestApi.
+func (t *ApiTransformer) buildDomainResources(apiLogicalID string, domain *DomainConfig, stageLogicalID string) map[string]interface{} {
+	resources := make(map[string]interface{})
+
+	if domain.DomainName == nil {
+		return resources
+	}
+
+	endpointType := "REGIONAL"
+	if et, ok := domain.EndpointConfiguration.(string); ok && et != "" {
+		endpointType = strings.ToUpper(et)
+	}
+
+	// Create Domain Name resource
+	domainLogicalID := apiLogicalID + "DomainName"
+	domainProps := map[string]interface{}{
+		"DomainName": domain.DomainName,
+		"EndpointConfiguration": map[string]interface{}{
+			"Types": []interface{}{endpointType},
+		},
+	}
+
+	// Edge-optimized domains use a certificate in us-east-1 via CertificateArn
+	if domain.CertificateArn != nil {
+		if endpointType == "EDGE" {
+			domainProps["CertificateArn"] = domain.CertificateArn
+		} else {
+			domainProps["RegionalCertificateArn"] = domain.CertificateArn
+		}
+	}
+
+	resources[domainLogicalID] = map[string]interface{}{
+		"Type":       "AWS::ApiGateway::DomainName",
+		"Properties": domainProps,
+	}
+
+	// Create Base Path Mappings
+	var basePaths []string
+	switch bp := domain.BasePath.(type) {
+	case string:
+		basePaths = append(basePaths, bp)
+	case []interface{}:
+		for _, path := range bp {
+			if pathStr, ok := path.(string); ok {
+				basePaths = append(basePaths, pathStr)
+			}
+		}
+	}
+	if len(basePaths) == 0 {
+		basePaths = []string{"/"}
+	}
+
+	for _, path := range basePaths {
+		mappingProps := map[string]interface{}{
+			"DomainName": map[string]interface{}{"Ref": domainLogicalID},
+			"RestApiId":  map[string]interface{}{"Ref": apiLogicalID},
+			"Stage":      map[string]interface{}{"Ref": stageLogicalID},
+		}
+
+		mappingLogicalID := apiLogicalID + "BasePathMapping"
+		if trimmed := strings.Trim(path, "/"); trimmed != "" {
+			mappingProps["BasePath"] = trimmed
+			mappingLogicalID = apiLogicalID + alphanumericOnly(trimmed) + "BasePathMapping"
+		}
+
+		resources[mappingLogicalID] = map[string]interface{}{
+			"Type":       "AWS::ApiGateway::BasePathMapping",
+			"Properties": mappingProps,
+		}
+	}
+
+	// Route 53 records if configured
+	if route53Config, ok := domain.Route53.(map[string]interface{}); ok {
+		for k, v := range t.buildRoute53Resources(apiLogicalID, domain.DomainName, endpointType, route53Config) {
+			resources[k] = v
+		}
+	}
+
+	return resources
+}
+
+// methodSettingHttpMethod returns the stage MethodSettings HttpMethod for a
+// SAM setting. An omitted method or ANY applies to all methods.
+func methodSettingHttpMethod(method string) string {
+	method = strings.ToUpper(method)
+	if method == "" || method == "ANY" {
+		return "*"
+	}
+	return method
+}
+
+// methodSettingResourcePath returns the stage MethodSettings ResourcePath for
+// a SAM setting. An omitted path applies to all resources. API Gateway expects
+// the slashes inside a specific path encoded as ~1, so /users/{id} becomes
+// /~1users~1{id}; the root resource / and paths that are already encoded are kept.
+func methodSettingResourcePath(path string) string {
+	if path == "" || path == "/*" {
+		return "/*"
+	}
+	if path == "/" || strings.Contains(path, "~1") || !strings.HasPrefix(path, "/") {
+		return path
+	}
+	return "/" + strings.ReplaceAll(path, "/", "~1")
+}
+
+// apiTagList converts a tag map into the sorted [{Key, Value}] list used by
+// AWS::ApiGateway resources.
+func apiTagList(tagMap map[string]interface{}) []map[string]interface{} {
+	if len(tagMap) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(tagMap))
+	for k := range tagMap {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	tags := make([]map[string]interface{}, 0, len(keys))
+	for _, k := range keys {
+		tags = append(tags, map[string]interface{}{
+			"Key":   k,
+			"Value": tagMap[k],
+		})
+	}
+	return tags
+}
+
+// alphanumericOnly strips every character that is not valid in a logical ID.
+func alphanumericOnly(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// buildRoute53Resources builds Route 53 alias records pointing at the custom domain.
+func (t *ApiTransformer) buildRoute53Resources(apiLogicalID string, domainName interface{}, endpointType string, route53Config map[string]interface{}) map[string]interface{} {
+	resources := make(map[string]interface{})
+
+	domainLogicalID := apiLogicalID + "DomainName"
+	recordLogicalID := apiLogicalID + "RecordSet"
+
+	// Edge-optimized domains are served through CloudFront
+	dnsNameAttr, hostedZoneAttr := "RegionalDomainName", "RegionalHostedZoneId"
+	if endpointType == "EDGE" {
+		dnsNameAttr, hostedZoneAttr = "DistributionDomainName", "DistributionHostedZoneId"
+	}
+
+	aliasTarget := map[string]interface{}{
+		"DNSName": map[string]interface{}{
+			"Fn::GetAtt": []interface{}{domainLogicalID, dnsNameAttr},
+		},
+		"HostedZoneId": map[string]interface{}{
+			"Fn::GetAtt": []interface{}{domainLogicalID, hostedZoneAttr},
+		},
+	}
+	if evaluateTargetHealth, ok := route53Config["EvaluateTargetHealth"]; ok {
+		aliasTarget["EvaluateTargetHealth"] = evaluateTargetHealth
+	}
+
+	recordProps := map[string]interface{}{
+		"Name":        domainName,
+		"Type":        "A",
+		"AliasTarget": aliasTarget,
+	}
+
+	// Hosted zone ID or name
+	if hostedZoneId, ok := route53Config["HostedZoneId"]; ok {
+		recordProps["HostedZoneId"] = hostedZoneId
+	} else if hostedZoneName, ok := route53Config["HostedZoneName"]; ok {
+		recordProps["HostedZoneName"] = hostedZoneName
+	}
+
+	resources[recordLogicalID] = map[string]interface{}{
+		"Type":       "AWS::Route53::RecordSet",
+		"Properties": recordProps,
+	}
+
+	// Create AAAA record for IPv6 if specified
+	if ipv6, ok := route53Config["IpV6"].(bool); ok && ipv6 {
+		ipv6RecordProps := make(map[string]interface{})
+		for k, v := range recordProps {
+			ipv6RecordProps[k] = v
+		}
+		ipv6RecordProps["Type"] = "AAAA"
+
+		resources[recordLogicalID+"V6"] = map[string]interface{}{
+			"Type":       "AWS::Route53::RecordSet",
+			"Properties": ipv6RecordProps,
+		}
+	}
+
+	return resources
+}
+
+// executeApiResource is the resource used in Api resource policy statements.
+// It covers every stage, method and path of the API.
+const executeApiResource = "execute-api:/*"
+
+// buildResourcePolicy expands Auth.ResourcePolicy into the RestApi Policy document.
+// CustomStatements are passed through; the account, IP range and VPC lists are
+// expanded into allow/deny statements on execute-api:Invoke.
+func (t *ApiTransformer) buildResourcePolicy(resourcePolicy interface{}) (map[string]interface{}, error) {
+	rp, ok := resourcePolicy.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("ResourcePolicy must be an object")
+	}
+
+	var statements []interface{}
+
+	// Custom statements are used verbatim
+	switch custom := rp["CustomStatements"].(type) {
+	case []interface{}:
+		statements = append(statements, custom...)
+	case map[string]interface{}:
+		statements = append(statements, custom)
+	}
+
+	invoke := func(effect string) *iam.Statement {
+		return iam.NewStatement(effect).
+			WithAction("execute-api:Invoke").
+			WithResource([]interface{}{executeApiResource})
+	}
+
+	// IP and VPC whitelists become conditions on the allow statement; every
+	// list also denies requests from outside it, or from within a blacklist
+	allowConditions := make(map[string]interface{})
+	var denies []*iam.Statement
+	if ips := toInterfaceList(rp["IpRangeWhitelist"]); len(ips) > 0 {
+		allowConditions["IpAddress"] = map[string]interface{}{"aws:SourceIp": ips}
+		denies = append(denies, invoke(iam.EffectDeny).WithPrincipal("*").
+			WithCondition("NotIpAddress", map[string]interface{}{"aws:SourceIp": ips}))
+	}
+	if ips := toInterfaceList(rp["IpRangeBlacklist"]); len(ips) > 0 {
+		denies = append(denies, invoke(iam.EffectDeny).WithPrincipal("*").
+			WithCondition("IpAddress", map[string]interface{}{"aws:SourceIp": ips}))
+	}
+	if condition := sourceVpcCondition(rp["SourceVpcWhitelist"]); condition != nil {
+		allowConditions["StringEquals"] = condition
+		denies = append(denies, invoke(iam.EffectDeny).WithPrincipal("*").
+			WithCondition("StringNotEquals", condition))
+	}
+	if condition := sourceVpcCondition(rp["SourceVpcBlacklist"]); condition != nil {
+		denies = append(denies, invoke(iam.EffectDeny).WithPrincipal("*").
+			WithCondition("StringEquals", condition))
+	}
+
+	// An account whitelist restricts the allow by principal; otherwise the IP
+	// and VPC lists allow every principal
+	var allow *iam.Statement
+	if accounts := toInterfaceList(rp["AwsAccountWhitelist"]); len(accounts) > 0 {
+		allow = invoke(iam.EffectAllow).WithAWSPrincipal(accounts)
+	} else if len(denies) > 0 {
+		allow = invoke(iam.EffectAllow).WithPrincipal("*")
+	}
+	if allow != nil {
+		if len(allowConditions) > 0 {
+			allow.WithConditions(allowConditions)
+		}
+		statements = append(statements, allow.ToMap())
+	}
+	if accounts := toInterfaceList(rp["AwsAccountBlacklist"]); len(accounts) > 0 {
+		statements = append(statements, invoke(iam.EffectDeny).WithAWSPrincipal(accounts).ToMap())
+	}
+	for _, deny := range denies {
+		statements = append(statements, deny.ToMap())
+	}
+
+	if len(statements) == 0 {
+		return nil, nil
+	}
+
+	return map[string]interface{}{
+		"Version":   iam.PolicyDocumentVersion,
+		"Statement": statements,
+	}, nil
+}
+
+// sourceVpcCondition builds the condition body for a source VPC list, keying
+// VPC endpoint IDs (vpce-) on aws:SourceVpce and everything else, including
+// intrinsics, on aws:SourceVpc. Returns nil for an empty list.
+func sourceVpcCondition(list interface{}) map[string]interface{} {
+	var vpcs, vpces []interface{}
+	for _, v := range toInterfaceList(list) {
+		if s, ok := v.(string); ok && strings.HasPrefix(s, "vpce-") {
+			vpces = append(vpces, v)
+		} else {
+			vpcs = append(vpcs, v)
+		}
+	}
+
+	if len(vpcs) == 0 && len(vpces) == 0 {
+		return nil
+	}
+
+	condition := make(map[string]interface{})
+	if len(vpcs) > 0 {
+		condition["aws:SourceVpc"] = vpcs
+	}
+	if len(vpces) > 0 {
+		condition["aws:SourceVpce"] = vpces
+	}
+	return condition
+}
+
+// toInterfaceList normalizes a single value or list into a list.
+func toInterfaceList(v interface{}) []interface{} {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return val
+	case []string:
+		list := make([]interface{}, len(val))
+		for i, s := range val {
+			list[i] = s
+		}
+		return list
+	default:
+		return []interface{}{val}
+	}
+}
+
+// validCacheClusterSizes are the cache cluster sizes (in GB) supported by API Gateway.
+var validCacheClusterSizes = []string{"0.5", "1.6", "6.1", "13.5", "28.4", "58.2", "118", "237"}
+
+// maxMinimumCompressionSize is the largest MinimumCompressionSize API Gateway accepts (10 MiB).
+const maxMinimumCompressionSize = 10485760
+
+// validateCacheAndCompression checks the cache cluster and compression settings.
+func (t *ApiTransformer) validateCacheAndCompression(api *Api) error {
+	if api.CacheClusterSize != "" {
+		if !api.CacheClusterEnabled {
+			return fmt.Errorf("CacheClusterSize requires CacheClusterEnabled to be true")
+		}
+		valid := false
+		for _, size := range validCacheClusterSizes {
+			if api.CacheClusterSize == size {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("invalid CacheClusterSize '%s': must be one of [%s]",
+				api.CacheClusterSize, strings.Join(validCacheClusterSizes, ", "))
+		}
+	}
+
+	if api.MinimumCompressionSize < 0 || api.MinimumCompressionSize > maxMinimumCompressionSize {
+		return fmt.Errorf("MinimumCompressionSize must be between 0 and %d, got %d",
+			maxMinimumCompressionSize, api.MinimumCompressionSize)
+	}
+
+	return nil
+}
+
 // buildS3Location builds an S3 location from DefinitionUri.
 func (t *ApiTransformer) buildS3Location(definitionUri interface{}) (map[string]interface{}, error) {
 	switch uri := definitionUri.(type) {
@@ -477,6 +851,8 @@ func (t *ApiTransformer) parseS3Uri(uri string) (map[string]interface{}, error)
 }
 
 // generateDeploymentLogicalID generates a stable deployment logical ID based on the API definition.
+// Routes from function Api events are merged into DefinitionBody before the
+// transform, so adding or changing a route also changes the ID and redeploys.
 func (t *ApiTransformer) generateDeploymentLogicalID(logicalID string, api *Api) string {
 	// Create a hash of the definition for stable deployment IDs
 	h := sha256.New()
@@ -567,13 +943,8 @@ func (t *ApiTransformer) processAuthorizers(apiLogicalID string, auth *ApiAuth,
 
 // buildLambdaAuthorizerUri builds the Lambda authorizer URI.
 func (t *ApiTransformer) buildLambdaAuthorizerUri(functionArn interface{}) interface{} {
-	// Return Fn::Sub for the authorizer URI
-	return map[string]interface{}{
-		"Fn::Sub": []interface{}{
-			"arn:aws:apigateway:${AWS::Region}:lambda:path/2015-03-31/functions/${FunctionArn}/invocations",
-			map[string]interface{}{
-				"FunctionArn": functionArn,
-			},
-		},
-	}
+	return intrinsics.NewSub(
+		"arn:aws:apigateway:${AWS::Region}:lambda:path/2015-03-31/functions/${FunctionArn}/invocations",
+		"FunctionArn", functionArn,
+	)
 }
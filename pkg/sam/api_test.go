@@ -3,6 +3,7 @@ package sam
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -394,6 +395,73 @@ func TestApiTransformer_Transform_WithMethodSettings(t *testing.T) {
 	}
 }
 
+func TestApiTransformer_Transform_MethodSettingsWildcards(t *testing.T) {
+	api := &Api{
+		StageName: "Prod",
+		MethodSettings: []MethodSettingConfig{
+			{
+				ThrottlingBurstLimit: 100,
+				ThrottlingRateLimit:  50,
+				MetricsEnabled:       true,
+			},
+			{
+				HttpMethod:        "get",
+				ResourcePath:      "/users/{id}",
+				CachingEnabled:    true,
+				CacheTtlInSeconds: 300,
+				LoggingLevel:      "ERROR",
+			},
+			{
+				HttpMethod:   "POST",
+				ResourcePath: "/~1orders",
+			},
+			{
+				HttpMethod:     "GET",
+				ResourcePath:   "/",
+				MetricsEnabled: true,
+			},
+		},
+		DefinitionBody: map[string]interface{}{
+			"swagger": "2.0",
+		},
+	}
+
+	resources, err := NewApiTransformer().Transform("MyApi", api)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	stageProps := resources["MyApiStage"].(map[string]interface{})["Properties"].(map[string]interface{})
+	expected := []map[string]interface{}{
+		{
+			"HttpMethod":           "*",
+			"ResourcePath":         "/*",
+			"ThrottlingBurstLimit": 100,
+			"ThrottlingRateLimit":  float64(50),
+			"MetricsEnabled":       true,
+		},
+		{
+			"HttpMethod":        "GET",
+			"ResourcePath":      "/~1users~1{id}",
+			"CachingEnabled":    true,
+			"CacheTtlInSeconds": 300,
+			"LoggingLevel":      "ERROR",
+		},
+		{
+			"HttpMethod":   "POST",
+			"ResourcePath": "/~1orders",
+		},
+		{
+			"HttpMethod":     "GET",
+			"ResourcePath":   "/",
+			"MetricsEnabled": true,
+		},
+	}
+	if !reflect.DeepEqual(stageProps["MethodSettings"], expected) {
+		t.Errorf("expected MethodSettings %v, got %v", expected, stageProps["MethodSettings"])
+	}
+}
+
 func TestApiTransformer_Transform_WithTags(t *testing.T) {
 	transformer := NewApiTransformer()
 
@@ -423,6 +491,21 @@ func TestApiTransformer_Transform_WithTags(t *testing.T) {
 	if len(tags) == 0 {
 		t.Error("Expected at least 1 tag")
 	}
+
+	stage := resources["MyApiStage"].(map[string]interface{})
+	stageProps := stage["Properties"].(map[string]interface{})
+
+	stageTags, ok := stageProps["Tags"].([]map[string]interface{})
+	if !ok {
+		t.Fatalf("expected Tags list on stage, got %v", stageProps["Tags"])
+	}
+	expected := []map[string]interface{}{
+		{"Key": "Environment", "Value": "Production"},
+		{"Key": "Team", "Value": "Backend"},
+	}
+	if !reflect.DeepEqual(stageTags, expected) {
+		t.Errorf("expected stage tags %v, got %v", expected, stageTags)
+	}
 }
 
 func TestApiTransformer_Transform_DeploymentIdStability(t *testing.T) {
@@ -544,6 +627,292 @@ func TestApiTransformer_Transform_WithAuth(t *testing.T) {
 	}
 }
 
+func TestApiTransformer_Transform_ResourcePolicyIpRangeWhitelist(t *testing.T) {
+	transformer := NewApiTransformer()
+
+	api := &Api{
+		StageName: "Prod",
+		Auth: &ApiAuth{
+			ResourcePolicy: map[string]interface{}{
+				"IpRangeWhitelist": []interface{}{"10.0.0.0/16"},
+			},
+		},
+		DefinitionBody: map[string]interface{}{
+			"swagger": "2.0",
+		},
+	}
+
+	resources, err := transformer.Transform("MyApi", api)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	props := resources["MyApi"].(map[string]interface{})["Properties"].(map[string]interface{})
+	policy, ok := props["Policy"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected Policy on RestApi, got %v", props["Policy"])
+	}
+
+	statements := policy["Statement"].([]interface{})
+	if len(statements) != 2 {
+		t.Fatalf("Expected 2 statements, got %d: %v", len(statements), statements)
+	}
+
+	allow := statements[0].(map[string]interface{})
+	if allow["Effect"] != "Allow" || allow["Principal"] != "*" {
+		t.Errorf("Expected Allow for all principals, got %v", allow)
+	}
+	expectedAllowCondition := map[string]interface{}{
+		"IpAddress": map[string]interface{}{
+			"aws:SourceIp": []interface{}{"10.0.0.0/16"},
+		},
+	}
+	if !reflect.DeepEqual(allow["Condition"], expectedAllowCondition) {
+		t.Errorf("Expected allow condition %v, got %v", expectedAllowCondition, allow["Condition"])
+	}
+
+	deny := statements[1].(map[string]interface{})
+	if deny["Effect"] != "Deny" {
+		t.Errorf("Expected Deny statement, got %v", deny["Effect"])
+	}
+	expectedCondition := map[string]interface{}{
+		"NotIpAddress": map[string]interface{}{
+			"aws:SourceIp": []interface{}{"10.0.0.0/16"},
+		},
+	}
+	if !reflect.DeepEqual(deny["Condition"], expectedCondition) {
+		t.Errorf("Expected condition %v, got %v", expectedCondition, deny["Condition"])
+	}
+}
+
+func TestApiTransformer_Transform_ResourcePolicyAccountAndIpRangeWhitelist(t *testing.T) {
+	transformer := NewApiTransformer()
+
+	api := &Api{
+		StageName: "Prod",
+		Auth: &ApiAuth{
+			ResourcePolicy: map[string]interface{}{
+				"AwsAccountWhitelist": []interface{}{"123456789012"},
+				"IpRangeWhitelist":    []interface{}{"10.0.0.0/16"},
+			},
+		},
+		DefinitionBody: map[string]interface{}{
+			"swagger": "2.0",
+		},
+	}
+
+	resources, err := transformer.Transform("MyApi", api)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	props := resources["MyApi"].(map[string]interface{})["Properties"].(map[string]interface{})
+	statements := props["Policy"].(map[string]interface{})["Statement"].([]interface{})
+	if len(statements) != 2 {
+		t.Fatalf("Expected 2 statements, got %d: %v", len(statements), statements)
+	}
+
+	// The account whitelist must not be widened by an allow for every principal
+	for _, stmt := range statements {
+		stmt := stmt.(map[string]interface{})
+		if stmt["Effect"] == "Allow" && stmt["Principal"] == "*" {
+			t.Errorf("Expected no Allow for all principals, got %v", stmt)
+		}
+	}
+
+	allow := statements[0].(map[string]interface{})
+	expectedPrincipal := map[string]interface{}{"AWS": []interface{}{"123456789012"}}
+	if allow["Effect"] != "Allow" || !reflect.DeepEqual(allow["Principal"], expectedPrincipal) {
+		t.Errorf("Expected Allow for the whitelisted account, got %v", allow)
+	}
+	expectedCondition := map[string]interface{}{
+		"IpAddress": map[string]interface{}{
+			"aws:SourceIp": []interface{}{"10.0.0.0/16"},
+		},
+	}
+	if !reflect.DeepEqual(allow["Condition"], expectedCondition) {
+		t.Errorf("Expected allow condition %v, got %v", expectedCondition, allow["Condition"])
+	}
+
+	deny := statements[1].(map[string]interface{})
+	if deny["Effect"] != "Deny" || deny["Principal"] != "*" {
+		t.Errorf("Expected Deny for requests outside the IP range, got %v", deny)
+	}
+}
+
+func TestApiTransformer_Transform_ResourcePolicyCustomStatements(t *testing.T) {
+	transformer := NewApiTransformer()
+
+	custom := map[string]interface{}{
+		"Effect":    "Allow",
+		"Principal": map[string]interface{}{"AWS": "arn:aws:iam::123456789012:root"},
+		"Action":    "execute-api:Invoke",
+		"Resource":  "execute-api:/Prod/GET/items",
+	}
+
+	api := &Api{
+		StageName: "Prod",
+		Auth: &ApiAuth{
+			ResourcePolicy: map[string]interface{}{
+				"CustomStatements": []interface{}{custom},
+			},
+		},
+		DefinitionBody: map[string]interface{}{
+			"swagger": "2.0",
+		},
+	}
+
+	resources, err := transformer.Transform("MyApi", api)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	props := resources["MyApi"].(map[string]interface{})["Properties"].(map[string]interface{})
+	policy := props["Policy"].(map[string]interface{})
+	if policy["Version"] != "2012-10-17" {
+		t.Errorf("Expected Version 2012-10-17, got %v", policy["Version"])
+	}
+
+	statements := policy["Statement"].([]interface{})
+	if len(statements) != 1 || !reflect.DeepEqual(statements[0], custom) {
+		t.Errorf("Expected custom statement to pass through, got %v", statements)
+	}
+}
+
+func TestApiTransformer_Transform_DomainRegional(t *testing.T) {
+	transformer := NewApiTransformer()
+
+	api := &Api{
+		StageName: "Prod",
+		Domain: &DomainConfig{
+			DomainName:     "api.example.com",
+			CertificateArn: "arn:aws:acm:us-east-1:123456789012:certificate/abc",
+			BasePath:       []interface{}{"/v1", "/v2"},
+		},
+	}
+
+	resources, err := transformer.Transform("MyApi", api)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	domain, ok := resources["MyApiDomainName"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected MyApiDomainName resource")
+	}
+	if domain["Type"] != "AWS::ApiGateway::DomainName" {
+		t.Errorf("Expected AWS::ApiGateway::DomainName, got %v", domain["Type"])
+	}
+	props := domain["Properties"].(map[string]interface{})
+	if props["RegionalCertificateArn"] != "arn:aws:acm:us-east-1:123456789012:certificate/abc" {
+		t.Errorf("Expected RegionalCertificateArn, got %v", props)
+	}
+	if _, ok := props["CertificateArn"]; ok {
+		t.Error("Regional domain should not set CertificateArn")
+	}
+	expectedEndpoint := map[string]interface{}{"Types": []interface{}{"REGIONAL"}}
+	if !reflect.DeepEqual(props["EndpointConfiguration"], expectedEndpoint) {
+		t.Errorf("Expected EndpointConfiguration %v, got %v", expectedEndpoint, props["EndpointConfiguration"])
+	}
+
+	for id, basePath := range map[string]string{"MyApiv1BasePathMapping": "v1", "MyApiv2BasePathMapping": "v2"} {
+		mapping, ok := resources[id].(map[string]interface{})
+		if !ok {
+			t.Fatalf("Expected %s resource", id)
+		}
+		if mapping["Type"] != "AWS::ApiGateway::BasePathMapping" {
+			t.Errorf("Expected AWS::ApiGateway::BasePathMapping, got %v", mapping["Type"])
+		}
+		mappingProps := mapping["Properties"].(map[string]interface{})
+		if mappingProps["BasePath"] != basePath {
+			t.Errorf("Expected BasePath %s, got %v", basePath, mappingProps["BasePath"])
+		}
+		if !reflect.DeepEqual(mappingProps["Stage"], map[string]interface{}{"Ref": "MyApiStage"}) {
+			t.Errorf("Expected Stage Ref MyApiStage, got %v", mappingProps["Stage"])
+		}
+	}
+}
+
+func TestApiTransformer_Transform_DomainEdge(t *testing.T) {
+	transformer := NewApiTransformer()
+
+	api := &Api{
+		StageName: "Prod",
+		Domain: &DomainConfig{
+			DomainName:            "api.example.com",
+			CertificateArn:        "arn:aws:acm:us-east-1:123456789012:certificate/abc",
+			EndpointConfiguration: "EDGE",
+		},
+	}
+
+	resources, err := transformer.Transform("MyApi", api)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	props := resources["MyApiDomainName"].(map[string]interface{})["Properties"].(map[string]interface{})
+	if props["CertificateArn"] != "arn:aws:acm:us-east-1:123456789012:certificate/abc" {
+		t.Errorf("Expected CertificateArn, got %v", props)
+	}
+	if _, ok := props["RegionalCertificateArn"]; ok {
+		t.Error("Edge domain should not set RegionalCertificateArn")
+	}
+
+	mapping, ok := resources["MyApiBasePathMapping"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected MyApiBasePathMapping resource")
+	}
+	if _, ok := mapping["Properties"].(map[string]interface{})["BasePath"]; ok {
+		t.Error("Root mapping should not set BasePath")
+	}
+}
+
+func TestApiTransformer_Transform_DomainRoute53(t *testing.T) {
+	transformer := NewApiTransformer()
+
+	api := &Api{
+		StageName: "Prod",
+		Domain: &DomainConfig{
+			DomainName:            "api.example.com",
+			CertificateArn:        "arn:aws:acm:us-east-1:123456789012:certificate/abc",
+			EndpointConfiguration: "EDGE",
+			Route53: map[string]interface{}{
+				"HostedZoneId": "Z123456",
+				"IpV6":         true,
+			},
+		},
+	}
+
+	resources, err := transformer.Transform("MyApi", api)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	record, ok := resources["MyApiRecordSet"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected MyApiRecordSet resource")
+	}
+	props := record["Properties"].(map[string]interface{})
+	if props["Type"] != "A" || props["HostedZoneId"] != "Z123456" || props["Name"] != "api.example.com" {
+		t.Errorf("Unexpected A record properties: %v", props)
+	}
+	expectedAlias := map[string]interface{}{
+		"DNSName":      map[string]interface{}{"Fn::GetAtt": []interface{}{"MyApiDomainName", "DistributionDomainName"}},
+		"HostedZoneId": map[string]interface{}{"Fn::GetAtt": []interface{}{"MyApiDomainName", "DistributionHostedZoneId"}},
+	}
+	if !reflect.DeepEqual(props["AliasTarget"], expectedAlias) {
+		t.Errorf("Expected AliasTarget %v, got %v", expectedAlias, props["AliasTarget"])
+	}
+
+	v6, ok := resources["MyApiRecordSetV6"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected MyApiRecordSetV6 resource")
+	}
+	if v6["Properties"].(map[string]interface{})["Type"] != "AAAA" {
+		t.Errorf("Expected AAAA record, got %v", v6["Properties"])
+	}
+}
+
 func TestApiTransformer_Transform_WithMinimumCompressionSize(t *testing.T) {
 	transformer := NewApiTransformer()
 
@@ -568,6 +937,86 @@ func TestApiTransformer_Transform_WithMinimumCompressionSize(t *testing.T) {
 	}
 }
 
+func TestApiTransformer_Transform_CacheAndCompressionValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		api     *Api
+		wantErr string
+	}{
+		{
+			name: "invalid cache size",
+			api: &Api{
+				StageName:           "Prod",
+				CacheClusterEnabled: true,
+				CacheClusterSize:    "2.0",
+			},
+			wantErr: "invalid CacheClusterSize '2.0'",
+		},
+		{
+			name: "cache size without caching enabled",
+			api: &Api{
+				StageName:        "Prod",
+				CacheClusterSize: "0.5",
+			},
+			wantErr: "CacheClusterSize requires CacheClusterEnabled",
+		},
+		{
+			name: "compression size too large",
+			api: &Api{
+				StageName:              "Prod",
+				MinimumCompressionSize: maxMinimumCompressionSize + 1,
+			},
+			wantErr: "MinimumCompressionSize must be between",
+		},
+		{
+			name: "valid settings",
+			api: &Api{
+				StageName:              "Prod",
+				CacheClusterEnabled:    true,
+				CacheClusterSize:       "237",
+				MinimumCompressionSize: maxMinimumCompressionSize,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewApiTransformer()
+			tt.api.DefinitionBody = map[string]interface{}{"swagger": "2.0"}
+
+			resources, err := transformer.Transform("MyApi", tt.api)
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("Expected error containing %q", tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			restApiProps := resources["MyApi"].(map[string]interface{})["Properties"].(map[string]interface{})
+			if restApiProps["MinimumCompressionSize"] != maxMinimumCompressionSize {
+				t.Errorf("Expected MinimumCompressionSize on RestApi, got %v", restApiProps["MinimumCompressionSize"])
+			}
+			if _, ok := restApiProps["CacheClusterSize"]; ok {
+				t.Error("CacheClusterSize should not be set on the RestApi")
+			}
+
+			stageProps := resources["MyApiStage"].(map[string]interface{})["Properties"].(map[string]interface{})
+			if stageProps["CacheClusterSize"] != "237" {
+				t.Errorf("Expected CacheClusterSize '237' on Stage, got %v", stageProps["CacheClusterSize"])
+			}
+			if _, ok := stageProps["MinimumCompressionSize"]; ok {
+				t.Error("MinimumCompressionSize should not be set on the Stage")
+			}
+		})
+	}
+}
+
 func TestApiTransformer_Transform_DefaultStageNameIsRequired(t *testing.T) {
 	transformer := NewApiTransformer()
 
@@ -3,6 +3,7 @@ package sam
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -104,11 +105,18 @@ func (t *ApplicationTransformer) buildStackProperties(app *Application, ctx *Tra
 
 	// Process Tags
 	if len(app.Tags) > 0 {
-		tags := make([]interface{}, 0, len(app.Tags))
-		for k, v := range app.Tags {
+		// Sort keys for deterministic output
+		keys := make([]string, 0, len(app.Tags))
+		for k := range app.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		tags := make([]interface{}, 0, len(keys))
+		for _, k := range keys {
 			tags = append(tags, map[string]interface{}{
 				"Key":   k,
-				"Value": v,
+				"Value": app.Tags[k],
 			})
 		}
 		props["Tags"] = tags
@@ -1,6 +1,7 @@
 package sam
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -152,6 +153,14 @@ func TestApplicationTransformer_WithTags(t *testing.T) {
 			t.Error("each tag should have Key and Value")
 		}
 	}
+
+	expected := []interface{}{
+		map[string]interface{}{"Key": "Environment", "Value": "production"},
+		map[string]interface{}{"Key": "Team", "Value": "backend"},
+	}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Errorf("expected tags sorted by key %v, got %v", expected, tags)
+	}
 }
 
 func TestApplicationTransformer_WithTimeoutInMinutes(t *testing.T) {
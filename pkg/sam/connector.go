@@ -3,7 +3,11 @@ package sam
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 
+	"github.com/lex00/aws-sam-translator-go/pkg/intrinsics"
 	"github.com/lex00/aws-sam-translator-go/pkg/model/iam"
 )
 
@@ -12,6 +16,7 @@ const (
 	TypeLambdaFunction            = "AWS::Lambda::Function"
 	TypeServerlessFunction        = "AWS::Serverless::Function"
 	TypeDynamoDBTable             = "AWS::DynamoDB::Table"
+	TypeServerlessSimpleTable     = "AWS::Serverless::SimpleTable"
 	TypeSNSTopic                  = "AWS::SNS::Topic"
 	TypeSQSQueue                  = "AWS::SQS::Queue"
 	TypeS3Bucket                  = "AWS::S3::Bucket"
@@ -24,6 +29,8 @@ const (
 	TypeServerlessHttpApi         = "AWS::Serverless::HttpApi"
 	TypeLocationPlaceIndex        = "AWS::Location::PlaceIndex"
 	TypeAppSyncGraphQLApi         = "AWS::AppSync::GraphQLApi"
+	TypeServerlessGraphQLApi      = "AWS::Serverless::GraphQLApi"
+	TypeSESEmailIdentity          = "AWS::SES::EmailIdentity"
 	TypeEventsEventBus            = "AWS::Events::EventBus"
 )
 
@@ -93,9 +100,24 @@ func NewConnectorTransformer() *ConnectorTransformer {
 	}
 }
 
+// LoadProfiles adds the connector profiles in a ConnectorProfilesFile JSON
+// document, replacing built-in profiles for the same source and destination.
+func (t *ConnectorTransformer) LoadProfiles(data []byte) error {
+	return t.profiles.LoadJSON(data)
+}
+
 // Transform converts a SAM Connector to CloudFormation resources.
 // Returns a map of logical ID to CloudFormation resource.
 func (t *ConnectorTransformer) Transform(logicalID string, connector *Connector, templateResources map[string]interface{}) (map[string]interface{}, error) {
+	if err := validateNotSelfConnector(connector); err != nil {
+		return nil, err
+	}
+
+	permissions, err := t.normalizePermissions(connector.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
 	// Resolve source and destination types from template if ID is provided
 	sourceType, err := t.resolveResourceType(connector.Source, templateResources)
 	if err != nil {
@@ -116,14 +138,17 @@ func (t *ConnectorTransformer) Transform(logicalID string, connector *Connector,
 	// Build the resources based on the profile
 	resources := make(map[string]interface{})
 
-	// Deduplicate permissions
-	permissions := t.deduplicatePermissions(connector.Permissions)
+	// Managed policies for each permission share one logical ID, so they are
+	// collected in permission order and consolidated below
+	var policies []map[string]interface{}
+	var policyID string
 
 	for _, perm := range permissions {
 		switch profile.ResourceType {
 		case "AWS::IAM::ManagedPolicy":
-			policyResource, policyID := t.createManagedPolicy(logicalID, connector, sourceType, destType, perm, profile, templateResources)
-			resources[policyID] = policyResource
+			var policyResource map[string]interface{}
+			policyResource, policyID = t.createManagedPolicy(logicalID, connector, sourceType, destType, perm, profile, templateResources)
+			policies = append(policies, policyResource)
 		case "AWS::Lambda::Permission":
 			permResource, permID := t.createLambdaPermission(logicalID, connector, sourceType, destType, perm, profile, templateResources)
 			resources[permID] = permResource
@@ -136,25 +161,41 @@ func (t *ConnectorTransformer) Transform(logicalID string, connector *Connector,
 		}
 	}
 
-	// If there are multiple policies with same resource type, consolidate them
-	resources = t.consolidatePolicies(logicalID, resources, connector, sourceType, destType)
+	if len(policies) > 0 {
+		resources[policyID] = t.consolidatePolicies(logicalID, policies, sourceType, destType)
+	}
 
 	return resources, nil
 }
 
+// validateNotSelfConnector rejects a connector whose source and destination
+// are the same resource, which would grant a resource access to itself.
+func validateNotSelfConnector(connector *Connector) error {
+	source, dest := connector.Source, connector.Destination
+	if source.ID != "" && source.ID == dest.ID {
+		return fmt.Errorf("source and destination both reference resource %q; a connector cannot connect a resource to itself", source.ID)
+	}
+	if source.Arn != nil && reflect.DeepEqual(source.Arn, dest.Arn) {
+		return fmt.Errorf("source and destination have the same Arn %v; a connector cannot connect a resource to itself", source.Arn)
+	}
+	return nil
+}
+
 // TransformEmbedded transforms embedded connectors from a resource.
 // sourceID is the logical ID of the parent resource.
 // connectors is the map of connector names to EmbeddedConnector.
 func (t *ConnectorTransformer) TransformEmbedded(sourceID, sourceType string, connectors map[string]EmbeddedConnector, templateResources map[string]interface{}) (map[string]interface{}, error) {
 	allResources := make(map[string]interface{})
 
+	source, err := t.embeddedSource(sourceID, sourceType, templateResources)
+	if err != nil {
+		return nil, err
+	}
+
 	for connectorName, embedded := range connectors {
 		// Create a full Connector from the embedded connector
 		connector := &Connector{
-			Source: ConnectorEndpoint{
-				ID:   sourceID,
-				Type: sourceType,
-			},
+			Source:      source,
 			Destination: embedded.Properties.Destination,
 			Permissions: embedded.Properties.Permissions,
 		}
@@ -177,6 +218,42 @@ func (t *ConnectorTransformer) TransformEmbedded(sourceID, sourceType string, co
 	return allResources, nil
 }
 
+// embeddedSource builds the source endpoint for connectors embedded in a resource.
+// The type falls back to the owning resource's type, and the role and ARN are
+// inferred from it: SAM functions and state machines get a generated <Id>Role
+// unless they declare their own Role.
+func (t *ConnectorTransformer) embeddedSource(sourceID, sourceType string, templateResources map[string]interface{}) (ConnectorEndpoint, error) {
+	source := ConnectorEndpoint{ID: sourceID, Type: sourceType}
+
+	if source.Type == "" {
+		resolved, err := t.resolveResourceType(source, templateResources)
+		if err != nil {
+			return source, fmt.Errorf("failed to resolve source type: %w", err)
+		}
+		source.Type = resolved
+	}
+
+	var props map[string]interface{}
+	if resource, ok := templateResources[sourceID].(map[string]interface{}); ok {
+		props, _ = resource["Properties"].(map[string]interface{})
+	}
+
+	switch normalizeResourceType(source.Type) {
+	case TypeLambdaFunction, TypeStepFunctionsStateMachine:
+		if _, hasRole := props["Role"]; !hasRole && isServerlessType(source.Type) {
+			source.RoleName = map[string]interface{}{"Ref": sourceID + "Role"}
+		}
+	}
+	source.Arn = t.getResourceArn(source, source.Type, templateResources)
+
+	return source, nil
+}
+
+// isServerlessType reports whether resourceType is an AWS::Serverless::* type.
+func isServerlessType(resourceType string) bool {
+	return strings.HasPrefix(resourceType, "AWS::Serverless::")
+}
+
 // resolveResourceType gets the resource type from the endpoint or the template.
 func (t *ConnectorTransformer) resolveResourceType(endpoint ConnectorEndpoint, templateResources map[string]interface{}) (string, error) {
 	// If Type is explicitly provided, use it
@@ -204,17 +281,31 @@ func (t *ConnectorTransformer) resolveResourceType(endpoint ConnectorEndpoint, t
 	return "", fmt.Errorf("endpoint must have either Id or Type specified")
 }
 
-// deduplicatePermissions removes duplicate permissions.
-func (t *ConnectorTransformer) deduplicatePermissions(permissions []string) []string {
+// connectorPermissions lists the permissions a connector may grant.
+var connectorPermissions = []string{"Read", "Write"}
+
+// normalizePermissions validates permissions against connectorPermissions,
+// normalizes their case and removes duplicates.
+func (t *ConnectorTransformer) normalizePermissions(permissions []string) ([]string, error) {
 	seen := make(map[string]bool)
 	result := make([]string, 0, len(permissions))
 	for _, p := range permissions {
-		if !seen[p] {
-			seen[p] = true
-			result = append(result, p)
+		normalized := ""
+		for _, valid := range connectorPermissions {
+			if strings.EqualFold(p, valid) {
+				normalized = valid
+				break
+			}
+		}
+		if normalized == "" {
+			return nil, fmt.Errorf("unsupported permission '%s'; Permissions must be a list of [%s]", p, strings.Join(connectorPermissions, ", "))
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			result = append(result, normalized)
 		}
 	}
-	return result
+	return result, nil
 }
 
 // createManagedPolicy creates an AWS::IAM::ManagedPolicy resource.
@@ -225,11 +316,14 @@ func (t *ConnectorTransformer) createManagedPolicy(
 	profile *ConnectorProfile,
 	templateResources map[string]interface{},
 ) (map[string]interface{}, string) {
-	// Get role reference for the source
-	roleRef := t.getRoleReference(connector.Source, sourceType, templateResources)
+	// Get role references for the source
+	roleRefs := t.getRoleReferences(connector.Source, sourceType, templateResources)
 
 	// Get destination ARN
 	destArn := t.getResourceArn(connector.Destination, destType, templateResources)
+	if destType == TypeEventsRule {
+		destArn = t.getRuleEventBusArn(connector.Destination, templateResources)
+	}
 
 	// Get source ARN for some profiles
 	sourceArn := t.getResourceArn(connector.Source, sourceType, templateResources)
@@ -241,6 +335,12 @@ func (t *ConnectorTransformer) createManagedPolicy(
 	actions := profile.GetActions(permission, sourceType, destType)
 	resources := profile.GetResources(permission, destArn, sourceArn, destType, sourceType)
 
+	// Reading a table with a stream also covers reading the stream
+	if permission == "Read" && len(profile.StreamReadActions) > 0 && t.hasStream(connector.Destination, templateResources) {
+		actions = append(append([]string{}, actions...), profile.StreamReadActions...)
+		resources = append(resources, profile.GetStreamResources(destArn)...)
+	}
+
 	if len(actions) > 0 && len(resources) > 0 {
 		stmt := iam.NewAllowStatement()
 		stmt.Action = t.toInterfaceSlice(actions)
@@ -252,14 +352,49 @@ func (t *ConnectorTransformer) createManagedPolicy(
 	metadata := t.buildConnectorMetadata(logicalID, sourceType, destType)
 
 	policyID := logicalID + "Policy"
-	return map[string]interface{}{
+	policy := map[string]interface{}{
 		"Type":     "AWS::IAM::ManagedPolicy",
 		"Metadata": metadata,
 		"Properties": map[string]interface{}{
 			"PolicyDocument": policyDoc.ToMap(),
-			"Roles":          []interface{}{roleRef},
+			"Roles":          roleRefs,
 		},
-	}, policyID
+	}
+	if dependsOn := generatedRoleDependsOn(roleRefs, templateResources); len(dependsOn) > 0 {
+		policy["DependsOn"] = dependsOn
+	}
+	return policy, policyID
+}
+
+// generatedRoleDependsOn returns the roles referenced by Ref that the transform
+// generates: the <Id>Role of a serverless function or state machine in the
+// template that is not itself defined there. Other references, such as to a
+// parameter holding a role name, are not resources to depend on.
+func generatedRoleDependsOn(roleRefs []interface{}, templateResources map[string]interface{}) []interface{} {
+	var dependsOn []interface{}
+	for _, roleRef := range roleRefs {
+		ref, ok := roleRef.(map[string]interface{})
+		if !ok || len(ref) != 1 {
+			continue
+		}
+		roleID, ok := ref["Ref"].(string)
+		if !ok {
+			continue
+		}
+		if _, defined := templateResources[roleID]; defined {
+			continue
+		}
+		ownerID, isRole := strings.CutSuffix(roleID, "Role")
+		if !isRole {
+			continue
+		}
+		owner, _ := templateResources[ownerID].(map[string]interface{})
+		switch owner["Type"] {
+		case TypeServerlessFunction, TypeServerlessStateMachine:
+			dependsOn = append(dependsOn, roleID)
+		}
+	}
+	return dependsOn
 }
 
 // createLambdaPermission creates an AWS::Lambda::Permission resource.
@@ -373,37 +508,21 @@ func (t *ConnectorTransformer) createTopicPolicy(
 	}, policyID
 }
 
-// consolidatePolicies merges multiple IAM policies into one if they share the same type.
+// consolidatePolicies merges the managed policies generated for each of a
+// connector's permissions into one policy, keeping their statements in order.
 func (t *ConnectorTransformer) consolidatePolicies(
 	logicalID string,
-	resources map[string]interface{},
-	connector *Connector,
+	policies []map[string]interface{},
 	sourceType, destType string,
 ) map[string]interface{} {
-	// Collect all ManagedPolicy resources
-	var policies []map[string]interface{}
-	otherResources := make(map[string]interface{})
-
-	for id, r := range resources {
-		resource, ok := r.(map[string]interface{})
-		if !ok {
-			continue
-		}
-		if resource["Type"] == "AWS::IAM::ManagedPolicy" {
-			policies = append(policies, resource)
-		} else {
-			otherResources[id] = r
-		}
-	}
-
-	// If there's only one policy or no policies, no consolidation needed
-	if len(policies) <= 1 {
-		return resources
+	if len(policies) == 1 {
+		return policies[0]
 	}
 
 	// Merge all statements into one policy document
 	mergedDoc := iam.NewPolicyDocument()
 	var roles []interface{}
+	var dependsOn interface{}
 
 	for _, policy := range policies {
 		props, ok := policy["Properties"].(map[string]interface{})
@@ -436,6 +555,7 @@ func (t *ConnectorTransformer) consolidatePolicies(
 
 		if r, ok := props["Roles"].([]interface{}); ok && len(r) > 0 {
 			roles = r
+			dependsOn = policy["DependsOn"]
 		}
 	}
 
@@ -449,11 +569,10 @@ func (t *ConnectorTransformer) consolidatePolicies(
 			"Roles":          roles,
 		},
 	}
-
-	// Return consolidated resources
-	result := otherResources
-	result[logicalID+"Policy"] = mergedPolicy
-	return result
+	if dependsOn != nil {
+		mergedPolicy["DependsOn"] = dependsOn
+	}
+	return mergedPolicy
 }
 
 // buildConnectorMetadata builds the aws:sam:connectors metadata.
@@ -472,6 +591,55 @@ func (t *ConnectorTransformer) buildConnectorMetadata(logicalID, sourceType, des
 	}
 }
 
+// getRoleReferences gets the IAM roles a connector policy attaches to.
+// A serverless GraphQLApi has no role of its own; Lambda invocations are made
+// with the roles of its AWS_LAMBDA data sources.
+func (t *ConnectorTransformer) getRoleReferences(endpoint ConnectorEndpoint, resourceType string, templateResources map[string]interface{}) []interface{} {
+	if resourceType == TypeServerlessGraphQLApi && endpoint.RoleName == nil && endpoint.ID != "" {
+		if roles := t.getGraphQLApiLambdaDataSourceRoles(endpoint.ID, templateResources); len(roles) > 0 {
+			return roles
+		}
+	}
+	return []interface{}{t.getRoleReference(endpoint, resourceType, templateResources)}
+}
+
+// getGraphQLApiLambdaDataSourceRoles returns the roles of the AWS_LAMBDA data sources
+// defined on a serverless GraphQLApi, in data source name order.
+func (t *ConnectorTransformer) getGraphQLApiLambdaDataSourceRoles(apiID string, templateResources map[string]interface{}) []interface{} {
+	resource, ok := templateResources[apiID].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	props, ok := resource["Properties"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	dataSources, ok := props["DataSources"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	names := make([]string, 0, len(dataSources))
+	for name := range dataSources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var roles []interface{}
+	for _, name := range names {
+		ds, ok := dataSources[name].(map[string]interface{})
+		if !ok || ds["Type"] != "AWS_LAMBDA" {
+			continue
+		}
+		if serviceRoleArn, ok := ds["ServiceRoleArn"]; ok {
+			roles = append(roles, t.extractRoleNameFromArn(serviceRoleArn, apiID, TypeServerlessGraphQLApi))
+		} else {
+			roles = append(roles, map[string]interface{}{"Ref": apiID + name + "DataSourceRole"})
+		}
+	}
+	return roles
+}
+
 // getRoleReference gets the IAM role reference for a source resource.
 func (t *ConnectorTransformer) getRoleReference(endpoint ConnectorEndpoint, resourceType string, templateResources map[string]interface{}) interface{} {
 	// If RoleName is explicitly provided, use it
@@ -540,6 +708,11 @@ func (t *ConnectorTransformer) getResourceArn(endpoint ConnectorEndpoint, resour
 		return endpoint.Arn
 	}
 
+	// A queue given only by URL has its ARN derived from the URL
+	if endpoint.ID == "" && endpoint.QueueUrl != nil {
+		return queueArnFromUrl(endpoint.QueueUrl)
+	}
+
 	// If ID is provided, construct the ARN reference
 	if endpoint.ID != "" {
 		switch resourceType {
@@ -547,6 +720,12 @@ func (t *ConnectorTransformer) getResourceArn(endpoint ConnectorEndpoint, resour
 			return map[string]interface{}{"Ref": endpoint.ID}
 		case TypeServerlessStateMachine, TypeStepFunctionsStateMachine:
 			return map[string]interface{}{"Ref": endpoint.ID}
+		case TypeSESEmailIdentity:
+			// Email identities have no Arn attribute; Ref returns the identity
+			return intrinsics.NewSub(
+				"arn:${AWS::Partition}:ses:${AWS::Region}:${AWS::AccountId}:identity/${Identity}",
+				"Identity", intrinsics.NewRef(endpoint.ID),
+			)
 		default:
 			return map[string]interface{}{
 				"Fn::GetAtt": []interface{}{endpoint.ID, "Arn"},
@@ -557,6 +736,44 @@ func (t *ConnectorTransformer) getResourceArn(endpoint ConnectorEndpoint, resour
 	return nil
 }
 
+// hasStream reports whether the endpoint's resource in the template has a stream enabled.
+func (t *ConnectorTransformer) hasStream(endpoint ConnectorEndpoint, templateResources map[string]interface{}) bool {
+	resource, ok := templateResources[endpoint.ID].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	props, ok := resource["Properties"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, ok = props["StreamSpecification"]
+	return ok
+}
+
+// getRuleEventBusArn gets the ARN of the event bus an events rule is attached to.
+// An explicit Arn is used as is. Otherwise the rule's EventBusName may be a bus
+// name, a bus ARN or an intrinsic; rules without one are on the default bus.
+func (t *ConnectorTransformer) getRuleEventBusArn(endpoint ConnectorEndpoint, templateResources map[string]interface{}) interface{} {
+	if endpoint.Arn != nil {
+		return endpoint.Arn
+	}
+
+	var eventBusName interface{} = "default"
+	if resource, ok := templateResources[endpoint.ID].(map[string]interface{}); ok {
+		if props, ok := resource["Properties"].(map[string]interface{}); ok {
+			if name, ok := props["EventBusName"]; ok {
+				eventBusName = name
+			}
+		}
+	}
+
+	const busArnPattern = "arn:${AWS::Partition}:events:${AWS::Region}:${AWS::AccountId}:event-bus/"
+	if name, ok := eventBusName.(string); ok && strings.HasPrefix(name, "arn:") {
+		return name
+	}
+	return intrinsics.NewSub(busArnPattern+"${EventBusName}", "EventBusName", eventBusName)
+}
+
 // getSourceArnForLambdaPermission gets the source ARN for Lambda permission.
 func (t *ConnectorTransformer) getSourceArnForLambdaPermission(endpoint ConnectorEndpoint, resourceType string, templateResources map[string]interface{}) interface{} {
 	if endpoint.Arn != nil {
@@ -585,9 +802,89 @@ func (t *ConnectorTransformer) getQueueUrl(endpoint ConnectorEndpoint, templateR
 	if endpoint.ID != "" {
 		return map[string]interface{}{"Ref": endpoint.ID}
 	}
+	if endpoint.Arn != nil {
+		return queueUrlFromArn(endpoint.Arn)
+	}
 	return nil
 }
 
+// queueArnFromUrl derives a queue ARN from its URL. A Ref to a queue becomes
+// Fn::GetAtt Arn; a literal https://sqs.<region>.amazonaws.com/<account>/<name>
+// URL is rewritten, and anything else is split at runtime with Fn::Split.
+func queueArnFromUrl(queueUrl interface{}) interface{} {
+	switch v := queueUrl.(type) {
+	case string:
+		u := strings.TrimPrefix(strings.TrimPrefix(v, "https://"), "http://")
+		parts := strings.Split(u, "/")
+		hostParts := strings.Split(parts[0], ".")
+		if len(parts) == 3 && len(hostParts) >= 3 && hostParts[0] == "sqs" {
+			return map[string]interface{}{
+				"Fn::Sub": fmt.Sprintf("arn:${AWS::Partition}:sqs:%s:%s:%s", hostParts[1], parts[1], parts[2]),
+			}
+		}
+	case map[string]interface{}:
+		if ref, ok := v["Ref"].(string); ok && len(v) == 1 {
+			return map[string]interface{}{"Fn::GetAtt": []interface{}{ref, "Arn"}}
+		}
+	}
+
+	// https://sqs.<region>.<domain>/<account>/<name> splits into 5 parts
+	split := map[string]interface{}{"Fn::Split": []interface{}{"/", queueUrl}}
+	return map[string]interface{}{
+		"Fn::Sub": []interface{}{
+			"arn:${AWS::Partition}:sqs:${AWS::Region}:${Account}:${Name}",
+			map[string]interface{}{
+				"Account": map[string]interface{}{"Fn::Select": []interface{}{3, split}},
+				"Name":    map[string]interface{}{"Fn::Select": []interface{}{4, split}},
+			},
+		},
+	}
+}
+
+// queueUrlFromArn derives a queue URL from its ARN. A Fn::GetAtt Arn on a queue
+// becomes a Ref; a literal ARN is rewritten into the URL form. Anything else is
+// returned unchanged.
+func queueUrlFromArn(queueArn interface{}) interface{} {
+	if queueUrl, ok := lookupQueueUrl(queueArn); ok {
+		return queueUrl
+	}
+	return queueArn
+}
+
+// lookupQueueUrl derives a queue URL from its ARN, reporting whether the ARN
+// was a form it could convert.
+func lookupQueueUrl(queueArn interface{}) (interface{}, bool) {
+	switch v := queueArn.(type) {
+	case string:
+		parts := strings.Split(v, ":")
+		if len(parts) == 6 && parts[2] == "sqs" {
+			return map[string]interface{}{
+				"Fn::Sub": fmt.Sprintf("https://sqs.%s.${AWS::URLSuffix}/%s/%s", parts[3], parts[4], parts[5]),
+			}, true
+		}
+	case map[string]interface{}:
+		var queueID interface{}
+		switch getAtt := v["Fn::GetAtt"].(type) {
+		case []interface{}:
+			if len(getAtt) == 2 && getAtt[1] == "Arn" {
+				queueID = getAtt[0]
+			}
+		case []string:
+			if len(getAtt) == 2 && getAtt[1] == "Arn" {
+				queueID = getAtt[0]
+			}
+		case string:
+			if id, ok := strings.CutSuffix(getAtt, ".Arn"); ok {
+				queueID = id
+			}
+		}
+		if queueID != nil {
+			return map[string]interface{}{"Ref": queueID}, true
+		}
+	}
+	return nil, false
+}
+
 // toInterfaceSlice converts a string slice to an interface slice.
 func (t *ConnectorTransformer) toInterfaceSlice(strs []string) []interface{} {
 	result := make([]interface{}, len(strs))
@@ -636,6 +933,9 @@ func ExtractEmbeddedConnectors(templateResources map[string]interface{}) map[str
 				if arn, ok := destData["Arn"]; ok {
 					dest.Arn = arn
 				}
+				if queueUrl, ok := destData["QueueUrl"]; ok {
+					dest.QueueUrl = queueUrl
+				}
 			}
 
 			var permissions []string
@@ -1,5 +1,13 @@
 package sam
 
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
 // ConnectorProfile defines how to generate resources for a source/destination pair.
 type ConnectorProfile struct {
 	// ResourceType is the CloudFormation resource type to generate.
@@ -19,6 +27,12 @@ type ConnectorProfile struct {
 	// WriteResourcePatterns are resource patterns for Write permissions.
 	WriteResourcePatterns []ResourcePattern
 
+	// StreamReadActions are added to Read permissions when the destination has a stream enabled.
+	StreamReadActions []string
+
+	// StreamReadResourcePatterns are resource patterns added alongside StreamReadActions.
+	StreamReadResourcePatterns []ResourcePattern
+
 	// Principal is the service principal for resource policies.
 	Principal string
 }
@@ -44,12 +58,33 @@ type ConnectorProfiles struct {
 	profiles map[string]map[string]*ConnectorProfile
 }
 
-// NewConnectorProfiles creates a new ConnectorProfiles instance.
+// builtinProfiles holds the built-in profiles, built once per process by
+// NewConnectorProfiles. Profiles are never modified once added.
+var builtinProfiles struct {
+	once     sync.Once
+	profiles *ConnectorProfiles
+}
+
+// NewConnectorProfiles creates a new ConnectorProfiles instance with the
+// built-in profiles. The built-in profiles are built on the first call and
+// shared by later instances; each instance has its own lookup maps, so
+// LoadJSON on one does not affect the others.
 func NewConnectorProfiles() *ConnectorProfiles {
+	builtinProfiles.once.Do(func() {
+		builtinProfiles.profiles = &ConnectorProfiles{
+			profiles: make(map[string]map[string]*ConnectorProfile),
+		}
+		builtinProfiles.profiles.initProfiles()
+	})
+
 	p := &ConnectorProfiles{
-		profiles: make(map[string]map[string]*ConnectorProfile),
+		profiles: make(map[string]map[string]*ConnectorProfile, len(builtinProfiles.profiles.profiles)),
+	}
+	for sourceType, destProfiles := range builtinProfiles.profiles.profiles {
+		for destType, profile := range destProfiles {
+			p.addProfile(sourceType, destType, profile)
+		}
 	}
-	p.initProfiles()
 	return p
 }
 
@@ -67,6 +102,148 @@ func (p *ConnectorProfiles) GetProfile(sourceType, destType string) *ConnectorPr
 	return nil
 }
 
+// ConnectorProfilePair identifies a supported connector source and destination type.
+type ConnectorProfilePair struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+}
+
+// Pairs returns every source/destination pair with a profile, sorted by source then destination.
+func (p *ConnectorProfiles) Pairs() []ConnectorProfilePair {
+	var pairs []ConnectorProfilePair
+	for source, destProfiles := range p.profiles {
+		for dest := range destProfiles {
+			pairs = append(pairs, ConnectorProfilePair{Source: source, Destination: dest})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Source != pairs[j].Source {
+			return pairs[i].Source < pairs[j].Source
+		}
+		return pairs[i].Destination < pairs[j].Destination
+	})
+	return pairs
+}
+
+// connectorProfileResourceTypes are the resource types a connector profile can generate.
+var connectorProfileResourceTypes = []string{
+	"AWS::IAM::ManagedPolicy",
+	"AWS::Lambda::Permission",
+	"AWS::SQS::QueuePolicy",
+	"AWS::SNS::TopicPolicy",
+}
+
+// ConnectorProfilesFile is the JSON format of external connector profiles.
+//
+//	{
+//	  "Profiles": [
+//	    {
+//	      "Source": "AWS::Lambda::Function",
+//	      "Destination": "AWS::Custom::Store",
+//	      "Type": "AWS::IAM::ManagedPolicy",
+//	      "Actions": {"Read": ["store:Get"], "Write": ["store:Put"]},
+//	      "Resources": {"Read": ["${DestinationArn}", "${DestinationArn}/*"]}
+//	    }
+//	  ]
+//	}
+type ConnectorProfilesFile struct {
+	Profiles []ConnectorProfileDefinition `json:"Profiles"`
+}
+
+// ConnectorProfileDefinition defines one external connector profile.
+type ConnectorProfileDefinition struct {
+	// Source and Destination are the CloudFormation or SAM resource types
+	// the profile connects.
+	Source      string `json:"Source"`
+	Destination string `json:"Destination"`
+
+	// Type is the resource type the profile generates, one of
+	// AWS::IAM::ManagedPolicy, AWS::Lambda::Permission, AWS::SQS::QueuePolicy
+	// or AWS::SNS::TopicPolicy.
+	Type string `json:"Type"`
+
+	// Actions maps the Read and Write permissions to their IAM actions.
+	Actions map[string][]string `json:"Actions"`
+
+	// Resources maps the Read and Write permissions to Fn::Sub resource
+	// patterns, where ${DestinationArn} is the destination ARN. A permission
+	// without patterns uses the destination ARN directly.
+	Resources map[string][]string `json:"Resources,omitempty"`
+
+	// Principal is the service principal for resource policies.
+	Principal string `json:"Principal,omitempty"`
+}
+
+// LoadJSON adds the profiles in a ConnectorProfilesFile to p. A loaded
+// profile replaces any existing profile for the same source and destination.
+func (p *ConnectorProfiles) LoadJSON(data []byte) error {
+	var file ConnectorProfilesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse connector profiles: %w", err)
+	}
+
+	profiles := make([]*ConnectorProfile, len(file.Profiles))
+	for i, def := range file.Profiles {
+		profile, err := def.profile()
+		if err != nil {
+			return fmt.Errorf("connector profile %d (%s -> %s): %w", i, def.Source, def.Destination, err)
+		}
+		profiles[i] = profile
+	}
+
+	// Only add profiles once all of them are valid
+	for i, def := range file.Profiles {
+		p.addProfile(normalizeResourceType(def.Source), normalizeResourceType(def.Destination), profiles[i])
+	}
+	return nil
+}
+
+// profile validates the definition and converts it to a ConnectorProfile.
+func (d ConnectorProfileDefinition) profile() (*ConnectorProfile, error) {
+	if d.Source == "" || d.Destination == "" {
+		return nil, fmt.Errorf("Source and Destination are required")
+	}
+	if !containsString(connectorProfileResourceTypes, d.Type) {
+		return nil, fmt.Errorf("Type must be one of [%s], got '%s'", strings.Join(connectorProfileResourceTypes, ", "), d.Type)
+	}
+	for perm := range d.Actions {
+		if !containsString(connectorPermissions, perm) {
+			return nil, fmt.Errorf("unsupported permission '%s' in Actions; must be one of [%s]", perm, strings.Join(connectorPermissions, ", "))
+		}
+	}
+	for perm := range d.Resources {
+		if !containsString(connectorPermissions, perm) {
+			return nil, fmt.Errorf("unsupported permission '%s' in Resources; must be one of [%s]", perm, strings.Join(connectorPermissions, ", "))
+		}
+	}
+	if len(d.Actions) == 0 {
+		return nil, fmt.Errorf("Actions must define Read or Write actions")
+	}
+
+	return &ConnectorProfile{
+		ResourceType:          d.Type,
+		ReadActions:           d.Actions["Read"],
+		WriteActions:          d.Actions["Write"],
+		ReadResourcePatterns:  definitionResourcePatterns(d.Resources["Read"]),
+		WriteResourcePatterns: definitionResourcePatterns(d.Resources["Write"]),
+		Principal:             d.Principal,
+	}, nil
+}
+
+// definitionResourcePatterns converts Fn::Sub resource strings to resource
+// patterns. A bare ${DestinationArn} refers to the ARN directly.
+func definitionResourcePatterns(resources []string) []ResourcePattern {
+	var patterns []ResourcePattern
+	for _, resource := range resources {
+		if resource == "${DestinationArn}" {
+			patterns = append(patterns, ResourcePattern{UseArn: true})
+			continue
+		}
+		patterns = append(patterns, ResourcePattern{SubPattern: resource, VarName: "DestinationArn"})
+	}
+	return patterns
+}
+
 // normalizeResourceType normalizes SAM types to CloudFormation types for profile lookup.
 func normalizeResourceType(resourceType string) string {
 	switch resourceType {
@@ -78,6 +255,10 @@ func normalizeResourceType(resourceType string) string {
 		return TypeAPIGatewayRestApi
 	case TypeServerlessHttpApi:
 		return TypeAPIGatewayV2Api
+	case TypeServerlessGraphQLApi:
+		return TypeAppSyncGraphQLApi
+	case TypeServerlessSimpleTable:
+		return TypeDynamoDBTable
 	default:
 		return resourceType
 	}
@@ -130,6 +311,16 @@ func (p *ConnectorProfile) GetResources(permission string, destArn, sourceArn in
 		return []interface{}{destArn}
 	}
 
+	return expandResourcePatterns(patterns, destArn)
+}
+
+// GetStreamResources builds the resource references for stream Read permissions.
+func (p *ConnectorProfile) GetStreamResources(destArn interface{}) []interface{} {
+	return expandResourcePatterns(p.StreamReadResourcePatterns, destArn)
+}
+
+// expandResourcePatterns resolves resource patterns against the destination ARN.
+func expandResourcePatterns(patterns []ResourcePattern, destArn interface{}) []interface{} {
 	resources := make([]interface{}, 0, len(patterns))
 	for _, pattern := range patterns {
 		if pattern.UseArn {
@@ -180,6 +371,15 @@ func (p *ConnectorProfiles) initProfiles() {
 			{UseArn: true},
 			{SubPattern: "${DestinationArn}/index/*", VarName: "DestinationArn"},
 		},
+		StreamReadActions: []string{
+			"dynamodb:DescribeStream",
+			"dynamodb:GetRecords",
+			"dynamodb:GetShardIterator",
+			"dynamodb:ListStreams",
+		},
+		StreamReadResourcePatterns: []ResourcePattern{
+			{SubPattern: "${DestinationArn}/stream/*", VarName: "DestinationArn"},
+		},
 	})
 
 	// Lambda/Function -> S3 Bucket
@@ -270,6 +470,18 @@ func (p *ConnectorProfiles) initProfiles() {
 		},
 	})
 
+	// Lambda/Function -> SES Email Identity
+	p.addProfile(TypeLambdaFunction, TypeSESEmailIdentity, &ConnectorProfile{
+		ResourceType: "AWS::IAM::ManagedPolicy",
+		WriteActions: []string{
+			"ses:SendEmail",
+			"ses:SendRawEmail",
+		},
+		WriteResourcePatterns: []ResourcePattern{
+			{UseArn: true},
+		},
+	})
+
 	// Lambda/Function -> Location Place Index
 	p.addProfile(TypeLambdaFunction, TypeLocationPlaceIndex, &ConnectorProfile{
 		ResourceType: "AWS::IAM::ManagedPolicy",
@@ -463,6 +675,19 @@ func (p *ConnectorProfiles) initProfiles() {
 		},
 	})
 
+	// Lambda -> Events Rule
+	// Events are put on the bus the rule belongs to, so the destination ARN
+	// is resolved to the rule's event bus rather than the rule itself.
+	p.addProfile(TypeLambdaFunction, TypeEventsRule, &ConnectorProfile{
+		ResourceType: "AWS::IAM::ManagedPolicy",
+		WriteActions: []string{
+			"events:PutEvents",
+		},
+		WriteResourcePatterns: []ResourcePattern{
+			{UseArn: true},
+		},
+	})
+
 	// Step Functions -> DynamoDB Table
 	p.addProfile(TypeStepFunctionsStateMachine, TypeDynamoDBTable, &ConnectorProfile{
 		ResourceType: "AWS::IAM::ManagedPolicy",
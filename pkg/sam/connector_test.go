@@ -1,6 +1,8 @@
 package sam
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -237,6 +239,85 @@ func TestConnectorTransformer_Transform_EventsRuleToSQS(t *testing.T) {
 	}
 }
 
+func TestConnectorTransformer_Transform_SQSDestinationForms(t *testing.T) {
+	queueRef := map[string]interface{}{"Ref": "MyQueue"}
+	queueArn := map[string]interface{}{"Fn::GetAtt": []interface{}{"MyQueue", "Arn"}}
+
+	tests := []struct {
+		name        string
+		destination ConnectorEndpoint
+		expectedUrl interface{}
+		expectedArn interface{}
+	}{
+		{
+			name:        "by Id",
+			destination: ConnectorEndpoint{ID: "MyQueue"},
+			expectedUrl: queueRef,
+			expectedArn: queueArn,
+		},
+		{
+			name:        "by QueueUrl Ref",
+			destination: ConnectorEndpoint{Type: TypeSQSQueue, QueueUrl: queueRef},
+			expectedUrl: queueRef,
+			expectedArn: queueArn,
+		},
+		{
+			name:        "by Arn",
+			destination: ConnectorEndpoint{Type: TypeSQSQueue, Arn: queueArn},
+			expectedUrl: queueRef,
+			expectedArn: queueArn,
+		},
+		{
+			name: "by literal QueueUrl",
+			destination: ConnectorEndpoint{
+				Type:     TypeSQSQueue,
+				QueueUrl: "https://sqs.us-east-1.amazonaws.com/123456789012/my-queue",
+			},
+			expectedUrl: "https://sqs.us-east-1.amazonaws.com/123456789012/my-queue",
+			expectedArn: map[string]interface{}{"Fn::Sub": "arn:${AWS::Partition}:sqs:us-east-1:123456789012:my-queue"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewConnectorTransformer()
+
+			templateResources := map[string]interface{}{
+				"MyRule": map[string]interface{}{
+					"Type":       "AWS::Events::Rule",
+					"Properties": map[string]interface{}{},
+				},
+				"MyQueue": map[string]interface{}{
+					"Type":       "AWS::SQS::Queue",
+					"Properties": map[string]interface{}{},
+				},
+			}
+
+			connector := &Connector{
+				Source:      ConnectorEndpoint{ID: "MyRule"},
+				Destination: tt.destination,
+				Permissions: []string{"Write"},
+			}
+
+			resources, err := transformer.Transform("EventsConnector", connector, templateResources)
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			props := resources["EventsConnectorQueuePolicy"].(map[string]interface{})["Properties"].(map[string]interface{})
+			if !reflect.DeepEqual(props["Queues"], []interface{}{tt.expectedUrl}) {
+				t.Errorf("expected Queues [%v], got %v", tt.expectedUrl, props["Queues"])
+			}
+
+			statements := props["PolicyDocument"].(map[string]interface{})["Statement"].([]interface{})
+			stmt := statements[0].(map[string]interface{})
+			if !reflect.DeepEqual(stmt["Resource"], tt.expectedArn) {
+				t.Errorf("expected Resource %v, got %v", tt.expectedArn, stmt["Resource"])
+			}
+		})
+	}
+}
+
 func TestConnectorTransformer_Transform_EventsRuleToSNS(t *testing.T) {
 	transformer := NewConnectorTransformer()
 
@@ -372,6 +453,68 @@ func TestConnectorTransformer_Transform_DuplicatePermissions(t *testing.T) {
 	}
 }
 
+func TestConnectorTransformer_Transform_PermissionCaseNormalization(t *testing.T) {
+	transformer := NewConnectorTransformer()
+
+	templateResources := map[string]interface{}{
+		"MyFunction": map[string]interface{}{
+			"Type": "AWS::Serverless::Function",
+		},
+		"MyTable": map[string]interface{}{
+			"Type": "AWS::DynamoDB::Table",
+		},
+	}
+
+	normalized, err := transformer.Transform("CaseConnector", &Connector{
+		Source:      ConnectorEndpoint{ID: "MyFunction"},
+		Destination: ConnectorEndpoint{ID: "MyTable"},
+		Permissions: []string{"read", "WRITE"},
+	}, templateResources)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	canonical, err := transformer.Transform("CaseConnector", &Connector{
+		Source:      ConnectorEndpoint{ID: "MyFunction"},
+		Destination: ConnectorEndpoint{ID: "MyTable"},
+		Permissions: []string{"Read", "Write"},
+	}, templateResources)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(normalized, canonical) {
+		t.Errorf("expected lowercase permissions to match canonical ones, got %v and %v", normalized, canonical)
+	}
+}
+
+func TestConnectorTransformer_Transform_Error_InvalidPermission(t *testing.T) {
+	transformer := NewConnectorTransformer()
+
+	templateResources := map[string]interface{}{
+		"MyFunction": map[string]interface{}{
+			"Type": "AWS::Serverless::Function",
+		},
+		"MyTable": map[string]interface{}{
+			"Type": "AWS::DynamoDB::Table",
+		},
+	}
+
+	connector := &Connector{
+		Source:      ConnectorEndpoint{ID: "MyFunction"},
+		Destination: ConnectorEndpoint{ID: "MyTable"},
+		Permissions: []string{"Read", "ReadWrite"},
+	}
+
+	_, err := transformer.Transform("InvalidConnector", connector, templateResources)
+	if err == nil {
+		t.Fatal("expected error for invalid permission")
+	}
+	if !strings.Contains(err.Error(), "unsupported permission 'ReadWrite'") {
+		t.Errorf("expected unsupported permission error, got %v", err)
+	}
+}
+
 func TestConnectorTransformer_Transform_Error_MissingSource(t *testing.T) {
 	transformer := NewConnectorTransformer()
 
@@ -451,6 +594,55 @@ func TestConnectorTransformer_Transform_Error_UnsupportedProfile(t *testing.T) {
 	}
 }
 
+func TestConnectorTransformer_Transform_Error_SelfConnector(t *testing.T) {
+	transformer := NewConnectorTransformer()
+
+	templateResources := map[string]interface{}{
+		"MyTable": map[string]interface{}{
+			"Type": "AWS::DynamoDB::Table",
+		},
+	}
+
+	tableArn := map[string]interface{}{"Fn::GetAtt": []interface{}{"MyTable", "Arn"}}
+
+	tests := []struct {
+		name      string
+		connector *Connector
+		wantErr   string
+	}{
+		{
+			name: "same logical ID",
+			connector: &Connector{
+				Source:      ConnectorEndpoint{ID: "MyTable"},
+				Destination: ConnectorEndpoint{ID: "MyTable"},
+				Permissions: []string{"Read"},
+			},
+			wantErr: `source and destination both reference resource "MyTable"`,
+		},
+		{
+			name: "same Arn",
+			connector: &Connector{
+				Source:      ConnectorEndpoint{Type: "AWS::DynamoDB::Table", Arn: tableArn},
+				Destination: ConnectorEndpoint{Type: "AWS::DynamoDB::Table", Arn: tableArn},
+				Permissions: []string{"Read"},
+			},
+			wantErr: "source and destination have the same Arn",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := transformer.Transform("SelfConnector", tt.connector, templateResources)
+			if err == nil {
+				t.Fatal("expected error for self-connector")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestConnectorTransformer_TransformEmbedded(t *testing.T) {
 	transformer := NewConnectorTransformer()
 
@@ -493,6 +685,187 @@ func TestConnectorTransformer_TransformEmbedded(t *testing.T) {
 	}
 }
 
+func TestConnectorTransformer_TransformEmbedded_StateMachine(t *testing.T) {
+	tests := []struct {
+		name         string
+		sourceType   string
+		expectedRole interface{}
+	}{
+		{
+			name:         "explicit serverless type",
+			sourceType:   TypeServerlessStateMachine,
+			expectedRole: map[string]interface{}{"Ref": "MyStateMachineRole"},
+		},
+		{
+			name:         "type inferred from owning resource",
+			sourceType:   "",
+			expectedRole: map[string]interface{}{"Ref": "MyStateMachineRole"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewConnectorTransformer()
+
+			templateResources := map[string]interface{}{
+				"MyStateMachine": map[string]interface{}{
+					"Type": "AWS::Serverless::StateMachine",
+					"Properties": map[string]interface{}{
+						"DefinitionUri": "s3://bucket/definition.json",
+					},
+				},
+				"MyFunction": map[string]interface{}{
+					"Type":       "AWS::Serverless::Function",
+					"Properties": map[string]interface{}{},
+				},
+			}
+
+			connectors := map[string]EmbeddedConnector{
+				"InvokeConnector": {
+					Properties: EmbeddedConnectorProperties{
+						Destination: ConnectorEndpoint{ID: "MyFunction"},
+						Permissions: []string{"Write"},
+					},
+				},
+			}
+
+			resources, err := transformer.TransformEmbedded("MyStateMachine", tt.sourceType, connectors, templateResources)
+			if err != nil {
+				t.Fatalf("TransformEmbedded failed: %v", err)
+			}
+
+			policy, ok := resources["MyStateMachineInvokeConnectorPolicy"].(map[string]interface{})
+			if !ok {
+				t.Fatalf("expected policy resource, got keys: %v", getKeys(resources))
+			}
+
+			props := policy["Properties"].(map[string]interface{})
+			if !reflect.DeepEqual(props["Roles"], []interface{}{tt.expectedRole}) {
+				t.Errorf("expected Roles [%v], got %v", tt.expectedRole, props["Roles"])
+			}
+
+			metadata := policy["Metadata"].(map[string]interface{})["aws:sam:connectors"].(map[string]interface{})
+			source := metadata["MyStateMachineInvokeConnector"].(map[string]interface{})["Source"].(map[string]interface{})
+			if source["Type"] != TypeServerlessStateMachine {
+				t.Errorf("expected source type %s, got %v", TypeServerlessStateMachine, source["Type"])
+			}
+
+			statements := props["PolicyDocument"].(map[string]interface{})["Statement"].([]interface{})
+			stmt := statements[0].(map[string]interface{})
+			if !reflect.DeepEqual(stmt["Action"], []interface{}{"lambda:InvokeAsync", "lambda:InvokeFunction"}) {
+				t.Errorf("expected lambda invoke actions, got %v", stmt["Action"])
+			}
+		})
+	}
+}
+
+func TestConnectorTransformer_TransformEmbedded_StateMachineToTable(t *testing.T) {
+	tests := []struct {
+		name      string
+		tableType string
+	}{
+		{name: "DynamoDB table", tableType: TypeDynamoDBTable},
+		{name: "SimpleTable", tableType: TypeServerlessSimpleTable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewConnectorTransformer()
+
+			templateResources := map[string]interface{}{
+				"MyStateMachine": map[string]interface{}{
+					"Type": "AWS::Serverless::StateMachine",
+					"Properties": map[string]interface{}{
+						"DefinitionUri": "s3://bucket/definition.json",
+					},
+				},
+				"MyTable": map[string]interface{}{
+					"Type": tt.tableType,
+				},
+			}
+
+			connectors := map[string]EmbeddedConnector{
+				"TableConnector": {
+					Properties: EmbeddedConnectorProperties{
+						Destination: ConnectorEndpoint{ID: "MyTable"},
+						Permissions: []string{"Read", "Write"},
+					},
+				},
+			}
+
+			resources, err := transformer.TransformEmbedded("MyStateMachine", "", connectors, templateResources)
+			if err != nil {
+				t.Fatalf("TransformEmbedded failed: %v", err)
+			}
+			if len(resources) != 1 {
+				t.Fatalf("expected 1 resource, got keys: %v", getKeys(resources))
+			}
+
+			policy, ok := resources["MyStateMachineTableConnectorPolicy"].(map[string]interface{})
+			if !ok {
+				t.Fatalf("expected policy resource, got keys: %v", getKeys(resources))
+			}
+			props := policy["Properties"].(map[string]interface{})
+			if !reflect.DeepEqual(props["Roles"], []interface{}{map[string]interface{}{"Ref": "MyStateMachineRole"}}) {
+				t.Errorf("expected Roles [MyStateMachineRole], got %v", props["Roles"])
+			}
+
+			// Read and Write each produce a statement in the consolidated policy
+			statements := props["PolicyDocument"].(map[string]interface{})["Statement"].([]interface{})
+			if len(statements) != 2 {
+				t.Fatalf("expected 2 statements, got %v", statements)
+			}
+			tableArn := map[string]interface{}{"Fn::GetAtt": []interface{}{"MyTable", "Arn"}}
+			for i, action := range []string{"dynamodb:GetItem", "dynamodb:PutItem"} {
+				stmt := statements[i].(map[string]interface{})
+				if actions, ok := stmt["Action"].([]interface{}); !ok || actions[0] != action {
+					t.Errorf("expected statement %d to start with %s, got %v", i, action, stmt["Action"])
+				}
+				if !reflect.DeepEqual(stmt["Resource"].([]interface{})[0], tableArn) {
+					t.Errorf("expected statement %d resource %v, got %v", i, tableArn, stmt["Resource"])
+				}
+			}
+		})
+	}
+}
+
+func TestConnectorTransformer_TransformEmbedded_FunctionWithExplicitRole(t *testing.T) {
+	transformer := NewConnectorTransformer()
+
+	templateResources := map[string]interface{}{
+		"MyFunction": map[string]interface{}{
+			"Type": "AWS::Serverless::Function",
+			"Properties": map[string]interface{}{
+				"Role": map[string]interface{}{"Fn::GetAtt": []interface{}{"SharedRole", "Arn"}},
+			},
+		},
+		"MyQueue": map[string]interface{}{
+			"Type":       "AWS::SQS::Queue",
+			"Properties": map[string]interface{}{},
+		},
+	}
+
+	connectors := map[string]EmbeddedConnector{
+		"QueueConnector": {
+			Properties: EmbeddedConnectorProperties{
+				Destination: ConnectorEndpoint{ID: "MyQueue"},
+				Permissions: []string{"Write"},
+			},
+		},
+	}
+
+	resources, err := transformer.TransformEmbedded("MyFunction", TypeServerlessFunction, connectors, templateResources)
+	if err != nil {
+		t.Fatalf("TransformEmbedded failed: %v", err)
+	}
+
+	props := resources["MyFunctionQueueConnectorPolicy"].(map[string]interface{})["Properties"].(map[string]interface{})
+	expectedRoles := []interface{}{map[string]interface{}{"Ref": "SharedRole"}}
+	if !reflect.DeepEqual(props["Roles"], expectedRoles) {
+		t.Errorf("expected Roles %v, got %v", expectedRoles, props["Roles"])
+	}
+}
+
 func TestConnectorTransformer_TransformEmbedded_MultipleConnectors(t *testing.T) {
 	transformer := NewConnectorTransformer()
 
@@ -682,6 +1055,20 @@ func TestConnectorProfiles_GetProfile(t *testing.T) {
 			expectNil:    false,
 			expectedType: "AWS::SNS::TopicPolicy",
 		},
+		{
+			name:         "Serverless Function to EventBus",
+			sourceType:   TypeServerlessFunction,
+			destType:     TypeEventsEventBus,
+			expectNil:    false,
+			expectedType: "AWS::IAM::ManagedPolicy",
+		},
+		{
+			name:         "Serverless Function to Events Rule",
+			sourceType:   TypeServerlessFunction,
+			destType:     TypeEventsRule,
+			expectNil:    false,
+			expectedType: "AWS::IAM::ManagedPolicy",
+		},
 		{
 			name:       "Unsupported combination",
 			sourceType: TypeSQSQueue,
@@ -834,6 +1221,7 @@ func TestNormalizeResourceType(t *testing.T) {
 		{TypeServerlessStateMachine, TypeStepFunctionsStateMachine},
 		{TypeServerlessApi, TypeAPIGatewayRestApi},
 		{TypeServerlessHttpApi, TypeAPIGatewayV2Api},
+		{TypeServerlessGraphQLApi, TypeAppSyncGraphQLApi},
 		{TypeLambdaFunction, TypeLambdaFunction},
 		{TypeDynamoDBTable, TypeDynamoDBTable},
 	}
@@ -984,6 +1372,295 @@ func TestConnectorTransformer_LambdaToS3(t *testing.T) {
 	}
 }
 
+func TestConnectorTransformer_LambdaToEventBridge(t *testing.T) {
+	tests := []struct {
+		name             string
+		destination      map[string]interface{}
+		destinationArn   interface{}
+		expectedResource interface{}
+	}{
+		{
+			name: "event bus destination uses bus ARN",
+			destination: map[string]interface{}{
+				"Type":       "AWS::Events::EventBus",
+				"Properties": map[string]interface{}{"Name": "orders"},
+			},
+			expectedResource: map[string]interface{}{
+				"Fn::GetAtt": []interface{}{"MyDestination", "Arn"},
+			},
+		},
+		{
+			name: "rule destination uses the rule's bus",
+			destination: map[string]interface{}{
+				"Type": "AWS::Events::Rule",
+				"Properties": map[string]interface{}{
+					"EventBusName": map[string]interface{}{"Ref": "OrdersBus"},
+				},
+			},
+			expectedResource: map[string]interface{}{
+				"Fn::Sub": []interface{}{
+					"arn:${AWS::Partition}:events:${AWS::Region}:${AWS::AccountId}:event-bus/${EventBusName}",
+					map[string]interface{}{"EventBusName": map[string]interface{}{"Ref": "OrdersBus"}},
+				},
+			},
+		},
+		{
+			name: "rule destination defaults to the default bus",
+			destination: map[string]interface{}{
+				"Type":       "AWS::Events::Rule",
+				"Properties": map[string]interface{}{},
+			},
+			expectedResource: map[string]interface{}{
+				"Fn::Sub": "arn:${AWS::Partition}:events:${AWS::Region}:${AWS::AccountId}:event-bus/default",
+			},
+		},
+		{
+			name: "rule destination with explicit Arn uses it",
+			destination: map[string]interface{}{
+				"Type": "AWS::Events::Rule",
+				"Properties": map[string]interface{}{
+					"EventBusName": map[string]interface{}{"Ref": "OrdersBus"},
+				},
+			},
+			destinationArn:   "arn:aws:events:us-east-1:123456789012:event-bus/shared",
+			expectedResource: "arn:aws:events:us-east-1:123456789012:event-bus/shared",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewConnectorTransformer()
+
+			templateResources := map[string]interface{}{
+				"MyFunction": map[string]interface{}{
+					"Type":       "AWS::Serverless::Function",
+					"Properties": map[string]interface{}{},
+				},
+				"MyDestination": tt.destination,
+			}
+
+			connector := &Connector{
+				Source:      ConnectorEndpoint{ID: "MyFunction"},
+				Destination: ConnectorEndpoint{ID: "MyDestination", Arn: tt.destinationArn},
+				Permissions: []string{"Write"},
+			}
+
+			resources, err := transformer.Transform("EventsConnector", connector, templateResources)
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			policy, ok := resources["EventsConnectorPolicy"].(map[string]interface{})
+			if !ok {
+				t.Fatalf("expected policy resource, got keys: %v", getKeys(resources))
+			}
+
+			props := policy["Properties"].(map[string]interface{})
+			statements := props["PolicyDocument"].(map[string]interface{})["Statement"].([]interface{})
+			if len(statements) != 1 {
+				t.Fatalf("expected 1 statement, got %d", len(statements))
+			}
+
+			stmt := statements[0].(map[string]interface{})
+			if !reflect.DeepEqual(stmt["Action"], []interface{}{"events:PutEvents"}) {
+				t.Errorf("expected events:PutEvents, got %v", stmt["Action"])
+			}
+			if !reflect.DeepEqual(stmt["Resource"], []interface{}{tt.expectedResource}) {
+				t.Errorf("expected Resource %v, got %v", tt.expectedResource, stmt["Resource"])
+			}
+		})
+	}
+}
+
+func TestConnectorTransformer_GraphQLApiToLambda(t *testing.T) {
+	transformer := NewConnectorTransformer()
+
+	templateResources := map[string]interface{}{
+		"MyApi": map[string]interface{}{
+			"Type": "AWS::Serverless::GraphQLApi",
+			"Properties": map[string]interface{}{
+				"DataSources": map[string]interface{}{
+					"Resolver": map[string]interface{}{
+						"Type": "AWS_LAMBDA",
+						"LambdaConfig": map[string]interface{}{
+							"LambdaFunctionArn": map[string]interface{}{"Fn::GetAtt": []interface{}{"MyFunction", "Arn"}},
+						},
+					},
+					"Table": map[string]interface{}{
+						"Type": "AMAZON_DYNAMODB",
+					},
+				},
+			},
+		},
+		"MyFunction": map[string]interface{}{
+			"Type":       "AWS::Serverless::Function",
+			"Properties": map[string]interface{}{},
+		},
+	}
+
+	connector := &Connector{
+		Source:      ConnectorEndpoint{ID: "MyApi"},
+		Destination: ConnectorEndpoint{ID: "MyFunction"},
+		Permissions: []string{"Write"},
+	}
+
+	resources, err := transformer.Transform("ApiConnector", connector, templateResources)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	policy, ok := resources["ApiConnectorPolicy"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected policy resource, got keys: %v", getKeys(resources))
+	}
+	props := policy["Properties"].(map[string]interface{})
+
+	expectedRoles := []interface{}{map[string]interface{}{"Ref": "MyApiResolverDataSourceRole"}}
+	if !reflect.DeepEqual(props["Roles"], expectedRoles) {
+		t.Errorf("expected Roles %v, got %v", expectedRoles, props["Roles"])
+	}
+
+	statements := props["PolicyDocument"].(map[string]interface{})["Statement"].([]interface{})
+	stmt := statements[0].(map[string]interface{})
+	if !reflect.DeepEqual(stmt["Action"], []interface{}{"lambda:InvokeFunction"}) {
+		t.Errorf("expected lambda:InvokeFunction, got %v", stmt["Action"])
+	}
+	expectedResource := []interface{}{map[string]interface{}{"Fn::GetAtt": []interface{}{"MyFunction", "Arn"}}}
+	if !reflect.DeepEqual(stmt["Resource"], expectedResource) {
+		t.Errorf("expected Resource %v, got %v", expectedResource, stmt["Resource"])
+	}
+}
+
+func TestConnectorTransformer_LambdaToSES(t *testing.T) {
+	transformer := NewConnectorTransformer()
+
+	templateResources := map[string]interface{}{
+		"MyFunction": map[string]interface{}{
+			"Type":       "AWS::Serverless::Function",
+			"Properties": map[string]interface{}{},
+		},
+		"MyIdentity": map[string]interface{}{
+			"Type": "AWS::SES::EmailIdentity",
+			"Properties": map[string]interface{}{
+				"EmailIdentity": "example.com",
+			},
+		},
+	}
+
+	connector := &Connector{
+		Source:      ConnectorEndpoint{ID: "MyFunction"},
+		Destination: ConnectorEndpoint{ID: "MyIdentity"},
+		Permissions: []string{"Write"},
+	}
+
+	resources, err := transformer.Transform("EmailConnector", connector, templateResources)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	policy, ok := resources["EmailConnectorPolicy"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected policy resource, got keys: %v", getKeys(resources))
+	}
+
+	props := policy["Properties"].(map[string]interface{})
+	statements := props["PolicyDocument"].(map[string]interface{})["Statement"].([]interface{})
+	stmt := statements[0].(map[string]interface{})
+	if !reflect.DeepEqual(stmt["Action"], []interface{}{"ses:SendEmail", "ses:SendRawEmail"}) {
+		t.Errorf("expected SES send actions, got %v", stmt["Action"])
+	}
+	expectedResource := []interface{}{
+		map[string]interface{}{
+			"Fn::Sub": []interface{}{
+				"arn:${AWS::Partition}:ses:${AWS::Region}:${AWS::AccountId}:identity/${Identity}",
+				map[string]interface{}{"Identity": map[string]interface{}{"Ref": "MyIdentity"}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(stmt["Resource"], expectedResource) {
+		t.Errorf("expected Resource %v, got %v", expectedResource, stmt["Resource"])
+	}
+}
+
+func TestConnectorTransformer_LambdaToDynamoDBStream(t *testing.T) {
+	tests := []struct {
+		name          string
+		tableProps    map[string]interface{}
+		expectStreams bool
+	}{
+		{
+			name: "table with stream",
+			tableProps: map[string]interface{}{
+				"StreamSpecification": map[string]interface{}{"StreamViewType": "NEW_IMAGE"},
+			},
+			expectStreams: true,
+		},
+		{
+			name:          "table without stream",
+			tableProps:    map[string]interface{}{},
+			expectStreams: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewConnectorTransformer()
+
+			templateResources := map[string]interface{}{
+				"MyFunction": map[string]interface{}{
+					"Type":       "AWS::Serverless::Function",
+					"Properties": map[string]interface{}{},
+				},
+				"MyTable": map[string]interface{}{
+					"Type":       "AWS::DynamoDB::Table",
+					"Properties": tt.tableProps,
+				},
+			}
+
+			connector := &Connector{
+				Source:      ConnectorEndpoint{ID: "MyFunction"},
+				Destination: ConnectorEndpoint{ID: "MyTable"},
+				Permissions: []string{"Read"},
+			}
+
+			resources, err := transformer.Transform("TableConnector", connector, templateResources)
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			props := resources["TableConnectorPolicy"].(map[string]interface{})["Properties"].(map[string]interface{})
+			statements := props["PolicyDocument"].(map[string]interface{})["Statement"].([]interface{})
+			stmt := statements[0].(map[string]interface{})
+
+			hasGetRecords := false
+			for _, a := range stmt["Action"].([]interface{}) {
+				if a == "dynamodb:GetRecords" {
+					hasGetRecords = true
+				}
+			}
+			if hasGetRecords != tt.expectStreams {
+				t.Errorf("expected stream actions present = %v, got actions %v", tt.expectStreams, stmt["Action"])
+			}
+
+			streamArn := map[string]interface{}{
+				"Fn::Sub": []interface{}{
+					"${DestinationArn}/stream/*",
+					map[string]interface{}{"DestinationArn": map[string]interface{}{"Fn::GetAtt": []interface{}{"MyTable", "Arn"}}},
+				},
+			}
+			hasStreamArn := false
+			for _, r := range stmt["Resource"].([]interface{}) {
+				if reflect.DeepEqual(r, streamArn) {
+					hasStreamArn = true
+				}
+			}
+			if hasStreamArn != tt.expectStreams {
+				t.Errorf("expected stream ARN present = %v, got resources %v", tt.expectStreams, stmt["Resource"])
+			}
+		})
+	}
+}
+
 func TestConnectorTransformer_LambdaToSQS(t *testing.T) {
 	transformer := NewConnectorTransformer()
 
@@ -1047,6 +1724,70 @@ func TestConnectorTransformer_LambdaToSQS(t *testing.T) {
 	}
 }
 
+func TestConnectorTransformer_PolicyDependsOnGeneratedRole(t *testing.T) {
+	tests := []struct {
+		name          string
+		functionProps map[string]interface{}
+		roleName      interface{}
+		extra         map[string]interface{}
+		wantDependsOn interface{}
+	}{
+		{
+			name:          "generated role",
+			functionProps: map[string]interface{}{"Handler": "index.handler"},
+			wantDependsOn: []interface{}{"MyFunctionRole"},
+		},
+		{
+			name:          "role defined in template",
+			functionProps: map[string]interface{}{"Role": map[string]interface{}{"Fn::GetAtt": []interface{}{"MyRole", "Arn"}}},
+			extra:         map[string]interface{}{"MyRole": map[string]interface{}{"Type": "AWS::IAM::Role"}},
+		},
+		{
+			name:          "role name from parameter",
+			functionProps: map[string]interface{}{"Handler": "index.handler"},
+			roleName:      map[string]interface{}{"Ref": "ExistingRoleName"},
+		},
+		{
+			name:          "parameter named like a generated role",
+			functionProps: map[string]interface{}{"Handler": "index.handler"},
+			roleName:      map[string]interface{}{"Ref": "SharedRole"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewConnectorTransformer()
+
+			templateResources := map[string]interface{}{
+				"MyFunction": map[string]interface{}{
+					"Type":       "AWS::Serverless::Function",
+					"Properties": tt.functionProps,
+				},
+				"MyQueue": map[string]interface{}{"Type": "AWS::SQS::Queue"},
+			}
+			for k, v := range tt.extra {
+				templateResources[k] = v
+			}
+
+			connector := &Connector{
+				Source:      ConnectorEndpoint{ID: "MyFunction", RoleName: tt.roleName},
+				Destination: ConnectorEndpoint{ID: "MyQueue"},
+				Permissions: []string{"Write"},
+			}
+
+			resources, err := transformer.Transform("MyConnector", connector, templateResources)
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			policy := resources["MyConnectorPolicy"].(map[string]interface{})
+			if !reflect.DeepEqual(policy["DependsOn"], tt.wantDependsOn) {
+				t.Errorf("expected DependsOn %v, got %v", tt.wantDependsOn, policy["DependsOn"])
+			}
+		})
+	}
+}
+
 func TestConnectorTransformer_APIGatewayToLambda(t *testing.T) {
 	transformer := NewConnectorTransformer()
 
@@ -1108,3 +1849,180 @@ func getKeys(m map[string]interface{}) []string {
 	}
 	return keys
 }
+
+func TestLookupQueueUrl(t *testing.T) {
+	tests := []struct {
+		name     string
+		queueArn interface{}
+		expected interface{}
+		wantOK   bool
+	}{
+		{
+			name:     "literal arn",
+			queueArn: "arn:aws:sqs:us-east-1:123456789012:my-dlq",
+			expected: map[string]interface{}{"Fn::Sub": "https://sqs.us-east-1.${AWS::URLSuffix}/123456789012/my-dlq"},
+			wantOK:   true,
+		},
+		{
+			name:     "getatt list",
+			queueArn: map[string]interface{}{"Fn::GetAtt": []interface{}{"MyDLQ", "Arn"}},
+			expected: map[string]interface{}{"Ref": "MyDLQ"},
+			wantOK:   true,
+		},
+		{
+			name:     "getatt string",
+			queueArn: map[string]interface{}{"Fn::GetAtt": "MyDLQ.Arn"},
+			expected: map[string]interface{}{"Ref": "MyDLQ"},
+			wantOK:   true,
+		},
+		{
+			name:     "other intrinsic",
+			queueArn: map[string]interface{}{"Ref": "DLQArnParam"},
+		},
+		{
+			name:     "not an sqs arn",
+			queueArn: "arn:aws:sns:us-east-1:123456789012:topic",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := lookupQueueUrl(tt.queueArn)
+			if ok != tt.wantOK {
+				t.Fatalf("expected ok %v, got %v", tt.wantOK, ok)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+const customConnectorProfiles = `{
+  "Profiles": [
+    {
+      "Source": "AWS::Serverless::Function",
+      "Destination": "AWS::Custom::Store",
+      "Type": "AWS::IAM::ManagedPolicy",
+      "Actions": {"Read": ["store:Get"], "Write": ["store:Put"]},
+      "Resources": {"Read": ["${DestinationArn}", "${DestinationArn}/items/*"]}
+    },
+    {
+      "Source": "AWS::Lambda::Function",
+      "Destination": "AWS::SQS::Queue",
+      "Type": "AWS::IAM::ManagedPolicy",
+      "Actions": {"Write": ["sqs:SendMessage"]}
+    }
+  ]
+}`
+
+func TestConnectorProfiles_LoadJSON(t *testing.T) {
+	profiles := NewConnectorProfiles()
+	if err := profiles.LoadJSON([]byte(customConnectorProfiles)); err != nil {
+		t.Fatalf("LoadJSON failed: %v", err)
+	}
+
+	custom := profiles.GetProfile(TypeLambdaFunction, "AWS::Custom::Store")
+	if custom == nil {
+		t.Fatal("expected profile for Lambda -> AWS::Custom::Store")
+	}
+	expectedPatterns := []ResourcePattern{
+		{UseArn: true},
+		{SubPattern: "${DestinationArn}/items/*", VarName: "DestinationArn"},
+	}
+	if !reflect.DeepEqual(custom.ReadResourcePatterns, expectedPatterns) {
+		t.Errorf("expected Read patterns %v, got %v", expectedPatterns, custom.ReadResourcePatterns)
+	}
+	if custom.WriteResourcePatterns != nil {
+		t.Errorf("expected no Write patterns, got %v", custom.WriteResourcePatterns)
+	}
+
+	// External profiles replace built-ins for the same pair
+	queue := profiles.GetProfile(TypeLambdaFunction, TypeSQSQueue)
+	if queue == nil || !reflect.DeepEqual(queue.WriteActions, []string{"sqs:SendMessage"}) || queue.ReadActions != nil {
+		t.Errorf("expected external Lambda -> SQS profile to win, got %+v", queue)
+	}
+
+	// Other built-ins are kept
+	if profiles.GetProfile(TypeLambdaFunction, TypeDynamoDBTable) == nil {
+		t.Error("expected built-in Lambda -> DynamoDB profile to be kept")
+	}
+
+	// Instances share the built-in profiles but not loaded ones
+	fresh := NewConnectorProfiles()
+	if fresh.GetProfile(TypeLambdaFunction, "AWS::Custom::Store") != nil {
+		t.Error("expected loaded profiles not to leak into new instances")
+	}
+	if fresh.GetProfile(TypeLambdaFunction, TypeSQSQueue) == queue {
+		t.Error("expected new instances to keep the built-in Lambda -> SQS profile")
+	}
+}
+
+func TestConnectorProfiles_LoadJSON_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "invalid JSON", data: `{"Profiles": [`, wantErr: "failed to parse connector profiles"},
+		{name: "missing destination", data: `{"Profiles": [{"Source": "AWS::Lambda::Function", "Type": "AWS::IAM::ManagedPolicy", "Actions": {"Read": ["a:B"]}}]}`, wantErr: "Source and Destination are required"},
+		{name: "unsupported type", data: `{"Profiles": [{"Source": "AWS::Lambda::Function", "Destination": "AWS::Custom::Store", "Type": "AWS::IAM::Policy", "Actions": {"Read": ["a:B"]}}]}`, wantErr: "Type must be one of"},
+		{name: "unsupported permission", data: `{"Profiles": [{"Source": "AWS::Lambda::Function", "Destination": "AWS::Custom::Store", "Type": "AWS::IAM::ManagedPolicy", "Actions": {"Delete": ["a:B"]}}]}`, wantErr: "unsupported permission 'Delete' in Actions"},
+		{name: "no actions", data: `{"Profiles": [{"Source": "AWS::Lambda::Function", "Destination": "AWS::Custom::Store", "Type": "AWS::IAM::ManagedPolicy"}]}`, wantErr: "Actions must define Read or Write actions"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profiles := NewConnectorProfiles()
+			err := profiles.LoadJSON([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if profiles.GetProfile(TypeLambdaFunction, "AWS::Custom::Store") != nil {
+				t.Error("expected no profiles to be added from an invalid file")
+			}
+		})
+	}
+}
+
+func TestConnectorTransformer_CustomProfile(t *testing.T) {
+	transformer := NewConnectorTransformer()
+	if err := transformer.LoadProfiles([]byte(customConnectorProfiles)); err != nil {
+		t.Fatalf("LoadProfiles failed: %v", err)
+	}
+
+	templateResources := map[string]interface{}{
+		"MyFunction": map[string]interface{}{
+			"Type":       "AWS::Serverless::Function",
+			"Properties": map[string]interface{}{"Handler": "index.handler"},
+		},
+		"MyStore": map[string]interface{}{
+			"Type":       "AWS::Custom::Store",
+			"Properties": map[string]interface{}{},
+		},
+	}
+
+	connector := &Connector{
+		Source:      ConnectorEndpoint{ID: "MyFunction"},
+		Destination: ConnectorEndpoint{ID: "MyStore"},
+		Permissions: []string{"Read"},
+	}
+
+	resources, err := transformer.Transform("StoreConnector", connector, templateResources)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	policy, ok := resources["StoreConnectorPolicy"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected policy resource, got keys: %v", getKeys(resources))
+	}
+	doc := policy["Properties"].(map[string]interface{})["PolicyDocument"].(map[string]interface{})
+	stmt := doc["Statement"].([]interface{})[0].(map[string]interface{})
+	if !reflect.DeepEqual(stmt["Action"], []interface{}{"store:Get"}) {
+		t.Errorf("expected Action [store:Get], got %v", stmt["Action"])
+	}
+	if arns, ok := stmt["Resource"].([]interface{}); !ok || len(arns) != 2 {
+		t.Errorf("expected 2 Resource entries, got %v", stmt["Resource"])
+	}
+}
@@ -3,10 +3,17 @@ package sam
 
 import (
 	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/lex00/aws-sam-translator-go/pkg/intrinsics"
 	"github.com/lex00/aws-sam-translator-go/pkg/model/iam"
 	"github.com/lex00/aws-sam-translator-go/pkg/model/lambda"
+	"github.com/lex00/aws-sam-translator-go/pkg/policy"
+	"github.com/lex00/aws-sam-translator-go/pkg/utils"
 )
 
 // Function represents an AWS::Serverless::Function resource.
@@ -32,10 +39,12 @@ type Function struct {
 	Description string `json:"Description,omitempty" yaml:"Description,omitempty"`
 
 	// MemorySize is the amount of memory available to the function (MB).
-	MemorySize int `json:"MemorySize,omitempty" yaml:"MemorySize,omitempty"`
+	// It is an integer or an intrinsic function such as a Ref to a parameter.
+	MemorySize interface{} `json:"MemorySize,omitempty" yaml:"MemorySize,omitempty"`
 
 	// Timeout is the amount of time Lambda allows a function to run (seconds).
-	Timeout int `json:"Timeout,omitempty" yaml:"Timeout,omitempty"`
+	// It is an integer or an intrinsic function such as a Ref to a parameter.
+	Timeout interface{} `json:"Timeout,omitempty" yaml:"Timeout,omitempty"`
 
 	// Role is the ARN of the function's execution role.
 	// If not specified, SAM creates a role automatically.
@@ -78,13 +87,20 @@ type Function struct {
 	// ProvisionedConcurrencyConfig specifies provisioned concurrency settings.
 	ProvisionedConcurrencyConfig map[string]interface{} `json:"ProvisionedConcurrencyConfig,omitempty" yaml:"ProvisionedConcurrencyConfig,omitempty"`
 
+	// ProvisionedConcurrencyAutoScaling configures Application Auto Scaling of the
+	// alias's provisioned concurrency. Keys: MinCapacity, MaxCapacity, TargetUtilization.
+	// Requires AutoPublishAlias.
+	ProvisionedConcurrencyAutoScaling map[string]interface{} `json:"ProvisionedConcurrencyAutoScaling,omitempty" yaml:"ProvisionedConcurrencyAutoScaling,omitempty"`
+
 	// ReservedConcurrentExecutions is the number of reserved concurrent executions.
-	ReservedConcurrentExecutions *int `json:"ReservedConcurrentExecutions,omitempty" yaml:"ReservedConcurrentExecutions,omitempty"`
+	// It is a non-negative integer or an intrinsic function.
+	ReservedConcurrentExecutions interface{} `json:"ReservedConcurrentExecutions,omitempty" yaml:"ReservedConcurrentExecutions,omitempty"`
 
 	// Tracing configures AWS X-Ray tracing. Valid values: Active, PassThrough.
 	Tracing string `json:"Tracing,omitempty" yaml:"Tracing,omitempty"`
 
 	// DeadLetterQueue configures the dead letter queue for failed invocations.
+	// Without a TargetArn, an SQS queue or SNS topic is created for it.
 	DeadLetterQueue map[string]interface{} `json:"DeadLetterQueue,omitempty" yaml:"DeadLetterQueue,omitempty"`
 
 	// KmsKeyArn is the ARN of the KMS key used to encrypt environment variables.
@@ -97,6 +113,7 @@ type Function struct {
 	SnapStart map[string]interface{} `json:"SnapStart,omitempty" yaml:"SnapStart,omitempty"`
 
 	// FileSystemConfigs connects the function to an Amazon EFS file system.
+	// Lambda can only mount EFS from within a VPC, so VpcConfig must also be set.
 	FileSystemConfigs []map[string]interface{} `json:"FileSystemConfigs,omitempty" yaml:"FileSystemConfigs,omitempty"`
 
 	// ImageConfig overrides the container image settings.
@@ -111,10 +128,15 @@ type Function struct {
 	// PermissionsBoundary is the ARN of a permissions boundary policy.
 	PermissionsBoundary interface{} `json:"PermissionsBoundary,omitempty" yaml:"PermissionsBoundary,omitempty"`
 
+	// RolePath is the path for the generated IAM role.
+	RolePath string `json:"RolePath,omitempty" yaml:"RolePath,omitempty"`
+
 	// FunctionUrlConfig configures a Lambda function URL.
 	FunctionUrlConfig map[string]interface{} `json:"FunctionUrlConfig,omitempty" yaml:"FunctionUrlConfig,omitempty"`
 
-	// LoggingConfig configures CloudWatch logging settings.
+	// LoggingConfig configures CloudWatch logging settings. The SAM-only
+	// CreateLogGroup key generates an AWS::Logs::LogGroup named by LogGroup,
+	// with an optional RetentionInDays.
 	LoggingConfig map[string]interface{} `json:"LoggingConfig,omitempty" yaml:"LoggingConfig,omitempty"`
 
 	// RecursiveLoop sets loop detection behavior for recursive invocations.
@@ -146,7 +168,42 @@ type TransformContext struct {
 }
 
 // FunctionTransformer transforms AWS::Serverless::Function to CloudFormation.
-type FunctionTransformer struct{}
+type FunctionTransformer struct {
+	// ValidateSnapStartRuntime rejects SnapStart on runtimes that do not support it.
+	ValidateSnapStartRuntime bool
+
+	// DefaultRolePath is the path used for generated roles when a function sets no RolePath.
+	DefaultRolePath string
+
+	// AllowLocalCodeUri emits a placeholder Code location for local CodeUri paths,
+	// and passes local ImageUri names through, instead of rejecting a template
+	// that has not been packaged.
+	AllowLocalCodeUri bool
+
+	// UseInlineBasicExecutionPolicy replaces the AWSLambdaBasicExecutionRole
+	// managed policy on generated roles with an inline logging policy scoped
+	// to the function's log group. A function without FunctionName gets the
+	// policy as a separate <Id>BasicExecutionPolicy resource instead, since its
+	// log group name references the function.
+	UseInlineBasicExecutionPolicy bool
+
+	// StrictEvents rejects events with an unknown Type instead of skipping them.
+	StrictEvents bool
+
+	// OmitCreatedByTag leaves out the lambda:createdBy tag that SAM adds to
+	// functions and their generated roles.
+	OmitCreatedByTag bool
+}
+
+// LocalCodeUriPlaceholderBucket is the S3Bucket emitted for a local CodeUri when
+// AllowLocalCodeUri is set. The local path is emitted as the S3Key so packaging
+// tooling can replace both after uploading the artifact.
+const LocalCodeUriPlaceholderBucket = "__LOCAL_CODE_URI__"
+
+// policyTemplates expands SAM policy templates in function Policies. It is nil
+// if the embedded templates cannot be loaded, in which case templates are
+// passed through unexpanded.
+var policyTemplates, _ = policy.New()
 
 // NewFunctionTransformer creates a new FunctionTransformer.
 func NewFunctionTransformer() *FunctionTransformer {
@@ -157,6 +214,10 @@ func NewFunctionTransformer() *FunctionTransformer {
 func (t *FunctionTransformer) Transform(logicalID string, f *Function, ctx *TransformContext) (map[string]interface{}, error) {
 	resources := make(map[string]interface{})
 
+	if err := t.validate(logicalID, f); err != nil {
+		return nil, err
+	}
+
 	// Build the Lambda function properties
 	functionProps, err := t.buildFunctionProperties(logicalID, f)
 	if err != nil {
@@ -172,6 +233,14 @@ func (t *FunctionTransformer) Transform(logicalID string, f *Function, ctx *Tran
 	functionProps["Role"] = roleRef
 	if roleResource != nil {
 		resources[logicalID+"Role"] = roleResource
+
+		// The role cannot reference the function it is attached to, so logging
+		// to a log group named after the function is granted by a separate policy
+		if t.UseInlineBasicExecutionPolicy && logGroupNamedByFunction(f) {
+			resources[logicalID+"BasicExecutionPolicy"] = iam.NewPolicy(logicalID+"BasicExecutionPolicy", basicExecutionPolicy(logicalID, f)).
+				AttachToRole(intrinsics.NewRef(logicalID + "Role")).
+				ToResource()
+		}
 	}
 
 	// Build the function resource
@@ -192,6 +261,32 @@ func (t *FunctionTransformer) Transform(logicalID string, f *Function, ctx *Tran
 
 	resources[logicalID] = functionResource
 
+	// Build the log group if LoggingConfig requested its creation
+	if createsLogGroup(f) {
+		logGroupProps := map[string]interface{}{}
+		if name, ok := f.LoggingConfig["LogGroup"]; ok {
+			logGroupProps["LogGroupName"] = name
+		}
+		if retention, ok := f.LoggingConfig["RetentionInDays"]; ok {
+			logGroupProps["RetentionInDays"] = retention
+		}
+		resources[logicalID+"LogGroup"] = map[string]interface{}{
+			"Type":       "AWS::Logs::LogGroup",
+			"Properties": logGroupProps,
+		}
+	}
+
+	// Build the dead letter queue if DeadLetterQueue has no TargetArn.
+	// validateDeadLetterQueue rejects any other Type.
+	if createsDeadLetterQueue(f) {
+		dlqType, _ := f.DeadLetterQueue["Type"].(string)
+		if resourceType, ok := deadLetterQueueResourceTypes[dlqType]; ok {
+			resources[logicalID+"DeadLetterQueue"] = map[string]interface{}{
+				"Type": resourceType,
+			}
+		}
+	}
+
 	// Handle AutoPublishAlias (versioning)
 	if f.AutoPublishAlias != "" {
 		versionResources, err := t.buildVersionAndAlias(logicalID, f)
@@ -258,11 +353,11 @@ func (t *FunctionTransformer) buildFunctionProperties(logicalID string, f *Funct
 		props["Description"] = f.Description
 	}
 
-	if f.MemorySize > 0 {
+	if f.MemorySize != nil {
 		props["MemorySize"] = f.MemorySize
 	}
 
-	if f.Timeout > 0 {
+	if f.Timeout != nil {
 		props["Timeout"] = f.Timeout
 	}
 
@@ -275,17 +370,10 @@ func (t *FunctionTransformer) buildFunctionProperties(logicalID string, f *Funct
 	}
 
 	if f.Environment != nil {
-		props["Environment"] = f.Environment
+		props["Environment"] = normalizeEnvironment(f.Environment)
 	}
 
-	if len(f.Tags) > 0 {
-		tags := make([]interface{}, 0, len(f.Tags))
-		for k, v := range f.Tags {
-			tags = append(tags, map[string]interface{}{
-				"Key":   k,
-				"Value": v,
-			})
-		}
+	if tags := t.functionTags(f); len(tags) > 0 {
 		props["Tags"] = tags
 	}
 
@@ -298,7 +386,7 @@ func (t *FunctionTransformer) buildFunctionProperties(logicalID string, f *Funct
 	}
 
 	if f.ReservedConcurrentExecutions != nil {
-		props["ReservedConcurrentExecutions"] = *f.ReservedConcurrentExecutions
+		props["ReservedConcurrentExecutions"] = f.ReservedConcurrentExecutions
 	}
 
 	if f.Tracing != "" {
@@ -308,7 +396,9 @@ func (t *FunctionTransformer) buildFunctionProperties(logicalID string, f *Funct
 	}
 
 	if f.DeadLetterQueue != nil {
-		props["DeadLetterConfig"] = f.DeadLetterQueue
+		props["DeadLetterConfig"] = map[string]interface{}{
+			"TargetArn": deadLetterQueueArn(logicalID, f),
+		}
 	}
 
 	if f.KmsKeyArn != nil {
@@ -340,7 +430,7 @@ func (t *FunctionTransformer) buildFunctionProperties(logicalID string, f *Funct
 	}
 
 	if f.LoggingConfig != nil {
-		props["LoggingConfig"] = f.LoggingConfig
+		props["LoggingConfig"] = t.buildLoggingConfig(logicalID, f)
 	}
 
 	if f.RecursiveLoop != "" {
@@ -350,11 +440,49 @@ func (t *FunctionTransformer) buildFunctionProperties(logicalID string, f *Funct
 	return props, nil
 }
 
+// normalizeEnvironment converts scalar non-string environment variable
+// values to strings, as Lambda requires. Intrinsic functions are left untouched.
+func normalizeEnvironment(env map[string]interface{}) map[string]interface{} {
+	variables, ok := env["Variables"].(map[string]interface{})
+	if !ok {
+		return env
+	}
+
+	normalized := make(map[string]interface{}, len(variables))
+	for name, value := range variables {
+		switch v := value.(type) {
+		case bool:
+			normalized[name] = strconv.FormatBool(v)
+		case int:
+			normalized[name] = strconv.Itoa(v)
+		case int64:
+			normalized[name] = strconv.FormatInt(v, 10)
+		case uint64:
+			normalized[name] = strconv.FormatUint(v, 10)
+		case float64:
+			normalized[name] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			normalized[name] = value
+		}
+	}
+
+	result := make(map[string]interface{}, len(env))
+	for k, v := range env {
+		result[k] = v
+	}
+	result["Variables"] = normalized
+	return result
+}
+
 // buildCodeConfig builds the Code property from CodeUri or ImageUri.
 func (t *FunctionTransformer) buildCodeConfig(f *Function) (map[string]interface{}, error) {
 	code := make(map[string]interface{})
 
 	if f.ImageUri != nil {
+		// Local image names are only valid before the image is pushed to ECR
+		if uri, ok := f.ImageUri.(string); ok && isLocalImageUri(uri) && !t.AllowLocalCodeUri {
+			return nil, fmt.Errorf("ImageUri '%s' is a local image; push it to ECR and reference the repository URI (e.g. sam package) or enable AllowLocalCodeUri", uri)
+		}
 		code["ImageUri"] = f.ImageUri
 		return code, nil
 	}
@@ -377,8 +505,12 @@ func (t *FunctionTransformer) buildCodeConfig(f *Function) (map[string]interface
 				code["S3ObjectVersion"] = version
 			}
 		} else {
-			// Local path - keep as-is for packaging
-			code["S3Bucket"] = v
+			// Local path - the template has not been packaged yet
+			if !t.AllowLocalCodeUri {
+				return nil, fmt.Errorf("CodeUri '%s' is a local path; package the template (e.g. sam package) before translating or enable AllowLocalCodeUri", v)
+			}
+			code["S3Bucket"] = LocalCodeUriPlaceholderBucket
+			code["S3Key"] = v
 		}
 	case map[string]interface{}:
 		if bucket, ok := v["Bucket"]; ok {
@@ -408,6 +540,18 @@ func (t *FunctionTransformer) buildCodeConfig(f *Function) (map[string]interface
 	return code, nil
 }
 
+// isLocalImageUri reports whether uri names a local image rather than one in a registry.
+// Following Docker's convention, a reference is registry-qualified when its first
+// path component contains a '.' or ':' (e.g. 123456789012.dkr.ecr.us-east-1.amazonaws.com/repo:tag).
+func isLocalImageUri(uri string) bool {
+	slash := strings.Index(uri, "/")
+	if slash < 0 {
+		return true
+	}
+	host := uri[:slash]
+	return !strings.ContainsAny(host, ".:") && host != "localhost"
+}
+
 // parseS3Uri parses an S3 URI string (s3://bucket/key) into components.
 func parseS3Uri(uri string) (map[string]interface{}, error) {
 	if !strings.HasPrefix(uri, "s3://") {
@@ -426,9 +570,42 @@ func parseS3Uri(uri string) (map[string]interface{}, error) {
 	}, nil
 }
 
+// CreatedByTagKey and CreatedByTagValue are the tag SAM adds to functions and
+// their generated roles.
+const (
+	CreatedByTagKey   = "lambda:createdBy"
+	CreatedByTagValue = "SAM"
+)
+
+// functionTags returns the Tags list shared by a function and its generated
+// role: the lambda:createdBy tag unless OmitCreatedByTag is set, followed by
+// the function's tags in key order. A function tag with the createdBy key
+// replaces the automatic one.
+func (t *FunctionTransformer) functionTags(f *Function) []interface{} {
+	var tags []interface{}
+	if _, overridden := f.Tags[CreatedByTagKey]; !t.OmitCreatedByTag && !overridden {
+		tags = append(tags, map[string]interface{}{"Key": CreatedByTagKey, "Value": CreatedByTagValue})
+	}
+
+	keys := make([]string, 0, len(f.Tags))
+	for key := range f.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		tags = append(tags, map[string]interface{}{"Key": key, "Value": f.Tags[key]})
+	}
+	return tags
+}
+
 // buildRole builds the IAM role for the function.
 func (t *FunctionTransformer) buildRole(logicalID string, f *Function) (interface{}, map[string]interface{}, error) {
-	// If Role is explicitly provided, use it
+	if err := validatePermissionsBoundary(f.PermissionsBoundary); err != nil {
+		return nil, nil, err
+	}
+
+	// If Role is explicitly provided, use it; RolePath and PermissionsBoundary
+	// only apply to the generated role
 	if f.Role != nil {
 		return f.Role, nil, nil
 	}
@@ -437,9 +614,15 @@ func (t *FunctionTransformer) buildRole(logicalID string, f *Function) (interfac
 	trustPolicy := iam.NewAssumeRolePolicyForService(iam.ServiceLambda)
 	role := iam.NewRole(trustPolicy)
 
-	// Add basic execution role policy
-	managedPolicies := []interface{}{
-		"arn:aws:iam::aws:policy/service-role/AWSLambdaBasicExecutionRole",
+	// Add basic execution role policy, or its inline equivalent
+	var managedPolicies []interface{}
+	if t.UseInlineBasicExecutionPolicy || createsLogGroup(f) {
+		if !logGroupNamedByFunction(f) {
+			role.AddInlinePolicy(logicalID+"BasicExecutionPolicy", basicExecutionPolicy(logicalID, f))
+		}
+	} else {
+		managedPolicies = append(managedPolicies,
+			"arn:aws:iam::aws:policy/service-role/AWSLambdaBasicExecutionRole")
 	}
 
 	// Add VPC access policy if VPC is configured
@@ -464,6 +647,52 @@ func (t *FunctionTransformer) buildRole(logicalID string, f *Function) (interfac
 		role.Policies = append(role.Policies, inlinePolicies...)
 	}
 
+	// Grant delivery of failed events to the dead letter queue
+	if policy := deadLetterQueuePolicy(logicalID, f); policy != nil {
+		role.AddInlinePolicy("DeadLetterQueuePolicy", policy)
+	}
+
+	// Grant decrypt on the key used to encrypt environment variables
+	if f.KmsKeyArn != nil {
+		role.AddInlinePolicy(logicalID+"KmsDecryptPolicy", iam.KMSDecryptPolicy(f.KmsKeyArn))
+	}
+
+	// Grant decrypt on the keys used to encrypt event source mapping filter criteria
+	for _, eventName := range sortedEventNames(f.Events) {
+		eventMap, _ := f.Events[eventName].(map[string]interface{})
+		eventType, _ := eventMap["Type"].(string)
+		eventProps, _ := eventMap["Properties"].(map[string]interface{})
+		if !filterCriteriaEventTypes[eventType] {
+			continue
+		}
+		if keyArn, ok := filterCriteriaKmsKeyArn(eventProps); ok {
+			role.AddInlinePolicy(logicalID+eventName+"FilterCriteriaKmsDecryptPolicy", iam.KMSDecryptPolicy(keyArn))
+		}
+	}
+
+	// Grant EFS client access on each mounted access point
+	if len(f.FileSystemConfigs) > 0 {
+		var accessPoints []interface{}
+		for _, fsc := range f.FileSystemConfigs {
+			if arn, ok := fsc["Arn"]; ok {
+				accessPoints = append(accessPoints, arn)
+			}
+		}
+		if len(accessPoints) > 0 {
+			stmt := iam.NewStatement(iam.EffectAllow).
+				WithActions("elasticfilesystem:ClientMount", "elasticfilesystem:ClientWrite").
+				WithResources(accessPoints...)
+			role.AddInlinePolicy(logicalID+"EfsClientPolicy", iam.NewPolicyDocument().AddStatement(stmt))
+		}
+	}
+
+	// Set role path
+	if f.RolePath != "" {
+		role.WithPath(f.RolePath)
+	} else if t.DefaultRolePath != "" {
+		role.WithPath(t.DefaultRolePath)
+	}
+
 	// Set permissions boundary
 	if f.PermissionsBoundary != nil {
 		role.PermissionsBoundary = f.PermissionsBoundary
@@ -471,7 +700,12 @@ func (t *FunctionTransformer) buildRole(logicalID string, f *Function) (interfac
 
 	// Build role properties
 	roleProps := role.ToCloudFormation()
-	roleProps["ManagedPolicyArns"] = managedPolicies
+	if len(managedPolicies) > 0 {
+		roleProps["ManagedPolicyArns"] = managedPolicies
+	}
+	if tags := t.functionTags(f); len(tags) > 0 {
+		roleProps["Tags"] = tags
+	}
 
 	roleResource := map[string]interface{}{
 		"Type":       "AWS::IAM::Role",
@@ -486,62 +720,150 @@ func (t *FunctionTransformer) buildRole(logicalID string, f *Function) (interfac
 	return roleRef, roleResource, nil
 }
 
+// buildLoggingConfig returns the function's LoggingConfig without the SAM-only
+// keys, pointing LogGroup at the generated log group when one is created.
+func (t *FunctionTransformer) buildLoggingConfig(logicalID string, f *Function) map[string]interface{} {
+	config := make(map[string]interface{}, len(f.LoggingConfig))
+	for k, v := range f.LoggingConfig {
+		if k == "CreateLogGroup" || k == "RetentionInDays" {
+			continue
+		}
+		config[k] = v
+	}
+	if createsLogGroup(f) {
+		config["LogGroup"] = intrinsics.NewRef(logicalID + "LogGroup")
+	}
+	return config
+}
+
+// createsLogGroup reports whether the function's LoggingConfig requests a
+// generated log group.
+func createsLogGroup(f *Function) bool {
+	create, _ := f.LoggingConfig["CreateLogGroup"].(bool)
+	return create
+}
+
+// basicExecutionPolicy returns the inline equivalent of the
+// AWSLambdaBasicExecutionRole managed policy, scoped to the function's log group.
+func basicExecutionPolicy(logicalID string, f *Function) *iam.PolicyDocument {
+	stmt := iam.NewStatement(iam.EffectAllow).
+		WithActions("logs:CreateLogGroup", "logs:CreateLogStream", "logs:PutLogEvents").
+		WithResources(functionLogGroupArn(logicalID, f))
+	return iam.NewPolicyDocument().AddStatement(stmt)
+}
+
+// logGroupNamedByFunction reports whether the function logs to the default
+// /aws/lambda/<name> log group under the name CloudFormation generates for it,
+// so its ARN references the function.
+func logGroupNamedByFunction(f *Function) bool {
+	_, hasLogGroup := f.LoggingConfig["LogGroup"]
+	return !createsLogGroup(f) && !hasLogGroup && f.FunctionName == nil
+}
+
+// functionLogGroupArn returns the ARN pattern covering the function's log
+// group and its log streams: the generated or configured log group, or
+// /aws/lambda/ followed by the FunctionName or, without one, the function's Ref.
+func functionLogGroupArn(logicalID string, f *Function) interface{} {
+	if createsLogGroup(f) {
+		// The log group Arn attribute already ends in :*
+		return intrinsics.NewGetAtt(logicalID+"LogGroup", "Arn")
+	}
+
+	if name, ok := f.LoggingConfig["LogGroup"]; ok {
+		return intrinsics.NewSub("arn:${AWS::Partition}:logs:${AWS::Region}:${AWS::AccountId}:log-group:${LogGroup}:*", "LogGroup", name)
+	}
+
+	const logGroupArn = "arn:${AWS::Partition}:logs:${AWS::Region}:${AWS::AccountId}:log-group:/aws/lambda/${FunctionName}:*"
+	if f.FunctionName != nil {
+		return intrinsics.NewSub(logGroupArn, "FunctionName", f.FunctionName)
+	}
+	return intrinsics.NewSub(logGroupArn, "FunctionName", "${"+logicalID+"}")
+}
+
+// deadLetterQueueResourceTypes maps DeadLetterQueue types to the resource
+// generated when no TargetArn is given.
+var deadLetterQueueResourceTypes = map[string]string{
+	"SNS": "AWS::SNS::Topic",
+	"SQS": "AWS::SQS::Queue",
+}
+
+// createsDeadLetterQueue reports whether the function's DeadLetterQueue omits
+// TargetArn, so a queue or topic is generated for it.
+func createsDeadLetterQueue(f *Function) bool {
+	if f.DeadLetterQueue == nil {
+		return false
+	}
+	_, hasTarget := f.DeadLetterQueue["TargetArn"]
+	return !hasTarget
+}
+
+// deadLetterQueueArn returns the ARN of the function's dead letter queue,
+// referencing the generated <logicalID>DeadLetterQueue when there is no TargetArn.
+func deadLetterQueueArn(logicalID string, f *Function) interface{} {
+	if !createsDeadLetterQueue(f) {
+		return f.DeadLetterQueue["TargetArn"]
+	}
+	// Ref on a topic returns its ARN, while a queue only exposes it as an attribute
+	if f.DeadLetterQueue["Type"] == "SNS" {
+		return intrinsics.NewRef(logicalID + "DeadLetterQueue")
+	}
+	return intrinsics.NewGetAtt(logicalID+"DeadLetterQueue", "Arn")
+}
+
+// deadLetterQueuePolicy grants the function permission to send failed events
+// to its dead letter queue. It returns nil when the queue type is not given.
+func deadLetterQueuePolicy(logicalID string, f *Function) *iam.PolicyDocument {
+	var action string
+	switch f.DeadLetterQueue["Type"] {
+	case "SNS":
+		action = "sns:Publish"
+	case "SQS":
+		action = "sqs:SendMessage"
+	default:
+		return nil
+	}
+	stmt := iam.NewStatement(iam.EffectAllow).
+		WithActions(action).
+		WithResources(deadLetterQueueArn(logicalID, f))
+	return iam.NewPolicyDocument().AddStatement(stmt)
+}
+
 // processPolicies processes the Policies property and returns managed policy ARNs and inline policies.
+// Each entry of a Policies list is dispatched on its own: a string is a managed
+// policy ARN, a map with a Statement is an inline policy document, and a
+// single-key map naming a known SAM policy template is expanded to an inline
+// policy. Anything else, such as an intrinsic, is passed through as a managed
+// policy. Inline policies are named <Id>RolePolicy<index> after their
+// position in the list.
 func (t *FunctionTransformer) processPolicies(logicalID string, policies interface{}) ([]interface{}, []iam.InlinePolicy, error) {
 	var managedPolicies []interface{}
 	var inlinePolicies []iam.InlinePolicy
 
+	var entries []interface{}
 	switch p := policies.(type) {
-	case string:
-		// Single managed policy ARN
-		managedPolicies = append(managedPolicies, p)
-
+	case string, map[string]interface{}:
+		entries = []interface{}{p}
 	case []interface{}:
-		// Array of policies
-		for _, item := range p {
-			switch v := item.(type) {
-			case string:
-				managedPolicies = append(managedPolicies, v)
-			case map[string]interface{}:
-				// Could be an inline policy or a SAM policy template
-				if _, hasStatement := v["Statement"]; hasStatement {
-					// Inline policy document
-					doc := iam.NewPolicyDocument()
-					if statements, ok := v["Statement"].([]interface{}); ok {
-						for _, stmt := range statements {
-							if stmtMap, ok := stmt.(map[string]interface{}); ok {
-								statement := t.mapToStatement(stmtMap)
-								doc.AddStatement(statement)
-							}
-						}
-					}
-					inlinePolicies = append(inlinePolicies, iam.InlinePolicy{
-						PolicyName:     logicalID + "Policy",
-						PolicyDocument: doc,
-					})
-				} else {
-					// Treat as SAM policy template (already expanded by plugin)
-					// or unknown format - add as-is
-					managedPolicies = append(managedPolicies, v)
-				}
-			}
-		}
+		entries = p
+	}
 
-	case map[string]interface{}:
-		// Single inline policy document
-		if _, hasStatement := p["Statement"]; hasStatement {
-			doc := iam.NewPolicyDocument()
-			if statements, ok := p["Statement"].([]interface{}); ok {
-				for _, stmt := range statements {
-					if stmtMap, ok := stmt.(map[string]interface{}); ok {
-						statement := t.mapToStatement(stmtMap)
-						doc.AddStatement(statement)
-					}
-				}
+	for i, entry := range entries {
+		switch v := entry.(type) {
+		case string:
+			managedPolicies = append(managedPolicies, v)
+		case map[string]interface{}:
+			document, err := t.policyDocument(v)
+			if err != nil {
+				return nil, nil, err
+			}
+			if document == nil {
+				// Intrinsic or unknown format - add as-is
+				managedPolicies = append(managedPolicies, v)
+				continue
 			}
 			inlinePolicies = append(inlinePolicies, iam.InlinePolicy{
-				PolicyName:     logicalID + "Policy",
-				PolicyDocument: doc,
+				PolicyName:     fmt.Sprintf("%sRolePolicy%d", logicalID, i),
+				PolicyDocument: document,
 			})
 		}
 	}
@@ -549,19 +871,82 @@ func (t *FunctionTransformer) processPolicies(logicalID string, policies interfa
 	return managedPolicies, inlinePolicies, nil
 }
 
+// policyDocument returns the inline policy document for a Policies entry: the
+// entry itself when it has a Statement, or the expansion of a SAM policy
+// template. It returns nil for any other map.
+func (t *FunctionTransformer) policyDocument(entry map[string]interface{}) (*iam.PolicyDocument, error) {
+	if _, hasStatement := entry["Statement"]; !hasStatement {
+		expanded, err := t.expandPolicyTemplate(entry)
+		if err != nil || expanded == nil {
+			return nil, err
+		}
+		entry = expanded
+	}
+
+	doc := iam.NewPolicyDocument()
+	if statements, ok := entry["Statement"].([]interface{}); ok {
+		for _, stmt := range statements {
+			if stmtMap, ok := stmt.(map[string]interface{}); ok {
+				doc.AddStatement(t.mapToStatement(stmtMap))
+			}
+		}
+	}
+	return doc, nil
+}
+
+// expandPolicyTemplate expands entry when it is a single-key map naming a
+// known SAM policy template, and returns nil otherwise. Templates are usually
+// expanded by the policy templates plugin before the transform, so this only
+// applies when the transformer is used on its own.
+func (t *FunctionTransformer) expandPolicyTemplate(entry map[string]interface{}) (map[string]interface{}, error) {
+	if len(entry) != 1 || policyTemplates == nil {
+		return nil, nil
+	}
+	for name, params := range entry {
+		if !policyTemplates.HasTemplate(name) {
+			return nil, nil
+		}
+		paramMap, ok := params.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("policy template %s parameters must be a map", name)
+		}
+		expanded, err := policyTemplates.Expand(name, paramMap)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand policy template %s: %w", name, err)
+		}
+		return expanded, nil
+	}
+	return nil, nil
+}
+
 // mapToStatement converts a map to an IAM Statement.
+// The statement is deep-copied so nested intrinsics in Action, Resource,
+// Principal and Condition round-trip unchanged and are not shared with the input.
 func (t *FunctionTransformer) mapToStatement(m map[string]interface{}) *iam.Statement {
+	m = utils.DeepCopy(m)
 	stmt := iam.NewStatement(iam.EffectAllow)
 
 	if effect, ok := m["Effect"].(string); ok {
 		stmt.Effect = effect
 	}
+	if principal, ok := m["Principal"]; ok {
+		stmt.Principal = principal
+	}
+	if notPrincipal, ok := m["NotPrincipal"]; ok {
+		stmt.NotPrincipal = notPrincipal
+	}
 	if action, ok := m["Action"]; ok {
 		stmt.Action = action
 	}
+	if notAction, ok := m["NotAction"]; ok {
+		stmt.NotAction = notAction
+	}
 	if resource, ok := m["Resource"]; ok {
 		stmt.Resource = resource
 	}
+	if notResource, ok := m["NotResource"]; ok {
+		stmt.NotResource = notResource
+	}
 	if condition, ok := m["Condition"].(map[string]interface{}); ok {
 		stmt.Condition = condition
 	}
@@ -611,27 +996,94 @@ func (t *FunctionTransformer) buildVersionAndAlias(logicalID string, f *Function
 	}
 	resources[aliasID] = aliasResource
 
+	// Add auto scaling of provisioned concurrency if specified
+	if f.ProvisionedConcurrencyAutoScaling != nil {
+		for k, v := range t.buildProvisionedConcurrencyScaling(logicalID, aliasID, f) {
+			resources[k] = v
+		}
+	}
+
 	return resources, nil
 }
 
+// buildProvisionedConcurrencyScaling builds the Application Auto Scaling target and
+// target tracking policy for the alias's provisioned concurrency.
+func (t *FunctionTransformer) buildProvisionedConcurrencyScaling(logicalID, aliasID string, f *Function) map[string]interface{} {
+	config := f.ProvisionedConcurrencyAutoScaling
+	minCapacity := config["MinCapacity"]
+	maxCapacity := config["MaxCapacity"]
+
+	targetUtilization, ok := config["TargetUtilization"]
+	if !ok {
+		targetUtilization = 0.7
+	}
+
+	targetID := aliasID + "ScalableTarget"
+	policyID := aliasID + "ScalingPolicy"
+
+	target := map[string]interface{}{
+		"Type": "AWS::ApplicationAutoScaling::ScalableTarget",
+		"Properties": map[string]interface{}{
+			"MinCapacity": minCapacity,
+			"MaxCapacity": maxCapacity,
+			"ResourceId": map[string]interface{}{
+				"Fn::Join": []interface{}{":", []interface{}{
+					"function",
+					map[string]interface{}{"Ref": logicalID},
+					f.AutoPublishAlias,
+				}},
+			},
+			"ScalableDimension": "lambda:function:ProvisionedConcurrency",
+			"ServiceNamespace":  "lambda",
+		},
+		"DependsOn": aliasID,
+	}
+
+	policy := map[string]interface{}{
+		"Type": "AWS::ApplicationAutoScaling::ScalingPolicy",
+		"Properties": map[string]interface{}{
+			"PolicyName":      policyID,
+			"PolicyType":      "TargetTrackingScaling",
+			"ScalingTargetId": map[string]interface{}{"Ref": targetID},
+			"TargetTrackingScalingPolicyConfiguration": map[string]interface{}{
+				"TargetValue": targetUtilization,
+				"PredefinedMetricSpecification": map[string]interface{}{
+					"PredefinedMetricType": "LambdaProvisionedConcurrencyUtilization",
+				},
+			},
+		},
+	}
+
+	return map[string]interface{}{
+		targetID: target,
+		policyID: policy,
+	}
+}
+
+// sortedEventNames returns the event names in sorted order.
+func sortedEventNames(events map[string]interface{}) []string {
+	eventNames := make([]string, 0, len(events))
+	for eventName := range events {
+		eventNames = append(eventNames, eventName)
+	}
+	sort.Strings(eventNames)
+	return eventNames
+}
+
 // buildEventResources creates resources for function event sources.
 func (t *FunctionTransformer) buildEventResources(logicalID string, f *Function) (map[string]interface{}, error) {
 	resources := make(map[string]interface{})
 
-	// Reference to the function (or alias if AutoPublishAlias is set)
-	var functionRef interface{}
-	if f.AutoPublishAlias != "" {
-		functionRef = map[string]interface{}{
-			"Ref": logicalID + "Alias" + f.AutoPublishAlias,
-		}
-	} else {
-		functionRef = map[string]interface{}{
-			"Fn::GetAtt": []string{logicalID, "Arn"},
-		}
-	}
+	functionRef := eventFunctionRef(logicalID, f)
+
+	// Process events in sorted order so collision errors are deterministic
+	eventNames := sortedEventNames(f.Events)
 
-	for eventName, eventConfig := range f.Events {
-		eventMap, ok := eventConfig.(map[string]interface{})
+	// owners records which event generated each resource
+	owners := make(map[string]string)
+
+	for _, eventName := range eventNames {
+		eventMap, ok := f.Events[eventName].(map[string]interface{})
 		if !ok {
 			continue
 		}
@@ -645,54 +1097,200 @@ func (t *FunctionTransformer) buildEventResources(logicalID string, f *Function)
 		}
 
 		for k, v := range eventResources {
+			if owner, exists := owners[k]; exists {
+				return nil, fmt.Errorf("events '%s' and '%s' both generate resource '%s'; rename one of the events", owner, eventName, k)
+			}
+			owners[k] = eventName
 			resources[k] = v
 		}
 	}
 
+	for k, v := range t.buildCognitoPermissions(logicalID, f, functionRef) {
+		if owner, exists := owners[k]; exists {
+			return nil, fmt.Errorf("event '%s' and the Cognito permission both generate resource '%s'; rename the event", owner, k)
+		}
+		resources[k] = v
+	}
+
 	return resources, nil
 }
 
+// eventFunctionRef returns the reference event sources invoke: the alias when
+// AutoPublishAlias is set, otherwise the function ARN.
+func eventFunctionRef(logicalID string, f *Function) interface{} {
+	if f.AutoPublishAlias != "" {
+		return map[string]interface{}{
+			"Ref": logicalID + "Alias" + f.AutoPublishAlias,
+		}
+	}
+	return map[string]interface{}{
+		"Fn::GetAtt": []string{logicalID, "Arn"},
+	}
+}
+
+// S3Notification is a Lambda notification that an S3 event adds to a bucket
+// defined elsewhere in the template.
+type S3Notification struct {
+	// FunctionID is the logical ID of the function.
+	FunctionID string
+
+	// EventName is the name of the function event.
+	EventName string
+
+	// Bucket is the event's Bucket property.
+	Bucket interface{}
+
+	// PermissionID is the logical ID of the Lambda permission the bucket must depend on.
+	PermissionID string
+
+	// Configurations are the LambdaConfigurations entries to add to the bucket.
+	Configurations []interface{}
+}
+
+// S3Notifications returns the bucket notifications required by the function's
+// S3 events, in event name order. Buckets are shared across functions, so the
+// caller applies them once every function has been transformed.
+func S3Notifications(logicalID string, f *Function) []S3Notification {
+	eventNames := make([]string, 0, len(f.Events))
+	for eventName := range f.Events {
+		eventNames = append(eventNames, eventName)
+	}
+	sort.Strings(eventNames)
+
+	functionRef := eventFunctionRef(logicalID, f)
+
+	var notifications []S3Notification
+	for _, eventName := range eventNames {
+		event, ok := f.Events[eventName].(map[string]interface{})
+		if !ok || event["Type"] != "S3" {
+			continue
+		}
+		props, _ := event["Properties"].(map[string]interface{})
+
+		var s3Events []interface{}
+		switch v := props["Events"].(type) {
+		case []interface{}:
+			s3Events = v
+		case nil:
+		default:
+			s3Events = []interface{}{v}
+		}
+
+		configurations := make([]interface{}, 0, len(s3Events))
+		for _, s3Event := range s3Events {
+			configuration := map[string]interface{}{
+				"Event":    s3Event,
+				"Function": functionRef,
+			}
+			if filter, ok := props["Filter"]; ok {
+				configuration["Filter"] = filter
+			}
+			configurations = append(configurations, configuration)
+		}
+
+		notifications = append(notifications, S3Notification{
+			FunctionID:     logicalID,
+			EventName:      eventName,
+			Bucket:         props["Bucket"],
+			PermissionID:   logicalID + eventName + "Permission",
+			Configurations: configurations,
+		})
+	}
+	return notifications
+}
+
+// CognitoTrigger is a set of LambdaConfig triggers that a Cognito event adds to
+// a user pool defined elsewhere in the template.
+type CognitoTrigger struct {
+	// FunctionID is the logical ID of the function.
+	FunctionID string
+
+	// EventName is the name of the function event.
+	EventName string
+
+	// UserPool is the event's UserPool property.
+	UserPool interface{}
+
+	// Triggers are the LambdaConfig trigger names the event sets.
+	Triggers []string
+
+	// FunctionArn is the function or alias ARN each trigger invokes.
+	FunctionArn interface{}
+}
+
+// CognitoTriggers returns the user pool triggers set by the function's Cognito
+// events, in event name order. Events with an invalid Trigger are skipped, as
+// Transform rejects them.
+func CognitoTriggers(logicalID string, f *Function) []CognitoTrigger {
+	functionRef := eventFunctionRef(logicalID, f)
+
+	var result []CognitoTrigger
+	for _, eventName := range sortedEventNames(f.Events) {
+		event, ok := f.Events[eventName].(map[string]interface{})
+		if !ok || event["Type"] != "Cognito" {
+			continue
+		}
+		props, _ := event["Properties"].(map[string]interface{})
+		triggers, err := cognitoEventTriggers(props)
+		if err != nil {
+			continue
+		}
+		result = append(result, CognitoTrigger{
+			FunctionID:  logicalID,
+			EventName:   eventName,
+			UserPool:    props["UserPool"],
+			Triggers:    triggers,
+			FunctionArn: functionRef,
+		})
+	}
+	return result
+}
+
 // buildEventSource creates resources for a single event source.
 func (t *FunctionTransformer) buildEventSource(logicalID, eventName, eventType string, props map[string]interface{}, functionRef interface{}) (map[string]interface{}, error) {
-	resources := make(map[string]interface{})
-
-	switch eventType {
-	case "S3":
-		return t.buildS3Event(logicalID, eventName, props, functionRef)
-	case "SQS":
-		return t.buildSQSEvent(logicalID, eventName, props, functionRef)
-	case "Kinesis":
-		return t.buildKinesisEvent(logicalID, eventName, props, functionRef)
-	case "DynamoDB":
-		return t.buildDynamoDBEvent(logicalID, eventName, props, functionRef)
-	case "Api":
-		return t.buildApiEvent(logicalID, eventName, props, functionRef)
-	case "HttpApi":
-		return t.buildHttpApiEvent(logicalID, eventName, props, functionRef)
-	case "Schedule":
-		return t.buildScheduleEvent(logicalID, eventName, props, functionRef)
-	case "CloudWatchEvent", "EventBridgeRule":
-		return t.buildCloudWatchEvent(logicalID, eventName, props, functionRef)
-	case "SNS":
-		return t.buildSNSEvent(logicalID, eventName, props, functionRef)
-	case "IoTRule":
-		return t.buildIoTRuleEvent(logicalID, eventName, props, functionRef)
-	case "Cognito":
-		return t.buildCognitoEvent(logicalID, eventName, props, functionRef)
-	case "MSK":
-		return t.buildMSKEvent(logicalID, eventName, props, functionRef)
-	case "MQ":
-		return t.buildMQEvent(logicalID, eventName, props, functionRef)
-	case "SelfManagedKafka":
-		return t.buildSelfManagedKafkaEvent(logicalID, eventName, props, functionRef)
-	case "CloudWatchLogs":
-		return t.buildCloudWatchLogsEvent(logicalID, eventName, props, functionRef)
-	case "AlexaSkill":
-		return t.buildAlexaSkillEvent(logicalID, eventName, props, functionRef)
-	default:
+	build, ok := eventSourceBuilders[eventType]
+	if !ok {
+		if t.StrictEvents {
+			return nil, fmt.Errorf("function '%s' event '%s' has unknown event type '%s'", logicalID, eventName, eventType)
+		}
 		// Unknown event type - skip
-		return resources, nil
+		return make(map[string]interface{}), nil
+	}
+	return build(t, logicalID, eventName, props, functionRef)
+}
+
+// eventSourceBuilder creates the resources for one function event.
+type eventSourceBuilder func(t *FunctionTransformer, logicalID, eventName string, props map[string]interface{}, functionRef interface{}) (map[string]interface{}, error)
+
+// eventSourceBuilders maps each supported function event type to its builder.
+var eventSourceBuilders = map[string]eventSourceBuilder{
+	"S3":               (*FunctionTransformer).buildS3Event,
+	"SQS":              (*FunctionTransformer).buildSQSEvent,
+	"Kinesis":          (*FunctionTransformer).buildKinesisEvent,
+	"DynamoDB":         (*FunctionTransformer).buildDynamoDBEvent,
+	"Api":              (*FunctionTransformer).buildApiEvent,
+	"HttpApi":          (*FunctionTransformer).buildHttpApiEvent,
+	"Schedule":         (*FunctionTransformer).buildScheduleEvent,
+	"CloudWatchEvent":  (*FunctionTransformer).buildCloudWatchEvent,
+	"EventBridgeRule":  (*FunctionTransformer).buildCloudWatchEvent,
+	"SNS":              (*FunctionTransformer).buildSNSEvent,
+	"IoTRule":          (*FunctionTransformer).buildIoTRuleEvent,
+	"Cognito":          (*FunctionTransformer).buildCognitoEvent,
+	"MSK":              (*FunctionTransformer).buildMSKEvent,
+	"MQ":               (*FunctionTransformer).buildMQEvent,
+	"SelfManagedKafka": (*FunctionTransformer).buildSelfManagedKafkaEvent,
+	"CloudWatchLogs":   (*FunctionTransformer).buildCloudWatchLogsEvent,
+	"AlexaSkill":       (*FunctionTransformer).buildAlexaSkillEvent,
+}
+
+// SupportedEventTypes returns the function event types the transformer handles, sorted.
+func SupportedEventTypes() []string {
+	eventTypes := make([]string, 0, len(eventSourceBuilders))
+	for eventType := range eventSourceBuilders {
+		eventTypes = append(eventTypes, eventType)
 	}
+	sort.Strings(eventTypes)
+	return eventTypes
 }
 
 // buildS3Event creates resources for an S3 event source.
@@ -707,7 +1305,10 @@ func (t *FunctionTransformer) buildS3Event(logicalID, eventName string, props ma
 		"Principal":     "s3.amazonaws.com",
 		"SourceAccount": map[string]interface{}{"Ref": "AWS::AccountId"},
 	}
-	if bucket, ok := props["Bucket"]; ok {
+	// A bucket in the template depends on this permission through its
+	// notification configuration, so only a bucket name can be used as the
+	// SourceArn without creating a circular dependency
+	if bucket, ok := props["Bucket"].(string); ok {
 		permissionProps["SourceArn"] = t.buildS3BucketArn(bucket)
 	}
 
@@ -721,31 +1322,10 @@ func (t *FunctionTransformer) buildS3Event(logicalID, eventName string, props ma
 
 // buildS3BucketArn creates an S3 bucket ARN from various input formats.
 func (t *FunctionTransformer) buildS3BucketArn(bucket interface{}) interface{} {
-	switch v := bucket.(type) {
-	case string:
-		if strings.HasPrefix(v, "arn:") {
-			return v
-		}
-		return fmt.Sprintf("arn:aws:s3:::%s", v)
-	case map[string]interface{}:
-		if _, hasRef := v["Ref"]; hasRef {
-			return map[string]interface{}{
-				"Fn::Sub": []interface{}{
-					"arn:aws:s3:::${Bucket}",
-					map[string]interface{}{"Bucket": v},
-				},
-			}
-		}
-		if getAtt, hasGetAtt := v["Fn::GetAtt"]; hasGetAtt {
-			return map[string]interface{}{
-				"Fn::Sub": []interface{}{
-					"arn:aws:s3:::${Bucket}",
-					map[string]interface{}{"Bucket": map[string]interface{}{"Fn::GetAtt": getAtt}},
-				},
-			}
-		}
+	if s, ok := bucket.(string); ok && strings.HasPrefix(s, "arn:") {
+		return s
 	}
-	return bucket
+	return intrinsics.NewSub("arn:aws:s3:::${Bucket}", "Bucket", bucket)
 }
 
 // buildSQSEvent creates resources for an SQS event source.
@@ -772,9 +1352,23 @@ func (t *FunctionTransformer) buildSQSEvent(logicalID, eventName string, props m
 	if filterCriteria, ok := props["FilterCriteria"]; ok {
 		esmProps["FilterCriteria"] = filterCriteria
 	}
-	if scalingConfig, ok := props["ScalingConfig"]; ok {
+	if keyArn, ok := filterCriteriaKmsKeyArn(props); ok {
+		esmProps["KmsKeyArn"] = keyArn
+	}
+	scalingConfig, err := sqsScalingConfig(props)
+	if err != nil {
+		return nil, err
+	}
+	if scalingConfig != nil {
 		esmProps["ScalingConfig"] = scalingConfig
 	}
+	pollerConfig, err := provisionedPollerConfig(props)
+	if err != nil {
+		return nil, err
+	}
+	if pollerConfig != nil {
+		esmProps["ProvisionedPollerConfig"] = pollerConfig
+	}
 
 	resources[esmID] = map[string]interface{}{
 		"Type":       lambda.ResourceTypeEventSourceMapping,
@@ -784,10 +1378,156 @@ func (t *FunctionTransformer) buildSQSEvent(logicalID, eventName string, props m
 	return resources, nil
 }
 
+// sqsScalingConfig returns the ScalingConfig for an SQS event, folding in the
+// MaximumConcurrency shortcut and validating its range. Intrinsic values are
+// not validated.
+func sqsScalingConfig(props map[string]interface{}) (interface{}, error) {
+	scalingConfig, hasScalingConfig := props["ScalingConfig"]
+	maxConcurrency, hasShortcut := props["MaximumConcurrency"]
+
+	if hasShortcut {
+		merged := map[string]interface{}{}
+		if configMap, ok := scalingConfig.(map[string]interface{}); ok {
+			if _, ok := configMap["MaximumConcurrency"]; ok {
+				return nil, fmt.Errorf("MaximumConcurrency cannot be set both directly and in ScalingConfig")
+			}
+			for k, v := range configMap {
+				merged[k] = v
+			}
+		} else if hasScalingConfig {
+			return nil, fmt.Errorf("MaximumConcurrency cannot be combined with a non-object ScalingConfig")
+		}
+		merged["MaximumConcurrency"] = maxConcurrency
+		scalingConfig, hasScalingConfig = merged, true
+	}
+
+	if !hasScalingConfig {
+		return nil, nil
+	}
+
+	if configMap, ok := scalingConfig.(map[string]interface{}); ok {
+		if n, ok := numberValue(configMap["MaximumConcurrency"]); ok && (n < 2 || n > 1000) {
+			return nil, fmt.Errorf("ScalingConfig MaximumConcurrency must be between 2 and 1000, got %v", configMap["MaximumConcurrency"])
+		}
+	}
+	return scalingConfig, nil
+}
+
+// provisionedPollerConfig returns an event's ProvisionedPollerConfig after
+// checking that MinimumPollers does not exceed MaximumPollers. Intrinsic
+// values are not validated.
+func provisionedPollerConfig(props map[string]interface{}) (interface{}, error) {
+	pollerConfig, ok := props["ProvisionedPollerConfig"]
+	if !ok {
+		return nil, nil
+	}
+
+	if configMap, ok := pollerConfig.(map[string]interface{}); ok {
+		minPollers, hasMin := numberValue(configMap["MinimumPollers"])
+		maxPollers, hasMax := numberValue(configMap["MaximumPollers"])
+		if hasMin && hasMax && minPollers > maxPollers {
+			return nil, fmt.Errorf("ProvisionedPollerConfig MinimumPollers (%v) cannot be greater than MaximumPollers (%v)", configMap["MinimumPollers"], configMap["MaximumPollers"])
+		}
+	}
+	return pollerConfig, nil
+}
+
+// startingPositionTimestamp returns an event's StartingPositionTimestamp after
+// checking that it is set exactly when StartingPosition is AT_TIMESTAMP.
+// Intrinsic starting positions are not validated.
+func startingPositionTimestamp(props map[string]interface{}) (interface{}, error) {
+	timestamp, hasTimestamp := props["StartingPositionTimestamp"]
+	position, isString := props["StartingPosition"].(string)
+	if !isString {
+		return timestamp, nil
+	}
+
+	if position == "AT_TIMESTAMP" && !hasTimestamp {
+		return nil, fmt.Errorf("StartingPosition AT_TIMESTAMP requires StartingPositionTimestamp")
+	}
+	if position != "AT_TIMESTAMP" && hasTimestamp {
+		return nil, fmt.Errorf("StartingPositionTimestamp requires StartingPosition AT_TIMESTAMP, got '%s'", position)
+	}
+	return timestamp, nil
+}
+
+// streamStartingPositions are the accepted StartingPosition values for stream event sources.
+var streamStartingPositions = []string{"LATEST", "TRIM_HORIZON", "AT_TIMESTAMP"}
+
+// validateStreamEvent checks a Kinesis or DynamoDB event's StartingPosition
+// and that its OnFailure destination is an ARN. Intrinsic values are not validated.
+func validateStreamEvent(props map[string]interface{}) error {
+	if position, ok := props["StartingPosition"].(string); ok && !containsString(streamStartingPositions, position) {
+		return fmt.Errorf("StartingPosition must be one of [%s], got '%s'", strings.Join(streamStartingPositions, ", "), position)
+	}
+
+	destConfig, _ := props["DestinationConfig"].(map[string]interface{})
+	onFailure, _ := destConfig["OnFailure"].(map[string]interface{})
+	destination, ok := onFailure["Destination"]
+	if !ok || intrinsics.IsIntrinsic(destination) {
+		return nil
+	}
+	if arn, isString := destination.(string); !isString || !strings.HasPrefix(arn, "arn:") {
+		return fmt.Errorf("DestinationConfig OnFailure Destination must be an ARN or an intrinsic function, got %v", destination)
+	}
+	return nil
+}
+
+// validatePermissionsBoundary checks that a function's PermissionsBoundary is
+// a policy ARN or an intrinsic function.
+func validatePermissionsBoundary(boundary interface{}) error {
+	if boundary == nil || intrinsics.IsIntrinsic(boundary) {
+		return nil
+	}
+	if arn, isString := boundary.(string); !isString || !strings.HasPrefix(arn, "arn:") {
+		return fmt.Errorf("PermissionsBoundary must be an ARN or an intrinsic function, got %v", boundary)
+	}
+	return nil
+}
+
+// filterCriteriaEventTypes lists the event types whose mappings support
+// encrypting FilterCriteria with a customer managed key.
+var filterCriteriaEventTypes = map[string]bool{
+	"SQS":      true,
+	"Kinesis":  true,
+	"DynamoDB": true,
+}
+
+// filterCriteriaKmsKeyArn returns the key used to encrypt an event's
+// FilterCriteria, given as KmsKeyArn or FilterCriteriaKmsKeyArn.
+func filterCriteriaKmsKeyArn(props map[string]interface{}) (interface{}, bool) {
+	if keyArn, ok := props["KmsKeyArn"]; ok {
+		return keyArn, true
+	}
+	keyArn, ok := props["FilterCriteriaKmsKeyArn"]
+	return keyArn, ok
+}
+
+// numberValue returns a numeric property value as a float64. It reports false
+// for intrinsic functions and other non-numeric values.
+func numberValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
 // buildKinesisEvent creates resources for a Kinesis event source.
 func (t *FunctionTransformer) buildKinesisEvent(logicalID, eventName string, props map[string]interface{}, functionRef interface{}) (map[string]interface{}, error) {
 	resources := make(map[string]interface{})
 
+	if err := validateStreamEvent(props); err != nil {
+		return nil, err
+	}
+
 	esmID := logicalID + eventName
 	esmProps := map[string]interface{}{
 		"FunctionName": functionRef,
@@ -799,6 +1539,13 @@ func (t *FunctionTransformer) buildKinesisEvent(logicalID, eventName string, pro
 	if startingPosition, ok := props["StartingPosition"]; ok {
 		esmProps["StartingPosition"] = startingPosition
 	}
+	timestamp, err := startingPositionTimestamp(props)
+	if err != nil {
+		return nil, err
+	}
+	if timestamp != nil {
+		esmProps["StartingPositionTimestamp"] = timestamp
+	}
 	if batchSize, ok := props["BatchSize"]; ok {
 		esmProps["BatchSize"] = batchSize
 	}
@@ -826,6 +1573,12 @@ func (t *FunctionTransformer) buildKinesisEvent(logicalID, eventName string, pro
 	if tumbling, ok := props["TumblingWindowInSeconds"]; ok {
 		esmProps["TumblingWindowInSeconds"] = tumbling
 	}
+	if filterCriteria, ok := props["FilterCriteria"]; ok {
+		esmProps["FilterCriteria"] = filterCriteria
+	}
+	if keyArn, ok := filterCriteriaKmsKeyArn(props); ok {
+		esmProps["KmsKeyArn"] = keyArn
+	}
 
 	resources[esmID] = map[string]interface{}{
 		"Type":       lambda.ResourceTypeEventSourceMapping,
@@ -839,6 +1592,10 @@ func (t *FunctionTransformer) buildKinesisEvent(logicalID, eventName string, pro
 func (t *FunctionTransformer) buildDynamoDBEvent(logicalID, eventName string, props map[string]interface{}, functionRef interface{}) (map[string]interface{}, error) {
 	resources := make(map[string]interface{})
 
+	if err := validateStreamEvent(props); err != nil {
+		return nil, err
+	}
+
 	esmID := logicalID + eventName
 	esmProps := map[string]interface{}{
 		"FunctionName": functionRef,
@@ -850,6 +1607,13 @@ func (t *FunctionTransformer) buildDynamoDBEvent(logicalID, eventName string, pr
 	if startingPosition, ok := props["StartingPosition"]; ok {
 		esmProps["StartingPosition"] = startingPosition
 	}
+	timestamp, err := startingPositionTimestamp(props)
+	if err != nil {
+		return nil, err
+	}
+	if timestamp != nil {
+		esmProps["StartingPositionTimestamp"] = timestamp
+	}
 	if batchSize, ok := props["BatchSize"]; ok {
 		esmProps["BatchSize"] = batchSize
 	}
@@ -880,6 +1644,9 @@ func (t *FunctionTransformer) buildDynamoDBEvent(logicalID, eventName string, pr
 	if filterCriteria, ok := props["FilterCriteria"]; ok {
 		esmProps["FilterCriteria"] = filterCriteria
 	}
+	if keyArn, ok := filterCriteriaKmsKeyArn(props); ok {
+		esmProps["KmsKeyArn"] = keyArn
+	}
 
 	resources[esmID] = map[string]interface{}{
 		"Type":       lambda.ResourceTypeEventSourceMapping,
@@ -911,6 +1678,10 @@ func (t *FunctionTransformer) buildApiEvent(logicalID, eventName string, props m
 
 // buildHttpApiEvent creates resources for an HTTP API (API Gateway V2) event source.
 func (t *FunctionTransformer) buildHttpApiEvent(logicalID, eventName string, props map[string]interface{}, functionRef interface{}) (map[string]interface{}, error) {
+	if err := validateHttpApiIntegration(props); err != nil {
+		return nil, err
+	}
+
 	resources := make(map[string]interface{})
 
 	// Create Lambda permission for API Gateway V2
@@ -919,6 +1690,7 @@ func (t *FunctionTransformer) buildHttpApiEvent(logicalID, eventName string, pro
 		"Action":       "lambda:InvokeFunction",
 		"FunctionName": functionRef,
 		"Principal":    "apigateway.amazonaws.com",
+		"SourceArn":    httpApiPermissionSourceArn(props),
 	}
 
 	resources[permissionID] = map[string]interface{}{
@@ -929,6 +1701,58 @@ func (t *FunctionTransformer) buildHttpApiEvent(logicalID, eventName string, pro
 	return resources, nil
 }
 
+// validateHttpApiIntegration checks the integration settings of an HttpApi
+// event. Intrinsic values are not validated.
+func validateHttpApiIntegration(props map[string]interface{}) error {
+	if version, ok := props["PayloadFormatVersion"].(string); ok && version != "1.0" && version != "2.0" {
+		return fmt.Errorf("PayloadFormatVersion '%s' is not valid; must be 1.0 or 2.0", version)
+	}
+	if n, ok := numberValue(props["TimeoutInMillis"]); ok && (n < 50 || n > 30000) {
+		return fmt.Errorf("TimeoutInMillis must be between 50 and 30000, got %v", props["TimeoutInMillis"])
+	}
+	return nil
+}
+
+// httpApiPathParameter matches path parameters, which the permission source ARN replaces with a wildcard.
+var httpApiPathParameter = regexp.MustCompile(`{([a-zA-Z0-9._-]+|proxy\+)}`)
+
+// httpApiPermissionSourceArn builds the execute-api source ARN for an HttpApi
+// event permission. Events without ApiId are scoped to the implicit
+// ServerlessHttpApi rather than any explicit API in the template.
+func httpApiPermissionSourceArn(props map[string]interface{}) interface{} {
+	apiID, ok := props["ApiId"]
+	if !ok {
+		apiID = map[string]interface{}{"Ref": "ServerlessHttpApi"}
+	}
+
+	path, _ := props["Path"].(string)
+	method, _ := props["Method"].(string)
+
+	resource := "${__ApiId__}/${__Stage__}/*"
+	if path != "" && path != "$default" {
+		if len(path) > 1 {
+			path = strings.TrimSuffix(path, "/")
+		}
+		path = httpApiPathParameter.ReplaceAllString(path, "*")
+
+		methodPart := strings.ToUpper(method)
+		if methodPart == "" || methodPart == "ANY" || methodPart == "X-AMAZON-APIGATEWAY-ANY-METHOD" {
+			methodPart = "*"
+		}
+		resource = "${__ApiId__}/${__Stage__}/" + methodPart + path
+	}
+
+	return map[string]interface{}{
+		"Fn::Sub": []interface{}{
+			"arn:${AWS::Partition}:execute-api:${AWS::Region}:${AWS::AccountId}:" + resource,
+			map[string]interface{}{
+				"__ApiId__": apiID,
+				"__Stage__": "*",
+			},
+		},
+	}
+}
+
 // buildScheduleEvent creates resources for a scheduled event (EventBridge).
 func (t *FunctionTransformer) buildScheduleEvent(logicalID, eventName string, props map[string]interface{}, functionRef interface{}) (map[string]interface{}, error) {
 	resources := make(map[string]interface{})
@@ -1098,6 +1922,21 @@ func (t *FunctionTransformer) buildSNSEvent(logicalID, eventName string, props m
 	if filterPolicyScope, ok := props["FilterPolicyScope"]; ok {
 		subscriptionProps["FilterPolicyScope"] = filterPolicyScope
 	}
+	if rawMessageDelivery, ok := props["RawMessageDelivery"]; ok {
+		subscriptionProps["RawMessageDelivery"] = rawMessageDelivery
+	}
+	if redrivePolicy, ok := props["RedrivePolicy"]; ok {
+		subscriptionProps["RedrivePolicy"] = redrivePolicy
+
+		// Allow the topic to send undeliverable messages to the dead-letter queue
+		if redriveMap, ok := redrivePolicy.(map[string]interface{}); ok {
+			if dlqArn, ok := redriveMap["deadLetterTargetArn"]; ok {
+				if queueUrl, ok := lookupQueueUrl(dlqArn); ok {
+					resources[logicalID+eventName+"DeadLetterQueuePolicy"] = snsDeadLetterQueuePolicy(queueUrl, dlqArn, props["Topic"])
+				}
+			}
+		}
+	}
 
 	resources[subscriptionID] = map[string]interface{}{
 		"Type":       "AWS::SNS::Subscription",
@@ -1107,10 +1946,41 @@ func (t *FunctionTransformer) buildSNSEvent(logicalID, eventName string, props m
 	return resources, nil
 }
 
+// snsDeadLetterQueuePolicy builds the AWS::SQS::QueuePolicy that lets SNS send
+// messages from topic to the dead-letter queue.
+func snsDeadLetterQueuePolicy(queueUrl, queueArn, topic interface{}) map[string]interface{} {
+	statement := map[string]interface{}{
+		"Effect":    "Allow",
+		"Principal": map[string]interface{}{"Service": "sns.amazonaws.com"},
+		"Action":    "sqs:SendMessage",
+		"Resource":  queueArn,
+	}
+	if topic != nil {
+		statement["Condition"] = map[string]interface{}{
+			"ArnEquals": map[string]interface{}{"aws:SourceArn": topic},
+		}
+	}
+
+	return map[string]interface{}{
+		"Type": "AWS::SQS::QueuePolicy",
+		"Properties": map[string]interface{}{
+			"Queues": []interface{}{queueUrl},
+			"PolicyDocument": map[string]interface{}{
+				"Version":   "2012-10-17",
+				"Statement": []interface{}{statement},
+			},
+		},
+	}
+}
+
 // buildIoTRuleEvent creates resources for an IoT Rule event source.
 func (t *FunctionTransformer) buildIoTRuleEvent(logicalID, eventName string, props map[string]interface{}, functionRef interface{}) (map[string]interface{}, error) {
 	resources := make(map[string]interface{})
 
+	if _, ok := props["Sql"]; !ok {
+		return nil, fmt.Errorf("IoTRule event requires Sql")
+	}
+
 	// Create IoT TopicRule
 	ruleID := logicalID + eventName
 	ruleProps := map[string]interface{}{
@@ -1136,6 +2006,12 @@ func (t *FunctionTransformer) buildIoTRuleEvent(logicalID, eventName string, pro
 		if desc, ok := props["Description"]; ok {
 			payload["Description"] = desc
 		}
+		if errorAction, ok := props["ErrorAction"]; ok {
+			payload["ErrorAction"] = errorAction
+		}
+	}
+	if ruleName, ok := props["RuleName"]; ok {
+		ruleProps["RuleName"] = ruleName
 	}
 
 	resources[ruleID] = map[string]interface{}{
@@ -1160,28 +2036,100 @@ func (t *FunctionTransformer) buildIoTRuleEvent(logicalID, eventName string, pro
 	return resources, nil
 }
 
-// buildCognitoEvent creates resources for a Cognito event source.
-func (t *FunctionTransformer) buildCognitoEvent(logicalID, eventName string, props map[string]interface{}, functionRef interface{}) (map[string]interface{}, error) {
-	resources := make(map[string]interface{})
+// cognitoTriggerNames are the LambdaConfig triggers a Cognito event can set.
+var cognitoTriggerNames = []string{
+	"CreateAuthChallenge", "CustomEmailSender", "CustomMessage", "CustomSMSSender",
+	"DefineAuthChallenge", "PostAuthentication", "PostConfirmation", "PreAuthentication",
+	"PreSignUp", "PreTokenGeneration", "UserMigration", "VerifyAuthChallengeResponse",
+}
 
-	// Create Lambda permission for Cognito
-	permissionID := logicalID + eventName + "Permission"
-	permissionProps := map[string]interface{}{
-		"Action":       "lambda:InvokeFunction",
-		"FunctionName": functionRef,
-		"Principal":    "cognito-idp.amazonaws.com",
+// cognitoEventTriggers returns the Cognito event's Trigger, which is a single
+// trigger name or a list of them.
+func cognitoEventTriggers(props map[string]interface{}) ([]string, error) {
+	var triggers []string
+	switch v := props["Trigger"].(type) {
+	case string:
+		triggers = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			trigger, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("type of property 'Trigger' is invalid")
+			}
+			triggers = append(triggers, trigger)
+		}
+	default:
+		return nil, fmt.Errorf("type of property 'Trigger' is invalid")
 	}
 
-	if userPool, ok := props["UserPool"]; ok {
-		permissionProps["SourceArn"] = t.buildCognitoUserPoolArn(userPool)
+	for i, trigger := range triggers {
+		if !containsString(cognitoTriggerNames, trigger) {
+			return nil, fmt.Errorf("Cognito trigger '%s' must be one of [%s]", trigger, strings.Join(cognitoTriggerNames, ", "))
+		}
+		if containsString(triggers[:i], trigger) {
+			return nil, fmt.Errorf("Cognito trigger \"%s\" defined multiple times.", trigger)
+		}
 	}
+	return triggers, nil
+}
 
-	resources[permissionID] = map[string]interface{}{
-		"Type":       lambda.ResourceTypePermission,
-		"Properties": permissionProps,
+// buildCognitoEvent validates a Cognito event source. The event adds its
+// triggers to the user pool's LambdaConfig, and the function's single
+// invoke permission is built by buildCognitoPermissions.
+func (t *FunctionTransformer) buildCognitoEvent(logicalID, eventName string, props map[string]interface{}, functionRef interface{}) (map[string]interface{}, error) {
+	if _, err := cognitoEventTriggers(props); err != nil {
+		return nil, err
 	}
+	return make(map[string]interface{}), nil
+}
 
-	return resources, nil
+// buildCognitoPermissions creates the permission letting Cognito invoke the
+// function, shared by all of its Cognito events. Events on a second user pool
+// need their own permission, named after the first event using that pool.
+func (t *FunctionTransformer) buildCognitoPermissions(logicalID string, f *Function, functionRef interface{}) map[string]interface{} {
+	resources := make(map[string]interface{})
+	var userPools []interface{}
+
+	for _, eventName := range sortedEventNames(f.Events) {
+		event, _ := f.Events[eventName].(map[string]interface{})
+		if event["Type"] != "Cognito" {
+			continue
+		}
+		props, _ := event["Properties"].(map[string]interface{})
+		userPool := props["UserPool"]
+
+		seen := false
+		for _, existing := range userPools {
+			if reflect.DeepEqual(existing, userPool) {
+				seen = true
+				break
+			}
+		}
+		if seen {
+			continue
+		}
+
+		permissionID := logicalID + "CognitoPermission"
+		if len(userPools) > 0 {
+			permissionID = logicalID + eventName + "CognitoPermission"
+		}
+		userPools = append(userPools, userPool)
+
+		permissionProps := map[string]interface{}{
+			"Action":       "lambda:InvokeFunction",
+			"FunctionName": functionRef,
+			"Principal":    "cognito-idp.amazonaws.com",
+		}
+		if userPool != nil {
+			permissionProps["SourceArn"] = t.buildCognitoUserPoolArn(userPool)
+		}
+		resources[permissionID] = map[string]interface{}{
+			"Type":       lambda.ResourceTypePermission,
+			"Properties": permissionProps,
+		}
+	}
+
+	return resources
 }
 
 // buildCognitoUserPoolArn creates a Cognito User Pool ARN.
@@ -1222,6 +2170,13 @@ func (t *FunctionTransformer) buildMSKEvent(logicalID, eventName string, props m
 	if startingPosition, ok := props["StartingPosition"]; ok {
 		esmProps["StartingPosition"] = startingPosition
 	}
+	timestamp, err := startingPositionTimestamp(props)
+	if err != nil {
+		return nil, err
+	}
+	if timestamp != nil {
+		esmProps["StartingPositionTimestamp"] = timestamp
+	}
 	if batchSize, ok := props["BatchSize"]; ok {
 		esmProps["BatchSize"] = batchSize
 	}
@@ -1230,6 +2185,13 @@ func (t *FunctionTransformer) buildMSKEvent(logicalID, eventName string, props m
 			"ConsumerGroupId": consumerGroupId,
 		}
 	}
+	pollerConfig, err := provisionedPollerConfig(props)
+	if err != nil {
+		return nil, err
+	}
+	if pollerConfig != nil {
+		esmProps["ProvisionedPollerConfig"] = pollerConfig
+	}
 
 	resources[esmID] = map[string]interface{}{
 		"Type":       lambda.ResourceTypeEventSourceMapping,
@@ -1257,9 +2219,11 @@ func (t *FunctionTransformer) buildMQEvent(logicalID, eventName string, props ma
 	if batchSize, ok := props["BatchSize"]; ok {
 		esmProps["BatchSize"] = batchSize
 	}
-	if sourceAccessConfigs, ok := props["SourceAccessConfigurations"]; ok {
-		esmProps["SourceAccessConfigurations"] = sourceAccessConfigs
+	sourceAccessConfigs, err := mqSourceAccessConfigurations(props)
+	if err != nil {
+		return nil, err
 	}
+	esmProps["SourceAccessConfigurations"] = sourceAccessConfigs
 
 	resources[esmID] = map[string]interface{}{
 		"Type":       lambda.ResourceTypeEventSourceMapping,
@@ -1269,6 +2233,63 @@ func (t *FunctionTransformer) buildMQEvent(logicalID, eventName string, props ma
 	return resources, nil
 }
 
+// mqSourceAccessConfigTypes are the SourceAccessConfigurations types
+// supported by Amazon MQ event sources.
+var mqSourceAccessConfigTypes = []string{"BASIC_AUTH", "VIRTUAL_HOST"}
+
+// mqSourceAccessConfigurations returns an MQ event's SourceAccessConfigurations
+// after checking them against the broker engine. Both ActiveMQ and RabbitMQ
+// brokers need exactly one BASIC_AUTH secret; a VIRTUAL_HOST entry marks the
+// broker as RabbitMQ and must name the virtual host in its URI. The broker ARN
+// is usually an intrinsic, so the engine is inferred from the configurations
+// rather than looked up. Intrinsic configuration lists are not validated.
+func mqSourceAccessConfigurations(props map[string]interface{}) (interface{}, error) {
+	sourceAccessConfigs, ok := props["SourceAccessConfigurations"]
+	if !ok {
+		return nil, fmt.Errorf("No SourceAccessConfigurations for Amazon MQ event provided.")
+	}
+	configs, ok := sourceAccessConfigs.([]interface{})
+	if !ok {
+		return sourceAccessConfigs, nil
+	}
+
+	var basicAuth, virtualHost int
+	for _, item := range configs {
+		config, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		configType, ok := config["Type"].(string)
+		if !ok {
+			continue
+		}
+		switch configType {
+		case "BASIC_AUTH":
+			basicAuth++
+			if _, hasURI := config["URI"]; !hasURI {
+				return nil, fmt.Errorf("No BASIC_AUTH URI property specified in SourceAccessConfigurations.")
+			}
+		case "VIRTUAL_HOST":
+			virtualHost++
+			if _, hasURI := config["URI"]; !hasURI {
+				return nil, fmt.Errorf("No VIRTUAL_HOST URI property specified in SourceAccessConfigurations.")
+			}
+		default:
+			return nil, fmt.Errorf("Invalid property Type specified in SourceAccessConfigurations. The supported values are: [%s].", strings.Join(mqSourceAccessConfigTypes, ", "))
+		}
+	}
+
+	switch {
+	case basicAuth == 0:
+		return nil, fmt.Errorf("No BASIC_AUTH property specified in SourceAccessConfigurations.")
+	case basicAuth > 1:
+		return nil, fmt.Errorf("Multiple BASIC_AUTH properties specified in SourceAccessConfigurations.")
+	case virtualHost > 1:
+		return nil, fmt.Errorf("Multiple VIRTUAL_HOST properties specified in SourceAccessConfigurations.")
+	}
+	return sourceAccessConfigs, nil
+}
+
 // buildSelfManagedKafkaEvent creates resources for a self-managed Kafka event source.
 func (t *FunctionTransformer) buildSelfManagedKafkaEvent(logicalID, eventName string, props map[string]interface{}, functionRef interface{}) (map[string]interface{}, error) {
 	resources := make(map[string]interface{})
@@ -1291,12 +2312,26 @@ func (t *FunctionTransformer) buildSelfManagedKafkaEvent(logicalID, eventName st
 	if startingPosition, ok := props["StartingPosition"]; ok {
 		esmProps["StartingPosition"] = startingPosition
 	}
+	timestamp, err := startingPositionTimestamp(props)
+	if err != nil {
+		return nil, err
+	}
+	if timestamp != nil {
+		esmProps["StartingPositionTimestamp"] = timestamp
+	}
 	if batchSize, ok := props["BatchSize"]; ok {
 		esmProps["BatchSize"] = batchSize
 	}
 	if sourceAccessConfigs, ok := props["SourceAccessConfigurations"]; ok {
 		esmProps["SourceAccessConfigurations"] = sourceAccessConfigs
 	}
+	pollerConfig, err := provisionedPollerConfig(props)
+	if err != nil {
+		return nil, err
+	}
+	if pollerConfig != nil {
+		esmProps["ProvisionedPollerConfig"] = pollerConfig
+	}
 
 	resources[esmID] = map[string]interface{}{
 		"Type":       lambda.ResourceTypeEventSourceMapping,
@@ -1351,7 +2386,12 @@ func (t *FunctionTransformer) buildCloudWatchLogsEvent(logicalID, eventName stri
 	return resources, nil
 }
 
+// alexaSkillID matches Alexa skill IDs, which the permission uses as its event source token.
+var alexaSkillID = regexp.MustCompile(`^amzn1\.ask\.skill\.[A-Za-z0-9-]+$`)
+
 // buildAlexaSkillEvent creates resources for an Alexa Skill event source.
+// A SkillId restricts the permission to that skill; without one, any Alexa
+// skill can invoke the function.
 func (t *FunctionTransformer) buildAlexaSkillEvent(logicalID, eventName string, props map[string]interface{}, functionRef interface{}) (map[string]interface{}, error) {
 	resources := make(map[string]interface{})
 
@@ -1363,7 +2403,11 @@ func (t *FunctionTransformer) buildAlexaSkillEvent(logicalID, eventName string,
 		"Principal":    "alexa-appkit.amazon.com",
 	}
 
+	// Without a SkillId the permission lets any Alexa skill invoke the function
 	if skillId, ok := props["SkillId"]; ok {
+		if id, isString := skillId.(string); isString && !alexaSkillID.MatchString(id) {
+			return nil, fmt.Errorf("SkillId '%s' is not a valid Alexa skill ID; expected the form amzn1.ask.skill.<id>", id)
+		}
 		permissionProps["EventSourceToken"] = skillId
 	}
 
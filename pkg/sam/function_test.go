@@ -2,6 +2,8 @@ package sam
 
 import (
 	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -57,6 +59,33 @@ func TestFunctionTransformer_BasicFunction(t *testing.T) {
 	}
 }
 
+func TestFunctionTransformer_IntrinsicTimeoutAndMemorySize(t *testing.T) {
+	transformer := NewFunctionTransformer()
+
+	timeout := map[string]interface{}{"Ref": "TimeoutParam"}
+	memorySize := map[string]interface{}{"Ref": "MemorySizeParam"}
+	fn := &Function{
+		Handler:    "index.handler",
+		Runtime:    "nodejs18.x",
+		CodeUri:    "s3://bucket/code.zip",
+		Timeout:    timeout,
+		MemorySize: memorySize,
+	}
+
+	resources, err := transformer.Transform("MyFunction", fn, nil)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	props := resources["MyFunction"].(map[string]interface{})["Properties"].(map[string]interface{})
+	if !reflect.DeepEqual(props["Timeout"], timeout) {
+		t.Errorf("expected Timeout %v, got %v", timeout, props["Timeout"])
+	}
+	if !reflect.DeepEqual(props["MemorySize"], memorySize) {
+		t.Errorf("expected MemorySize %v, got %v", memorySize, props["MemorySize"])
+	}
+}
+
 func TestFunctionTransformer_WithExplicitRole(t *testing.T) {
 	transformer := NewFunctionTransformer()
 
@@ -118,16 +147,21 @@ func TestFunctionTransformer_WithEnvironment(t *testing.T) {
 	}
 }
 
-func TestFunctionTransformer_WithTags(t *testing.T) {
+func TestFunctionTransformer_EnvironmentNormalization(t *testing.T) {
 	transformer := NewFunctionTransformer()
 
+	tableRef := map[string]interface{}{"Ref": "MyTable"}
 	fn := &Function{
 		Handler: "index.handler",
 		Runtime: "nodejs18.x",
 		CodeUri: "s3://bucket/code.zip",
-		Tags: map[string]string{
-			"Environment": "production",
-			"Team":        "backend",
+		Environment: map[string]interface{}{
+			"Variables": map[string]interface{}{
+				"DEBUG":      true,
+				"PORT":       8080,
+				"RATIO":      0.5,
+				"TABLE_NAME": tableRef,
+			},
 		},
 	}
 
@@ -138,9 +172,94 @@ func TestFunctionTransformer_WithTags(t *testing.T) {
 
 	fnResource := resources["MyFunction"].(map[string]interface{})
 	props := fnResource["Properties"].(map[string]interface{})
-	tags := props["Tags"].([]interface{})
-	if len(tags) != 2 {
-		t.Errorf("expected 2 tags, got %d", len(tags))
+	vars := props["Environment"].(map[string]interface{})["Variables"].(map[string]interface{})
+
+	for name, want := range map[string]string{"DEBUG": "true", "PORT": "8080", "RATIO": "0.5"} {
+		if vars[name] != want {
+			t.Errorf("expected %s %q, got %#v", name, want, vars[name])
+		}
+	}
+	if !reflect.DeepEqual(vars["TABLE_NAME"], tableRef) {
+		t.Errorf("expected TABLE_NAME intrinsic to be preserved, got %v", vars["TABLE_NAME"])
+	}
+	if fn.Environment["Variables"].(map[string]interface{})["DEBUG"] != true {
+		t.Error("expected input environment to be left unmodified")
+	}
+}
+
+func TestFunctionTransformer_WithTags(t *testing.T) {
+	createdBy := map[string]interface{}{"Key": "lambda:createdBy", "Value": "SAM"}
+
+	tests := []struct {
+		name      string
+		tags      map[string]string
+		omit      bool
+		wantTags  []interface{}
+		wantUnset bool
+	}{
+		{
+			name: "user tags follow createdBy",
+			tags: map[string]string{"Team": "backend", "Environment": "production"},
+			wantTags: []interface{}{
+				createdBy,
+				map[string]interface{}{"Key": "Environment", "Value": "production"},
+				map[string]interface{}{"Key": "Team", "Value": "backend"},
+			},
+		},
+		{
+			name:     "no user tags",
+			wantTags: []interface{}{createdBy},
+		},
+		{
+			name:     "user tag overrides createdBy",
+			tags:     map[string]string{"lambda:createdBy": "pipeline"},
+			wantTags: []interface{}{map[string]interface{}{"Key": "lambda:createdBy", "Value": "pipeline"}},
+		},
+		{
+			name:     "createdBy omitted",
+			tags:     map[string]string{"Team": "backend"},
+			omit:     true,
+			wantTags: []interface{}{map[string]interface{}{"Key": "Team", "Value": "backend"}},
+		},
+		{
+			name:      "createdBy omitted without user tags",
+			omit:      true,
+			wantUnset: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewFunctionTransformer()
+			transformer.OmitCreatedByTag = tt.omit
+
+			fn := &Function{
+				Handler: "index.handler",
+				Runtime: "nodejs18.x",
+				CodeUri: "s3://bucket/code.zip",
+				Tags:    tt.tags,
+			}
+
+			resources, err := transformer.Transform("MyFunction", fn, nil)
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			// The generated role carries the same tags as the function
+			for _, id := range []string{"MyFunction", "MyFunctionRole"} {
+				props := resources[id].(map[string]interface{})["Properties"].(map[string]interface{})
+				tags, ok := props["Tags"]
+				if tt.wantUnset {
+					if ok {
+						t.Errorf("%s: expected no Tags, got %v", id, tags)
+					}
+					continue
+				}
+				if !reflect.DeepEqual(tags, tt.wantTags) {
+					t.Errorf("%s: expected Tags %v, got %v", id, tt.wantTags, tags)
+				}
+			}
+		})
 	}
 }
 
@@ -299,6 +418,577 @@ func TestFunctionTransformer_WithSQSEvent(t *testing.T) {
 	}
 }
 
+func TestFunctionTransformer_SQSEventScalingConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		props       map[string]interface{}
+		want        interface{}
+		expectError bool
+	}{
+		{
+			name:  "valid ScalingConfig",
+			props: map[string]interface{}{"ScalingConfig": map[string]interface{}{"MaximumConcurrency": 5}},
+			want:  map[string]interface{}{"MaximumConcurrency": 5},
+		},
+		{
+			name:        "MaximumConcurrency below range",
+			props:       map[string]interface{}{"ScalingConfig": map[string]interface{}{"MaximumConcurrency": 1}},
+			expectError: true,
+		},
+		{
+			name:        "MaximumConcurrency above range",
+			props:       map[string]interface{}{"MaximumConcurrency": 1001},
+			expectError: true,
+		},
+		{
+			name:  "MaximumConcurrency shortcut",
+			props: map[string]interface{}{"MaximumConcurrency": 1000},
+			want:  map[string]interface{}{"MaximumConcurrency": 1000},
+		},
+		{
+			name:  "intrinsic MaximumConcurrency",
+			props: map[string]interface{}{"MaximumConcurrency": map[string]interface{}{"Ref": "Concurrency"}},
+			want:  map[string]interface{}{"MaximumConcurrency": map[string]interface{}{"Ref": "Concurrency"}},
+		},
+		{
+			name: "shortcut and ScalingConfig both set",
+			props: map[string]interface{}{
+				"MaximumConcurrency": 5,
+				"ScalingConfig":      map[string]interface{}{"MaximumConcurrency": 10},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			props := map[string]interface{}{"Queue": "arn:aws:sqs:us-east-1:123456789012:MyQueue"}
+			for k, v := range tt.props {
+				props[k] = v
+			}
+			fn := &Function{
+				Handler: "index.handler",
+				Runtime: "nodejs18.x",
+				CodeUri: "s3://bucket/code.zip",
+				Events: map[string]interface{}{
+					"SQSEvent": map[string]interface{}{"Type": "SQS", "Properties": props},
+				},
+			}
+
+			resources, err := NewFunctionTransformer().Transform("MyFunction", fn, nil)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			esmProps := resources["MyFunctionSQSEvent"].(map[string]interface{})["Properties"].(map[string]interface{})
+			if !reflect.DeepEqual(esmProps["ScalingConfig"], tt.want) {
+				t.Errorf("expected ScalingConfig %v, got %v", tt.want, esmProps["ScalingConfig"])
+			}
+			if _, ok := esmProps["MaximumConcurrency"]; ok {
+				t.Error("MaximumConcurrency shortcut should not be emitted on the EventSourceMapping")
+			}
+		})
+	}
+}
+
+func TestFunctionTransformer_EventFilterCriteriaKmsKeyArn(t *testing.T) {
+	keyArn := map[string]interface{}{"Fn::GetAtt": []interface{}{"FilterKey", "Arn"}}
+	tests := []struct {
+		eventType string
+		props     map[string]interface{}
+	}{
+		{"SQS", map[string]interface{}{"Queue": "arn:aws:sqs:us-east-1:123456789012:MyQueue", "KmsKeyArn": keyArn}},
+		{"Kinesis", map[string]interface{}{"Stream": "arn:aws:kinesis:us-east-1:123456789012:stream/MyStream", "StartingPosition": "LATEST", "FilterCriteriaKmsKeyArn": keyArn}},
+		{"DynamoDB", map[string]interface{}{"Stream": "arn:aws:dynamodb:us-east-1:123456789012:table/MyTable/stream/2021", "StartingPosition": "LATEST", "KmsKeyArn": keyArn}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.eventType, func(t *testing.T) {
+			tt.props["FilterCriteria"] = map[string]interface{}{
+				"Filters": []interface{}{map[string]interface{}{"Pattern": `{"body":{"type":["order"]}}`}},
+			}
+			fn := &Function{
+				Handler: "index.handler",
+				Runtime: "nodejs18.x",
+				CodeUri: "s3://bucket/code.zip",
+				Events: map[string]interface{}{
+					"Stream": map[string]interface{}{"Type": tt.eventType, "Properties": tt.props},
+				},
+			}
+
+			resources, err := NewFunctionTransformer().Transform("MyFunction", fn, nil)
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			esmProps := resources["MyFunctionStream"].(map[string]interface{})["Properties"].(map[string]interface{})
+			if !reflect.DeepEqual(esmProps["KmsKeyArn"], keyArn) {
+				t.Errorf("expected EventSourceMapping KmsKeyArn %v, got %v", keyArn, esmProps["KmsKeyArn"])
+			}
+			if _, ok := esmProps["FilterCriteria"]; !ok {
+				t.Error("expected FilterCriteria on the EventSourceMapping")
+			}
+
+			roleProps := resources["MyFunctionRole"].(map[string]interface{})["Properties"].(map[string]interface{})
+			policies := roleProps["Policies"].([]map[string]interface{})
+			if len(policies) != 1 {
+				t.Fatalf("expected 1 inline policy, got %d", len(policies))
+			}
+			if policies[0]["PolicyName"] != "MyFunctionStreamFilterCriteriaKmsDecryptPolicy" {
+				t.Errorf("expected PolicyName 'MyFunctionStreamFilterCriteriaKmsDecryptPolicy', got %v", policies[0]["PolicyName"])
+			}
+			doc := policies[0]["PolicyDocument"].(map[string]interface{})
+			stmt := doc["Statement"].([]interface{})[0].(map[string]interface{})
+			if stmt["Action"] != "kms:Decrypt" {
+				t.Errorf("expected Action 'kms:Decrypt', got %v", stmt["Action"])
+			}
+			if !reflect.DeepEqual(stmt["Resource"], keyArn) {
+				t.Errorf("expected Resource to be the key ARN intrinsic, got %v", stmt["Resource"])
+			}
+		})
+	}
+}
+
+func TestFunctionTransformer_EventProvisionedPollerConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		eventType   string
+		props       map[string]interface{}
+		pollers     map[string]interface{}
+		expectError bool
+	}{
+		{
+			name:      "SQS",
+			eventType: "SQS",
+			props:     map[string]interface{}{"Queue": "arn:aws:sqs:us-east-1:123456789012:MyQueue"},
+			pollers:   map[string]interface{}{"MinimumPollers": 2, "MaximumPollers": 10},
+		},
+		{
+			name:      "MSK",
+			eventType: "MSK",
+			props:     map[string]interface{}{"Stream": "arn:aws:kafka:us-east-1:123456789012:cluster/c/1", "Topics": []interface{}{"orders"}},
+			pollers:   map[string]interface{}{"MinimumPollers": 1, "MaximumPollers": 1},
+		},
+		{
+			name:      "SelfManagedKafka",
+			eventType: "SelfManagedKafka",
+			props:     map[string]interface{}{"KafkaBootstrapServers": []interface{}{"broker:9092"}, "Topics": []interface{}{"orders"}},
+			pollers:   map[string]interface{}{"MinimumPollers": map[string]interface{}{"Ref": "MinPollers"}, "MaximumPollers": 5},
+		},
+		{
+			name:        "minimum greater than maximum",
+			eventType:   "MSK",
+			props:       map[string]interface{}{"Stream": "arn:aws:kafka:us-east-1:123456789012:cluster/c/1", "Topics": []interface{}{"orders"}},
+			pollers:     map[string]interface{}{"MinimumPollers": 10, "MaximumPollers": 2},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.props["ProvisionedPollerConfig"] = tt.pollers
+			fn := &Function{
+				Handler: "index.handler",
+				Runtime: "nodejs18.x",
+				CodeUri: "s3://bucket/code.zip",
+				Events: map[string]interface{}{
+					"Source": map[string]interface{}{"Type": tt.eventType, "Properties": tt.props},
+				},
+			}
+
+			resources, err := NewFunctionTransformer().Transform("MyFunction", fn, nil)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if !strings.Contains(err.Error(), "MinimumPollers") {
+					t.Errorf("expected MinimumPollers error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			esmProps := resources["MyFunctionSource"].(map[string]interface{})["Properties"].(map[string]interface{})
+			if !reflect.DeepEqual(esmProps["ProvisionedPollerConfig"], tt.pollers) {
+				t.Errorf("expected ProvisionedPollerConfig %v, got %v", tt.pollers, esmProps["ProvisionedPollerConfig"])
+			}
+		})
+	}
+}
+
+func TestFunctionTransformer_EventStartingPositionTimestamp(t *testing.T) {
+	mskProps := func() map[string]interface{} {
+		return map[string]interface{}{"Stream": "arn:aws:kafka:us-east-1:123456789012:cluster/c/1", "Topics": []interface{}{"orders"}}
+	}
+	kafkaProps := func() map[string]interface{} {
+		return map[string]interface{}{"KafkaBootstrapServers": []interface{}{"broker:9092"}, "Topics": []interface{}{"orders"}}
+	}
+	streamProps := func() map[string]interface{} {
+		return map[string]interface{}{"Stream": "arn:aws:kinesis:us-east-1:123456789012:stream/orders"}
+	}
+
+	tests := []struct {
+		name        string
+		eventType   string
+		props       map[string]interface{}
+		position    interface{}
+		timestamp   interface{}
+		expectError string
+	}{
+		{name: "MSK at timestamp", eventType: "MSK", props: mskProps(), position: "AT_TIMESTAMP", timestamp: 1700000000},
+		{name: "SelfManagedKafka at timestamp", eventType: "SelfManagedKafka", props: kafkaProps(), position: "AT_TIMESTAMP", timestamp: 1700000000},
+		{name: "intrinsic position", eventType: "MSK", props: mskProps(), position: map[string]interface{}{"Ref": "Position"}, timestamp: 1700000000},
+		{name: "timestamp with LATEST", eventType: "MSK", props: mskProps(), position: "LATEST", timestamp: 1700000000, expectError: "requires StartingPosition AT_TIMESTAMP"},
+		{name: "AT_TIMESTAMP without timestamp", eventType: "SelfManagedKafka", props: kafkaProps(), position: "AT_TIMESTAMP", expectError: "requires StartingPositionTimestamp"},
+		{name: "Kinesis at timestamp", eventType: "Kinesis", props: streamProps(), position: "AT_TIMESTAMP", timestamp: 1700000000},
+		{name: "DynamoDB timestamp with TRIM_HORIZON", eventType: "DynamoDB", props: streamProps(), position: "TRIM_HORIZON", timestamp: 1700000000, expectError: "requires StartingPosition AT_TIMESTAMP"},
+		{name: "Kinesis AT_TIMESTAMP without timestamp", eventType: "Kinesis", props: streamProps(), position: "AT_TIMESTAMP", expectError: "requires StartingPositionTimestamp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.props["StartingPosition"] = tt.position
+			if tt.timestamp != nil {
+				tt.props["StartingPositionTimestamp"] = tt.timestamp
+			}
+			fn := &Function{
+				Handler: "index.handler",
+				Runtime: "nodejs18.x",
+				CodeUri: "s3://bucket/code.zip",
+				Events: map[string]interface{}{
+					"Source": map[string]interface{}{"Type": tt.eventType, "Properties": tt.props},
+				},
+			}
+
+			resources, err := NewFunctionTransformer().Transform("MyFunction", fn, nil)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("expected error containing %q, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			esmProps := resources["MyFunctionSource"].(map[string]interface{})["Properties"].(map[string]interface{})
+			if esmProps["StartingPositionTimestamp"] != tt.timestamp {
+				t.Errorf("expected StartingPositionTimestamp %v, got %v", tt.timestamp, esmProps["StartingPositionTimestamp"])
+			}
+		})
+	}
+}
+
+func TestFunctionTransformer_StreamEventValidation(t *testing.T) {
+	onFailure := func(destination interface{}) map[string]interface{} {
+		return map[string]interface{}{"OnFailure": map[string]interface{}{"Destination": destination}}
+	}
+
+	tests := []struct {
+		name        string
+		eventType   string
+		props       map[string]interface{}
+		expectError string
+	}{
+		{name: "LATEST", eventType: "Kinesis", props: map[string]interface{}{"StartingPosition": "LATEST"}},
+		{name: "intrinsic position", eventType: "DynamoDB", props: map[string]interface{}{"StartingPosition": map[string]interface{}{"Ref": "Position"}}},
+		{name: "invalid position", eventType: "Kinesis", props: map[string]interface{}{"StartingPosition": "EARLIEST"}, expectError: "StartingPosition must be one of"},
+		{name: "invalid DynamoDB position", eventType: "DynamoDB", props: map[string]interface{}{"StartingPosition": "latest"}, expectError: "StartingPosition must be one of"},
+		{name: "ARN destination", eventType: "Kinesis", props: map[string]interface{}{"StartingPosition": "LATEST", "DestinationConfig": onFailure("arn:aws:sqs:us-east-1:123456789012:dlq")}},
+		{name: "intrinsic destination", eventType: "DynamoDB", props: map[string]interface{}{"StartingPosition": "LATEST", "DestinationConfig": onFailure(map[string]interface{}{"Fn::GetAtt": []interface{}{"Dlq", "Arn"}})}},
+		{name: "non-ARN destination", eventType: "DynamoDB", props: map[string]interface{}{"StartingPosition": "LATEST", "DestinationConfig": onFailure("my-queue")}, expectError: "must be an ARN"},
+		{name: "non-string destination", eventType: "Kinesis", props: map[string]interface{}{"StartingPosition": "LATEST", "DestinationConfig": onFailure([]interface{}{"arn:aws:sqs:us-east-1:123456789012:dlq"})}, expectError: "must be an ARN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.props["Stream"] = "arn:aws:kinesis:us-east-1:123456789012:stream/orders"
+			fn := &Function{
+				Handler: "index.handler",
+				Runtime: "nodejs18.x",
+				CodeUri: "s3://bucket/code.zip",
+				Events: map[string]interface{}{
+					"Source": map[string]interface{}{"Type": tt.eventType, "Properties": tt.props},
+				},
+			}
+
+			_, err := NewFunctionTransformer().Transform("MyFunction", fn, nil)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("expected error containing %q, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestFunctionTransformer_MQEvent(t *testing.T) {
+	basicAuth := map[string]interface{}{"Type": "BASIC_AUTH", "URI": "arn:aws:secretsmanager:us-east-1:123456789012:secret:mq"}
+	virtualHost := map[string]interface{}{"Type": "VIRTUAL_HOST", "URI": "vhost_name"}
+
+	tests := []struct {
+		name        string
+		configs     interface{}
+		expectError string
+	}{
+		{name: "ActiveMQ", configs: []interface{}{basicAuth}},
+		{name: "RabbitMQ with virtual host", configs: []interface{}{basicAuth, virtualHost}},
+		{name: "intrinsic configurations", configs: map[string]interface{}{"Ref": "Configs"}},
+		{name: "missing configurations", expectError: "No SourceAccessConfigurations for Amazon MQ event provided"},
+		{name: "missing BASIC_AUTH", configs: []interface{}{virtualHost}, expectError: "No BASIC_AUTH property specified"},
+		{name: "BASIC_AUTH without URI", configs: []interface{}{map[string]interface{}{"Type": "BASIC_AUTH"}}, expectError: "No BASIC_AUTH URI property specified"},
+		{name: "multiple BASIC_AUTH", configs: []interface{}{basicAuth, basicAuth}, expectError: "Multiple BASIC_AUTH properties"},
+		{name: "VIRTUAL_HOST without URI", configs: []interface{}{basicAuth, map[string]interface{}{"Type": "VIRTUAL_HOST"}}, expectError: "No VIRTUAL_HOST URI property specified"},
+		{name: "multiple VIRTUAL_HOST", configs: []interface{}{basicAuth, virtualHost, virtualHost}, expectError: "Multiple VIRTUAL_HOST properties"},
+		{name: "unsupported type", configs: []interface{}{basicAuth, map[string]interface{}{"Type": "VPC_SUBNET", "URI": "subnet"}}, expectError: "The supported values are: [BASIC_AUTH, VIRTUAL_HOST]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			props := map[string]interface{}{
+				"Broker": "arn:aws:mq:us-east-1:123456789012:broker:MyBroker:b-1234",
+				"Queues": []interface{}{"Queue1"},
+			}
+			if tt.configs != nil {
+				props["SourceAccessConfigurations"] = tt.configs
+			}
+			fn := &Function{
+				Handler: "index.handler",
+				Runtime: "nodejs18.x",
+				CodeUri: "s3://bucket/code.zip",
+				Events: map[string]interface{}{
+					"Source": map[string]interface{}{"Type": "MQ", "Properties": props},
+				},
+			}
+
+			resources, err := NewFunctionTransformer().Transform("MyFunction", fn, nil)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("expected error containing %q, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			esmProps := resources["MyFunctionSource"].(map[string]interface{})["Properties"].(map[string]interface{})
+			if esmProps["EventSourceArn"] != props["Broker"] {
+				t.Errorf("expected EventSourceArn %v, got %v", props["Broker"], esmProps["EventSourceArn"])
+			}
+			if !reflect.DeepEqual(esmProps["Queues"], props["Queues"]) {
+				t.Errorf("expected Queues %v, got %v", props["Queues"], esmProps["Queues"])
+			}
+			if !reflect.DeepEqual(esmProps["SourceAccessConfigurations"], tt.configs) {
+				t.Errorf("expected SourceAccessConfigurations %v, got %v", tt.configs, esmProps["SourceAccessConfigurations"])
+			}
+		})
+	}
+}
+
+func TestFunctionTransformer_HttpApiEventIntegrationValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		props       map[string]interface{}
+		expectError string
+	}{
+		{name: "valid settings", props: map[string]interface{}{"PayloadFormatVersion": "1.0", "TimeoutInMillis": 30000}},
+		{name: "intrinsic timeout", props: map[string]interface{}{"TimeoutInMillis": map[string]interface{}{"Ref": "Timeout"}}},
+		{name: "invalid payload version", props: map[string]interface{}{"PayloadFormatVersion": "3.0"}, expectError: "PayloadFormatVersion"},
+		{name: "timeout too low", props: map[string]interface{}{"TimeoutInMillis": 49}, expectError: "TimeoutInMillis"},
+		{name: "timeout too high", props: map[string]interface{}{"TimeoutInMillis": 30001}, expectError: "TimeoutInMillis"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			props := map[string]interface{}{"Path": "/items", "Method": "GET"}
+			for k, v := range tt.props {
+				props[k] = v
+			}
+			fn := &Function{
+				Handler: "index.handler",
+				Runtime: "nodejs18.x",
+				CodeUri: "s3://bucket/code.zip",
+				Events: map[string]interface{}{
+					"GetItems": map[string]interface{}{"Type": "HttpApi", "Properties": props},
+				},
+			}
+
+			_, err := NewFunctionTransformer().Transform("MyFunction", fn, nil)
+			if tt.expectError == "" {
+				if err != nil {
+					t.Fatalf("Transform failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+				t.Errorf("expected %s error, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestFunctionTransformer_UseInlineBasicExecutionPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		functionName interface{}
+		wantResource interface{}
+		wantPolicy   bool
+	}{
+		{
+			name:         "named function",
+			functionName: "orders",
+			wantResource: map[string]interface{}{"Fn::Sub": "arn:${AWS::Partition}:logs:${AWS::Region}:${AWS::AccountId}:log-group:/aws/lambda/orders:*"},
+		},
+		{
+			name:         "generated name",
+			wantResource: map[string]interface{}{"Fn::Sub": "arn:${AWS::Partition}:logs:${AWS::Region}:${AWS::AccountId}:log-group:/aws/lambda/${MyFunction}:*"},
+			wantPolicy:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewFunctionTransformer()
+			transformer.UseInlineBasicExecutionPolicy = true
+
+			fn := &Function{
+				Handler:      "index.handler",
+				Runtime:      "nodejs18.x",
+				CodeUri:      "s3://bucket/code.zip",
+				FunctionName: tt.functionName,
+			}
+
+			resources, err := transformer.Transform("MyFunction", fn, nil)
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			roleProps := resources["MyFunctionRole"].(map[string]interface{})["Properties"].(map[string]interface{})
+			if managed, ok := roleProps["ManagedPolicyArns"]; ok {
+				t.Errorf("expected no managed policies, got %v", managed)
+			}
+
+			// A policy referencing the function cannot be inlined in its role
+			var doc map[string]interface{}
+			if tt.wantPolicy {
+				if _, ok := roleProps["Policies"]; ok {
+					t.Errorf("expected no inline policies, got %v", roleProps["Policies"])
+				}
+				policy, ok := resources["MyFunctionBasicExecutionPolicy"].(map[string]interface{})
+				if !ok || policy["Type"] != "AWS::IAM::Policy" {
+					t.Fatalf("expected MyFunctionBasicExecutionPolicy policy resource, got %v", resources["MyFunctionBasicExecutionPolicy"])
+				}
+				policyProps := policy["Properties"].(map[string]interface{})
+				if roles := []interface{}{map[string]interface{}{"Ref": "MyFunctionRole"}}; !reflect.DeepEqual(policyProps["Roles"], roles) {
+					t.Errorf("expected policy attached to %v, got %v", roles, policyProps["Roles"])
+				}
+				doc = policyProps["PolicyDocument"].(map[string]interface{})
+			} else {
+				policies := roleProps["Policies"].([]map[string]interface{})
+				if len(policies) != 1 || policies[0]["PolicyName"] != "MyFunctionBasicExecutionPolicy" {
+					t.Fatalf("expected MyFunctionBasicExecutionPolicy inline policy, got %v", policies)
+				}
+				doc = policies[0]["PolicyDocument"].(map[string]interface{})
+			}
+			stmt := doc["Statement"].([]interface{})[0].(map[string]interface{})
+			expectedActions := []interface{}{"logs:CreateLogGroup", "logs:CreateLogStream", "logs:PutLogEvents"}
+			if !reflect.DeepEqual(stmt["Action"], expectedActions) {
+				t.Errorf("expected actions %v, got %v", expectedActions, stmt["Action"])
+			}
+			if !reflect.DeepEqual(stmt["Resource"], tt.wantResource) {
+				t.Errorf("expected Resource %v, got %v", tt.wantResource, stmt["Resource"])
+			}
+		})
+	}
+}
+
+func TestFunctionTransformer_LoggingConfigCreatesLogGroup(t *testing.T) {
+	fn := &Function{
+		Handler: "index.handler",
+		Runtime: "nodejs18.x",
+		CodeUri: "s3://bucket/code.zip",
+		LoggingConfig: map[string]interface{}{
+			"LogFormat":       "JSON",
+			"LogGroup":        "/orders/service",
+			"CreateLogGroup":  true,
+			"RetentionInDays": 14,
+		},
+	}
+
+	resources, err := NewFunctionTransformer().Transform("MyFunction", fn, nil)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	logGroup, ok := resources["MyFunctionLogGroup"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected MyFunctionLogGroup to be created")
+	}
+	if logGroup["Type"] != "AWS::Logs::LogGroup" {
+		t.Errorf("expected Type AWS::Logs::LogGroup, got %v", logGroup["Type"])
+	}
+	expectedLogGroupProps := map[string]interface{}{"LogGroupName": "/orders/service", "RetentionInDays": 14}
+	if !reflect.DeepEqual(logGroup["Properties"], expectedLogGroupProps) {
+		t.Errorf("expected log group properties %v, got %v", expectedLogGroupProps, logGroup["Properties"])
+	}
+
+	fnProps := resources["MyFunction"].(map[string]interface{})["Properties"].(map[string]interface{})
+	expectedLoggingConfig := map[string]interface{}{
+		"LogFormat": "JSON",
+		"LogGroup":  map[string]interface{}{"Ref": "MyFunctionLogGroup"},
+	}
+	if !reflect.DeepEqual(fnProps["LoggingConfig"], expectedLoggingConfig) {
+		t.Errorf("expected LoggingConfig %v, got %v", expectedLoggingConfig, fnProps["LoggingConfig"])
+	}
+
+	roleProps := resources["MyFunctionRole"].(map[string]interface{})["Properties"].(map[string]interface{})
+	if managed, ok := roleProps["ManagedPolicyArns"]; ok {
+		t.Errorf("expected unscoped AWSLambdaBasicExecutionRole to be replaced, got %v", managed)
+	}
+	policies := roleProps["Policies"].([]map[string]interface{})
+	doc := policies[0]["PolicyDocument"].(map[string]interface{})
+	stmt := doc["Statement"].([]interface{})[0].(map[string]interface{})
+	expectedResource := map[string]interface{}{"Fn::GetAtt": []interface{}{"MyFunctionLogGroup", "Arn"}}
+	if !reflect.DeepEqual(stmt["Resource"], expectedResource) {
+		t.Errorf("expected logging statement scoped to %v, got %v", expectedResource, stmt["Resource"])
+	}
+}
+
+func TestFunctionTransformer_LoggingConfigWithoutCreateLogGroup(t *testing.T) {
+	fn := &Function{
+		Handler:       "index.handler",
+		Runtime:       "nodejs18.x",
+		CodeUri:       "s3://bucket/code.zip",
+		LoggingConfig: map[string]interface{}{"LogGroup": "/orders/service"},
+	}
+
+	resources, err := NewFunctionTransformer().Transform("MyFunction", fn, nil)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if _, ok := resources["MyFunctionLogGroup"]; ok {
+		t.Error("expected no log group without CreateLogGroup")
+	}
+	fnProps := resources["MyFunction"].(map[string]interface{})["Properties"].(map[string]interface{})
+	if !reflect.DeepEqual(fnProps["LoggingConfig"], fn.LoggingConfig) {
+		t.Errorf("expected LoggingConfig %v, got %v", fn.LoggingConfig, fnProps["LoggingConfig"])
+	}
+}
+
 func TestFunctionTransformer_WithApiEvent(t *testing.T) {
 	transformer := NewFunctionTransformer()
 
@@ -387,6 +1077,71 @@ func TestFunctionTransformer_WithInlinePolicies(t *testing.T) {
 	}
 }
 
+func TestFunctionTransformer_WithMixedPolicies(t *testing.T) {
+	transformer := NewFunctionTransformer()
+
+	fn := &Function{
+		Handler: "index.handler",
+		Runtime: "nodejs18.x",
+		CodeUri: "s3://bucket/code.zip",
+		Policies: []interface{}{
+			"arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess",
+			map[string]interface{}{
+				"SQSPollerPolicy": map[string]interface{}{"QueueName": "my-queue"},
+			},
+			map[string]interface{}{
+				"Statement": []interface{}{
+					map[string]interface{}{
+						"Effect":   "Allow",
+						"Action":   []interface{}{"dynamodb:GetItem"},
+						"Resource": "*",
+					},
+				},
+			},
+		},
+	}
+
+	resources, err := transformer.Transform("MyFunction", fn, nil)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	roleProps := resources["MyFunctionRole"].(map[string]interface{})["Properties"].(map[string]interface{})
+
+	managedPolicies := roleProps["ManagedPolicyArns"].([]interface{})
+	foundArn := false
+	for _, p := range managedPolicies {
+		if _, ok := p.(map[string]interface{}); ok {
+			t.Errorf("expected only managed policy ARNs, got %v", p)
+		}
+		if p == "arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess" {
+			foundArn = true
+		}
+	}
+	if !foundArn {
+		t.Errorf("expected AmazonS3ReadOnlyAccess in managed policies, got %v", managedPolicies)
+	}
+
+	policies := roleProps["Policies"].([]map[string]interface{})
+	names := make([]interface{}, 0, len(policies))
+	for _, p := range policies {
+		names = append(names, p["PolicyName"])
+	}
+	expected := []interface{}{"MyFunctionRolePolicy1", "MyFunctionRolePolicy2"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected inline policies %v, got %v", expected, names)
+	}
+
+	templateDoc, _ := json.Marshal(policies[0]["PolicyDocument"])
+	if !strings.Contains(string(templateDoc), "sqs:ReceiveMessage") {
+		t.Errorf("expected SQSPollerPolicy to be expanded, got %s", templateDoc)
+	}
+	inlineDoc, _ := json.Marshal(policies[1]["PolicyDocument"])
+	if !strings.Contains(string(inlineDoc), "dynamodb:GetItem") {
+		t.Errorf("expected inline statement to be kept, got %s", inlineDoc)
+	}
+}
+
 func TestFunctionTransformer_WithFunctionName(t *testing.T) {
 	transformer := NewFunctionTransformer()
 
@@ -510,6 +1265,7 @@ func TestFunctionTransformer_WithSnapStart(t *testing.T) {
 		SnapStart: map[string]interface{}{
 			"ApplyOn": "PublishedVersions",
 		},
+		AutoPublishAlias: "live",
 	}
 
 	resources, err := transformer.Transform("MyFunction", fn, nil)
@@ -644,6 +1400,67 @@ func TestFunctionTransformer_WithSNSEvent(t *testing.T) {
 	}
 }
 
+func TestFunctionTransformer_WithSNSEventSubscriptionOptions(t *testing.T) {
+	transformer := NewFunctionTransformer()
+
+	topic := map[string]interface{}{"Ref": "MyTopic"}
+	dlqArn := map[string]interface{}{"Fn::GetAtt": []interface{}{"MyDLQ", "Arn"}}
+	fn := &Function{
+		Handler: "index.handler",
+		Runtime: "nodejs18.x",
+		CodeUri: "s3://bucket/code.zip",
+		Events: map[string]interface{}{
+			"SNSEvent": map[string]interface{}{
+				"Type": "SNS",
+				"Properties": map[string]interface{}{
+					"Topic":              topic,
+					"RawMessageDelivery": true,
+					"RedrivePolicy": map[string]interface{}{
+						"deadLetterTargetArn": dlqArn,
+					},
+				},
+			},
+		},
+	}
+
+	resources, err := transformer.Transform("MyFunction", fn, nil)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	subscription := resources["MyFunctionSNSEventSubscription"].(map[string]interface{})
+	subProps := subscription["Properties"].(map[string]interface{})
+	if subProps["RawMessageDelivery"] != true {
+		t.Errorf("expected RawMessageDelivery true, got %v", subProps["RawMessageDelivery"])
+	}
+	redrive := subProps["RedrivePolicy"].(map[string]interface{})
+	if !reflect.DeepEqual(redrive["deadLetterTargetArn"], dlqArn) {
+		t.Errorf("expected RedrivePolicy deadLetterTargetArn %v, got %v", dlqArn, redrive["deadLetterTargetArn"])
+	}
+
+	policy, ok := resources["MyFunctionSNSEventDeadLetterQueuePolicy"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected MyFunctionSNSEventDeadLetterQueuePolicy resource")
+	}
+	if policy["Type"] != "AWS::SQS::QueuePolicy" {
+		t.Errorf("expected Type AWS::SQS::QueuePolicy, got %v", policy["Type"])
+	}
+	policyProps := policy["Properties"].(map[string]interface{})
+	expectedQueues := []interface{}{map[string]interface{}{"Ref": "MyDLQ"}}
+	if !reflect.DeepEqual(policyProps["Queues"], expectedQueues) {
+		t.Errorf("expected Queues %v, got %v", expectedQueues, policyProps["Queues"])
+	}
+	doc := policyProps["PolicyDocument"].(map[string]interface{})
+	statement := doc["Statement"].([]interface{})[0].(map[string]interface{})
+	if statement["Action"] != "sqs:SendMessage" {
+		t.Errorf("expected Action sqs:SendMessage, got %v", statement["Action"])
+	}
+	condition := statement["Condition"].(map[string]interface{})["ArnEquals"].(map[string]interface{})
+	if !reflect.DeepEqual(condition["aws:SourceArn"], topic) {
+		t.Errorf("expected SourceArn condition %v, got %v", topic, condition["aws:SourceArn"])
+	}
+}
+
 func TestFunctionTransformer_WithCloudWatchEvent(t *testing.T) {
 	transformer := NewFunctionTransformer()
 
@@ -713,6 +1530,80 @@ func TestFunctionTransformer_MultipleEvents(t *testing.T) {
 	}
 }
 
+func TestFunctionTransformer_EventLogicalIDCollision(t *testing.T) {
+	transformer := NewFunctionTransformer()
+
+	// Schedule event "Nightly" generates MyFunctionNightlyPermission, which is
+	// also the ESM logical ID of SQS event "NightlyPermission".
+	fn := &Function{
+		Handler: "index.handler",
+		Runtime: "nodejs18.x",
+		CodeUri: "s3://bucket/code.zip",
+		Events: map[string]interface{}{
+			"Nightly": map[string]interface{}{
+				"Type": "Schedule",
+				"Properties": map[string]interface{}{
+					"Schedule": "rate(1 day)",
+				},
+			},
+			"NightlyPermission": map[string]interface{}{
+				"Type": "SQS",
+				"Properties": map[string]interface{}{
+					"Queue": "arn:aws:sqs:us-east-1:123456789012:my-queue",
+				},
+			},
+		},
+	}
+
+	_, err := transformer.Transform("MyFunction", fn, nil)
+	if err == nil {
+		t.Fatal("expected error for colliding event logical IDs")
+	}
+	expected := "events 'Nightly' and 'NightlyPermission' both generate resource 'MyFunctionNightlyPermission'"
+	if !strings.Contains(err.Error(), expected) {
+		t.Errorf("expected error containing %q, got %v", expected, err)
+	}
+}
+
+func TestFunctionTransformer_ProvidedRuntime(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler string
+	}{
+		{name: "bootstrap handler", handler: "bootstrap"},
+		{name: "no handler", handler: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewFunctionTransformer()
+
+			fn := &Function{
+				Handler:       tt.handler,
+				Runtime:       "provided.al2023",
+				CodeUri:       "s3://bucket/code.zip",
+				Architectures: []string{"arm64"},
+			}
+
+			resources, err := transformer.Transform("MyFunction", fn, nil)
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			fnResource := resources["MyFunction"].(map[string]interface{})
+			props := fnResource["Properties"].(map[string]interface{})
+			if props["Runtime"] != "provided.al2023" {
+				t.Errorf("expected Runtime 'provided.al2023', got %v", props["Runtime"])
+			}
+			if handler, ok := props["Handler"]; tt.handler == "" && ok {
+				t.Errorf("expected no Handler, got %v", handler)
+			} else if tt.handler != "" && handler != tt.handler {
+				t.Errorf("expected Handler %q, got %v", tt.handler, handler)
+			}
+		})
+	}
+}
+
 func TestFunctionTransformer_WithDescription(t *testing.T) {
 	transformer := NewFunctionTransformer()
 
@@ -738,12 +1629,11 @@ func TestFunctionTransformer_WithDescription(t *testing.T) {
 func TestFunctionTransformer_WithReservedConcurrentExecutions(t *testing.T) {
 	transformer := NewFunctionTransformer()
 
-	reserved := 100
 	fn := &Function{
 		Handler:                      "index.handler",
 		Runtime:                      "nodejs18.x",
 		CodeUri:                      "s3://bucket/code.zip",
-		ReservedConcurrentExecutions: &reserved,
+		ReservedConcurrentExecutions: 100,
 	}
 
 	resources, err := transformer.Transform("MyFunction", fn, nil)
@@ -848,6 +1738,105 @@ func TestFunctionTransformer_WithDeadLetterConfig(t *testing.T) {
 	}
 }
 
+func TestFunctionTransformer_AutoCreatedDeadLetterQueue(t *testing.T) {
+	tests := []struct {
+		name       string
+		dlqType    string
+		wantType   string
+		wantArn    interface{}
+		wantAction string
+	}{
+		{
+			name:       "sqs",
+			dlqType:    "SQS",
+			wantType:   "AWS::SQS::Queue",
+			wantArn:    map[string]interface{}{"Fn::GetAtt": []interface{}{"MyFunctionDeadLetterQueue", "Arn"}},
+			wantAction: "sqs:SendMessage",
+		},
+		{
+			name:       "sns",
+			dlqType:    "SNS",
+			wantType:   "AWS::SNS::Topic",
+			wantArn:    map[string]interface{}{"Ref": "MyFunctionDeadLetterQueue"},
+			wantAction: "sns:Publish",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewFunctionTransformer()
+			fn := &Function{
+				Handler:         "index.handler",
+				Runtime:         "nodejs18.x",
+				CodeUri:         "s3://bucket/code.zip",
+				DeadLetterQueue: map[string]interface{}{"Type": tt.dlqType},
+			}
+
+			resources, err := transformer.Transform("MyFunction", fn, nil)
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			dlq, ok := resources["MyFunctionDeadLetterQueue"].(map[string]interface{})
+			if !ok {
+				t.Fatal("expected MyFunctionDeadLetterQueue resource")
+			}
+			if dlq["Type"] != tt.wantType {
+				t.Errorf("expected Type %s, got %v", tt.wantType, dlq["Type"])
+			}
+
+			props := resources["MyFunction"].(map[string]interface{})["Properties"].(map[string]interface{})
+			dlc := props["DeadLetterConfig"].(map[string]interface{})
+			if !reflect.DeepEqual(dlc["TargetArn"], tt.wantArn) {
+				t.Errorf("expected TargetArn %v, got %v", tt.wantArn, dlc["TargetArn"])
+			}
+
+			roleProps := resources["MyFunctionRole"].(map[string]interface{})["Properties"].(map[string]interface{})
+			policies, ok := roleProps["Policies"].([]map[string]interface{})
+			if !ok || len(policies) != 1 || policies[0]["PolicyName"] != "DeadLetterQueuePolicy" {
+				t.Fatalf("expected DeadLetterQueuePolicy, got %v", roleProps["Policies"])
+			}
+			doc := policies[0]["PolicyDocument"].(map[string]interface{})
+			stmt := doc["Statement"].([]interface{})[0].(map[string]interface{})
+			if stmt["Action"] != tt.wantAction {
+				t.Errorf("expected Action %s, got %v", tt.wantAction, stmt["Action"])
+			}
+			if !reflect.DeepEqual(stmt["Resource"], tt.wantArn) {
+				t.Errorf("expected Resource %v, got %v", tt.wantArn, stmt["Resource"])
+			}
+		})
+	}
+}
+
+func TestFunctionTransformer_DeadLetterQueueValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		want   string
+	}{
+		{name: "missing type and target", config: map[string]interface{}{}, want: "must specify Type"},
+		{name: "intrinsic type", config: map[string]interface{}{"Type": map[string]interface{}{"Ref": "DlqType"}, "TargetArn": "arn"}, want: "should be a string"},
+		{name: "unknown type", config: map[string]interface{}{"Type": "Kinesis"}, want: "must be one of [SNS, SQS]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewFunctionTransformer()
+			fn := &Function{
+				Handler:         "index.handler",
+				Runtime:         "nodejs18.x",
+				CodeUri:         "s3://bucket/code.zip",
+				DeadLetterQueue: tt.config,
+			}
+
+			_, err := transformer.Transform("MyFunction", fn, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
 func TestFunctionTransformer_WithKmsKeyArn(t *testing.T) {
 	transformer := NewFunctionTransformer()
 
@@ -870,6 +1859,315 @@ func TestFunctionTransformer_WithKmsKeyArn(t *testing.T) {
 	}
 }
 
+func TestFunctionTransformer_KmsKeyArnGrantsDecrypt(t *testing.T) {
+	transformer := NewFunctionTransformer()
+
+	keyArn := map[string]interface{}{"Fn::GetAtt": []interface{}{"MyKey", "Arn"}}
+	fn := &Function{
+		Handler:   "index.handler",
+		Runtime:   "nodejs18.x",
+		CodeUri:   "s3://bucket/code.zip",
+		KmsKeyArn: keyArn,
+	}
+
+	resources, err := transformer.Transform("MyFunction", fn, nil)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	roleResource := resources["MyFunctionRole"].(map[string]interface{})
+	roleProps := roleResource["Properties"].(map[string]interface{})
+	policies := roleProps["Policies"].([]map[string]interface{})
+	if len(policies) != 1 {
+		t.Fatalf("expected 1 inline policy, got %d", len(policies))
+	}
+	if policies[0]["PolicyName"] != "MyFunctionKmsDecryptPolicy" {
+		t.Errorf("expected PolicyName 'MyFunctionKmsDecryptPolicy', got %v", policies[0]["PolicyName"])
+	}
+
+	doc := policies[0]["PolicyDocument"].(map[string]interface{})
+	stmt := doc["Statement"].([]interface{})[0].(map[string]interface{})
+	if stmt["Action"] != "kms:Decrypt" {
+		t.Errorf("expected Action 'kms:Decrypt', got %v", stmt["Action"])
+	}
+	if !reflect.DeepEqual(stmt["Resource"], keyArn) {
+		t.Errorf("expected Resource to be the key ARN intrinsic, got %v", stmt["Resource"])
+	}
+}
+
+func TestFunctionTransformer_KmsKeyArnWithExplicitRole(t *testing.T) {
+	transformer := NewFunctionTransformer()
+
+	fn := &Function{
+		Handler:   "index.handler",
+		Runtime:   "nodejs18.x",
+		CodeUri:   "s3://bucket/code.zip",
+		Role:      "arn:aws:iam::123456789012:role/my-role",
+		KmsKeyArn: "arn:aws:kms:us-east-1:123456789012:key/abc",
+	}
+
+	resources, err := transformer.Transform("MyFunction", fn, nil)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if _, ok := resources["MyFunctionRole"]; ok {
+		t.Error("expected no generated role when Role is provided")
+	}
+}
+
+func TestFunctionTransformer_RolePath(t *testing.T) {
+	tests := []struct {
+		name            string
+		rolePath        string
+		defaultRolePath string
+		expectedPath    interface{}
+	}{
+		{name: "explicit path", rolePath: "/service/", expectedPath: "/service/"},
+		{name: "default path", defaultRolePath: "/org/", expectedPath: "/org/"},
+		{name: "explicit overrides default", rolePath: "/service/", defaultRolePath: "/org/", expectedPath: "/service/"},
+		{name: "no path", expectedPath: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewFunctionTransformer()
+			transformer.DefaultRolePath = tt.defaultRolePath
+
+			fn := &Function{
+				Handler:  "index.handler",
+				Runtime:  "nodejs18.x",
+				CodeUri:  "s3://bucket/code.zip",
+				RolePath: tt.rolePath,
+			}
+
+			resources, err := transformer.Transform("MyFunction", fn, nil)
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			role := resources["MyFunctionRole"].(map[string]interface{})
+			props := role["Properties"].(map[string]interface{})
+			path, ok := props["Path"]
+			if tt.expectedPath == nil {
+				if ok {
+					t.Errorf("expected Path to be unset, got %v", path)
+				}
+				return
+			}
+			if path != tt.expectedPath {
+				t.Errorf("expected Path %v, got %v", tt.expectedPath, path)
+			}
+		})
+	}
+}
+
+func TestFunctionTransformer_RolePathAndPermissionsBoundary(t *testing.T) {
+	boundary := "arn:aws:iam::123456789012:policy/boundary"
+
+	tests := []struct {
+		name            string
+		role            interface{}
+		defaultRolePath string
+		boundary        interface{}
+		expectRole      bool
+		expectedPath    interface{}
+		wantErr         string
+	}{
+		{name: "path and boundary", boundary: boundary, defaultRolePath: "/org/", expectRole: true, expectedPath: "/org/"},
+		{name: "intrinsic boundary", boundary: map[string]interface{}{"Ref": "Boundary"}, defaultRolePath: "/org/", expectRole: true, expectedPath: "/org/"},
+		{name: "user role suppresses both", role: "arn:aws:iam::123456789012:role/existing", boundary: boundary, defaultRolePath: "/org/"},
+		{name: "invalid boundary", boundary: "boundary", wantErr: "PermissionsBoundary must be an ARN or an intrinsic function, got boundary"},
+		{name: "non-string boundary", boundary: []interface{}{boundary}, wantErr: "PermissionsBoundary must be an ARN or an intrinsic function"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewFunctionTransformer()
+			transformer.DefaultRolePath = tt.defaultRolePath
+
+			fn := &Function{
+				Handler:             "index.handler",
+				Runtime:             "nodejs18.x",
+				CodeUri:             "s3://bucket/code.zip",
+				Role:                tt.role,
+				PermissionsBoundary: tt.boundary,
+			}
+
+			resources, err := transformer.Transform("MyFunction", fn, nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			role, ok := resources["MyFunctionRole"].(map[string]interface{})
+			if !tt.expectRole {
+				if ok {
+					t.Fatalf("expected no generated role when Role is provided, got %v", role)
+				}
+				fnProps := resources["MyFunction"].(map[string]interface{})["Properties"].(map[string]interface{})
+				if fnProps["Role"] != tt.role {
+					t.Errorf("expected function Role %v, got %v", tt.role, fnProps["Role"])
+				}
+				return
+			}
+
+			props := role["Properties"].(map[string]interface{})
+			if props["Path"] != tt.expectedPath {
+				t.Errorf("expected Path %v, got %v", tt.expectedPath, props["Path"])
+			}
+			if !reflect.DeepEqual(props["PermissionsBoundary"], tt.boundary) {
+				t.Errorf("expected PermissionsBoundary %v, got %v", tt.boundary, props["PermissionsBoundary"])
+			}
+		})
+	}
+}
+
+func TestFunctionTransformer_InlinePolicyNestedIntrinsics(t *testing.T) {
+	transformer := NewFunctionTransformer()
+
+	statement := map[string]interface{}{
+		"Effect": "Allow",
+		"Action": []interface{}{"s3:GetObject"},
+		"Resource": map[string]interface{}{
+			"Fn::Sub": "arn:${AWS::Partition}:s3:::${Bucket}/*",
+		},
+		"Condition": map[string]interface{}{
+			"StringEquals": map[string]interface{}{
+				"aws:PrincipalArn": map[string]interface{}{
+					"Fn::Sub": []interface{}{
+						"arn:${AWS::Partition}:iam::${AWS::AccountId}:role/${RoleName}",
+						map[string]interface{}{
+							"RoleName": map[string]interface{}{"Fn::Select": []interface{}{0, map[string]interface{}{"Ref": "RoleNames"}}},
+						},
+					},
+				},
+			},
+		},
+	}
+	expected, err := json.Marshal(statement)
+	if err != nil {
+		t.Fatalf("failed to marshal statement: %v", err)
+	}
+
+	fn := &Function{
+		Handler: "index.handler",
+		Runtime: "nodejs18.x",
+		CodeUri: "s3://bucket/code.zip",
+		Policies: []interface{}{
+			map[string]interface{}{
+				"Statement": []interface{}{statement},
+			},
+		},
+	}
+
+	resources, err := transformer.Transform("MyFunction", fn, nil)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	props := resources["MyFunctionRole"].(map[string]interface{})["Properties"].(map[string]interface{})
+	policies := props["Policies"].([]map[string]interface{})
+	doc := policies[0]["PolicyDocument"].(map[string]interface{})
+	statements := doc["Statement"].([]interface{})
+
+	actual, err := json.Marshal(statements[0])
+	if err != nil {
+		t.Fatalf("failed to marshal output statement: %v", err)
+	}
+	if string(actual) != string(expected) {
+		t.Errorf("expected statement to round-trip unchanged\nexpected: %s\ngot:      %s", expected, actual)
+	}
+}
+
+func TestFunctionTransformer_LocalCodeUri(t *testing.T) {
+	fn := &Function{
+		Handler: "index.handler",
+		Runtime: "nodejs18.x",
+		CodeUri: "./src",
+	}
+
+	t.Run("rejected by default", func(t *testing.T) {
+		transformer := NewFunctionTransformer()
+		_, err := transformer.Transform("MyFunction", fn, nil)
+		if err == nil {
+			t.Fatal("expected error for local CodeUri")
+		}
+		if !strings.Contains(err.Error(), "CodeUri './src' is a local path") {
+			t.Errorf("expected local path error, got %v", err)
+		}
+	})
+
+	t.Run("placeholder when allowed", func(t *testing.T) {
+		transformer := NewFunctionTransformer()
+		transformer.AllowLocalCodeUri = true
+
+		resources, err := transformer.Transform("MyFunction", fn, nil)
+		if err != nil {
+			t.Fatalf("Transform failed: %v", err)
+		}
+
+		props := resources["MyFunction"].(map[string]interface{})["Properties"].(map[string]interface{})
+		expected := map[string]interface{}{
+			"S3Bucket": LocalCodeUriPlaceholderBucket,
+			"S3Key":    "./src",
+		}
+		if !reflect.DeepEqual(props["Code"], expected) {
+			t.Errorf("expected Code %v, got %v", expected, props["Code"])
+		}
+	})
+}
+
+func TestFunctionTransformer_ImageUri(t *testing.T) {
+	tests := []struct {
+		name       string
+		imageUri   interface{}
+		allowLocal bool
+		wantErr    string
+	}{
+		{name: "ECR URI accepted", imageUri: "123456789012.dkr.ecr.us-east-1.amazonaws.com/my-repo:latest"},
+		{name: "ECR URI with digest accepted", imageUri: "123456789012.dkr.ecr.cn-north-1.amazonaws.com.cn/my-repo@sha256:abc123"},
+		{name: "intrinsic accepted", imageUri: map[string]interface{}{"Fn::Sub": "${AWS::AccountId}.dkr.ecr.${AWS::Region}.amazonaws.com/repo:tag"}},
+		{name: "local name rejected", imageUri: "myfunction:latest", wantErr: "ImageUri 'myfunction:latest' is a local image"},
+		{name: "local repo path rejected", imageUri: "team/myfunction", wantErr: "ImageUri 'team/myfunction' is a local image"},
+		{name: "local name allowed", imageUri: "myfunction:latest", allowLocal: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewFunctionTransformer()
+			transformer.AllowLocalCodeUri = tt.allowLocal
+
+			fn := &Function{
+				PackageType: "Image",
+				ImageUri:    tt.imageUri,
+			}
+
+			resources, err := transformer.Transform("MyFunction", fn, nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			props := resources["MyFunction"].(map[string]interface{})["Properties"].(map[string]interface{})
+			expected := map[string]interface{}{"ImageUri": tt.imageUri}
+			if !reflect.DeepEqual(props["Code"], expected) {
+				t.Errorf("expected Code %v, got %v", expected, props["Code"])
+			}
+		})
+	}
+}
+
 func TestFunctionTransformer_WithEphemeralStorage(t *testing.T) {
 	transformer := NewFunctionTransformer()
 
@@ -972,6 +2270,68 @@ func TestFunctionTransformer_WithIoTRuleEvent(t *testing.T) {
 	}
 }
 
+func TestFunctionTransformer_IoTRuleEventRuleNameAndErrorAction(t *testing.T) {
+	errorAction := map[string]interface{}{
+		"CloudwatchLogs": map[string]interface{}{
+			"LogGroupName": "iot-errors",
+			"RoleArn":      "arn:aws:iam::123456789012:role/iot",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		props   map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "rule name and error action",
+			props: map[string]interface{}{
+				"Sql":         "SELECT * FROM 'topic/test'",
+				"RuleName":    "MyRule",
+				"ErrorAction": errorAction,
+			},
+		},
+		{name: "missing sql", props: map[string]interface{}{"RuleName": "MyRule"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewFunctionTransformer()
+			fn := &Function{
+				Handler: "index.handler",
+				Runtime: "nodejs18.x",
+				CodeUri: "s3://bucket/code.zip",
+				Events: map[string]interface{}{
+					"IoTRule": map[string]interface{}{
+						"Type":       "IoTRule",
+						"Properties": tt.props,
+					},
+				},
+			}
+
+			resources, err := transformer.Transform("MyFunction", fn, nil)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "Sql") {
+					t.Errorf("expected Sql error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			ruleProps := resources["MyFunctionIoTRule"].(map[string]interface{})["Properties"].(map[string]interface{})
+			if ruleProps["RuleName"] != "MyRule" {
+				t.Errorf("expected RuleName MyRule, got %v", ruleProps["RuleName"])
+			}
+			payload := ruleProps["TopicRulePayload"].(map[string]interface{})
+			if !reflect.DeepEqual(payload["ErrorAction"], errorAction) {
+				t.Errorf("expected ErrorAction %v, got %v", errorAction, payload["ErrorAction"])
+			}
+		})
+	}
+}
+
 func TestFunctionTransformer_WithCognitoEvent(t *testing.T) {
 	transformer := NewFunctionTransformer()
 
@@ -996,11 +2356,141 @@ func TestFunctionTransformer_WithCognitoEvent(t *testing.T) {
 	}
 
 	// Should create Lambda permission for Cognito
-	if _, hasPermission := resources["MyFunctionCognitoEventPermission"]; !hasPermission {
+	if _, hasPermission := resources["MyFunctionCognitoPermission"]; !hasPermission {
 		t.Error("should create Lambda permission for Cognito event")
 	}
 }
 
+func TestFunctionTransformer_CognitoEventTriggers(t *testing.T) {
+	tests := []struct {
+		name    string
+		trigger interface{}
+		wantErr string
+	}{
+		{name: "single trigger", trigger: "PreSignUp"},
+		{name: "list of triggers", trigger: []interface{}{"PreSignUp", "PostConfirmation"}},
+		{name: "invalid trigger name", trigger: []interface{}{"PreSignUp", "PreSignIn"}, wantErr: "Cognito trigger 'PreSignIn' must be one of"},
+		{name: "repeated trigger", trigger: []interface{}{"PreSignUp", "PreSignUp"}, wantErr: "defined multiple times"},
+		{name: "intrinsic trigger", trigger: map[string]interface{}{"Fn::Join": []interface{}{"", []interface{}{"Pre", "SignUp"}}}, wantErr: "type of property 'Trigger' is invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewFunctionTransformer()
+			fn := &Function{
+				Handler: "index.handler",
+				Runtime: "nodejs18.x",
+				CodeUri: "s3://bucket/code.zip",
+				Events: map[string]interface{}{
+					"SignUp": map[string]interface{}{
+						"Type": "Cognito",
+						"Properties": map[string]interface{}{
+							"UserPool": map[string]interface{}{"Ref": "MyUserPool"},
+							"Trigger":  tt.trigger,
+						},
+					},
+					"Confirm": map[string]interface{}{
+						"Type": "Cognito",
+						"Properties": map[string]interface{}{
+							"UserPool": map[string]interface{}{"Ref": "MyUserPool"},
+							"Trigger":  "CustomMessage",
+						},
+					},
+				},
+			}
+
+			resources, err := transformer.Transform("MyFunction", fn, nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			var permissions []string
+			for id, res := range resources {
+				if res.(map[string]interface{})["Type"] == "AWS::Lambda::Permission" {
+					permissions = append(permissions, id)
+				}
+			}
+			if len(permissions) != 1 || permissions[0] != "MyFunctionCognitoPermission" {
+				t.Errorf("expected a single MyFunctionCognitoPermission, got %v", permissions)
+			}
+
+			triggers := CognitoTriggers("MyFunction", fn)
+			if len(triggers) != 2 || triggers[1].EventName != "SignUp" {
+				t.Fatalf("expected triggers for both events, got %v", triggers)
+			}
+			want := tt.trigger
+			if name, ok := want.(string); ok {
+				want = []interface{}{name}
+			}
+			if len(triggers[1].Triggers) != len(want.([]interface{})) {
+				t.Errorf("expected triggers %v, got %v", want, triggers[1].Triggers)
+			}
+		})
+	}
+}
+
+func TestFunctionTransformer_AlexaSkillEvent(t *testing.T) {
+	tests := []struct {
+		name      string
+		props     map[string]interface{}
+		wantToken interface{}
+		wantErr   bool
+	}{
+		{
+			name:      "valid skill ID",
+			props:     map[string]interface{}{"SkillId": "amzn1.ask.skill.12345678-1234-1234-1234-123456789012"},
+			wantToken: "amzn1.ask.skill.12345678-1234-1234-1234-123456789012",
+		},
+		{
+			name:      "intrinsic skill ID",
+			props:     map[string]interface{}{"SkillId": map[string]interface{}{"Ref": "SkillId"}},
+			wantToken: map[string]interface{}{"Ref": "SkillId"},
+		},
+		{name: "no skill ID", props: map[string]interface{}{}},
+		{name: "malformed skill ID", props: map[string]interface{}{"SkillId": "my-skill"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewFunctionTransformer()
+			fn := &Function{
+				Handler: "index.handler",
+				Runtime: "nodejs18.x",
+				CodeUri: "s3://bucket/code.zip",
+				Events: map[string]interface{}{
+					"Alexa": map[string]interface{}{
+						"Type":       "AlexaSkill",
+						"Properties": tt.props,
+					},
+				},
+			}
+
+			resources, err := transformer.Transform("MyFunction", fn, nil)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "SkillId") {
+					t.Errorf("expected SkillId error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			permission := resources["MyFunctionAlexaPermission"].(map[string]interface{})
+			props := permission["Properties"].(map[string]interface{})
+			if !reflect.DeepEqual(props["EventSourceToken"], tt.wantToken) {
+				t.Errorf("expected EventSourceToken %v, got %v", tt.wantToken, props["EventSourceToken"])
+			}
+		})
+	}
+}
+
 func TestFunctionTransformer_WithMSKEvent(t *testing.T) {
 	transformer := NewFunctionTransformer()
 
@@ -1030,3 +2520,157 @@ func TestFunctionTransformer_WithMSKEvent(t *testing.T) {
 		t.Error("should create EventSourceMapping for MSK event")
 	}
 }
+
+func TestFunctionTransformer_WithFileSystemConfigs(t *testing.T) {
+	transformer := NewFunctionTransformer()
+
+	accessPoint := map[string]interface{}{"Fn::GetAtt": []interface{}{"MyAccessPoint", "Arn"}}
+	fn := &Function{
+		Handler: "index.handler",
+		Runtime: "nodejs18.x",
+		CodeUri: "s3://bucket/code.zip",
+		VpcConfig: map[string]interface{}{
+			"SecurityGroupIds": []interface{}{"sg-12345678"},
+			"SubnetIds":        []interface{}{"subnet-12345678"},
+		},
+		FileSystemConfigs: []map[string]interface{}{
+			{"Arn": accessPoint, "LocalMountPath": "/mnt/efs"},
+		},
+	}
+
+	resources, err := transformer.Transform("MyFunction", fn, nil)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	roleResource := resources["MyFunctionRole"].(map[string]interface{})
+	roleProps := roleResource["Properties"].(map[string]interface{})
+	policies := roleProps["Policies"].([]map[string]interface{})
+	if len(policies) != 1 || policies[0]["PolicyName"] != "MyFunctionEfsClientPolicy" {
+		t.Fatalf("expected MyFunctionEfsClientPolicy, got %v", policies)
+	}
+
+	doc := policies[0]["PolicyDocument"].(map[string]interface{})
+	stmt := doc["Statement"].([]interface{})[0].(map[string]interface{})
+	expectedActions := []interface{}{"elasticfilesystem:ClientMount", "elasticfilesystem:ClientWrite"}
+	if !reflect.DeepEqual(stmt["Action"], expectedActions) {
+		t.Errorf("expected Action %v, got %v", expectedActions, stmt["Action"])
+	}
+	if !reflect.DeepEqual(stmt["Resource"], accessPoint) {
+		t.Errorf("expected Resource to be the access point ARN, got %v", stmt["Resource"])
+	}
+}
+
+func TestFunctionTransformer_WithProvisionedConcurrencyAutoScaling(t *testing.T) {
+	transformer := NewFunctionTransformer()
+
+	fn := &Function{
+		Handler:          "index.handler",
+		Runtime:          "nodejs18.x",
+		CodeUri:          "s3://bucket/code.zip",
+		AutoPublishAlias: "live",
+		ProvisionedConcurrencyConfig: map[string]interface{}{
+			"ProvisionedConcurrentExecutions": 5,
+		},
+		ProvisionedConcurrencyAutoScaling: map[string]interface{}{
+			"MinCapacity":       5,
+			"MaxCapacity":       20,
+			"TargetUtilization": 0.5,
+		},
+	}
+
+	resources, err := transformer.Transform("MyFunction", fn, nil)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	target, ok := resources["MyFunctionAliasliveScalableTarget"].(map[string]interface{})
+	if !ok {
+		t.Fatal("should create ScalableTarget for the alias")
+	}
+	if target["Type"] != "AWS::ApplicationAutoScaling::ScalableTarget" {
+		t.Errorf("expected Type 'AWS::ApplicationAutoScaling::ScalableTarget', got %v", target["Type"])
+	}
+	if target["DependsOn"] != "MyFunctionAliaslive" {
+		t.Errorf("expected DependsOn 'MyFunctionAliaslive', got %v", target["DependsOn"])
+	}
+
+	targetProps := target["Properties"].(map[string]interface{})
+	expectedResourceID := map[string]interface{}{
+		"Fn::Join": []interface{}{":", []interface{}{
+			"function",
+			map[string]interface{}{"Ref": "MyFunction"},
+			"live",
+		}},
+	}
+	if !reflect.DeepEqual(targetProps["ResourceId"], expectedResourceID) {
+		t.Errorf("expected ResourceId to reference the alias, got %v", targetProps["ResourceId"])
+	}
+	if targetProps["ScalableDimension"] != "lambda:function:ProvisionedConcurrency" {
+		t.Errorf("unexpected ScalableDimension %v", targetProps["ScalableDimension"])
+	}
+	if targetProps["MinCapacity"] != 5 || targetProps["MaxCapacity"] != 20 {
+		t.Errorf("unexpected capacity %v-%v", targetProps["MinCapacity"], targetProps["MaxCapacity"])
+	}
+
+	policy, ok := resources["MyFunctionAliasliveScalingPolicy"].(map[string]interface{})
+	if !ok {
+		t.Fatal("should create ScalingPolicy")
+	}
+	policyProps := policy["Properties"].(map[string]interface{})
+	if !reflect.DeepEqual(policyProps["ScalingTargetId"], map[string]interface{}{"Ref": "MyFunctionAliasliveScalableTarget"}) {
+		t.Errorf("expected ScalingTargetId to reference the target, got %v", policyProps["ScalingTargetId"])
+	}
+	tracking := policyProps["TargetTrackingScalingPolicyConfiguration"].(map[string]interface{})
+	if tracking["TargetValue"] != 0.5 {
+		t.Errorf("expected TargetValue 0.5, got %v", tracking["TargetValue"])
+	}
+}
+
+func TestFunctionTransformer_ProvisionedConcurrencyAutoScalingErrors(t *testing.T) {
+	transformer := NewFunctionTransformer()
+
+	t.Run("missing alias", func(t *testing.T) {
+		fn := &Function{
+			Handler: "index.handler",
+			Runtime: "nodejs18.x",
+			CodeUri: "s3://bucket/code.zip",
+			ProvisionedConcurrencyAutoScaling: map[string]interface{}{
+				"MinCapacity": 1,
+				"MaxCapacity": 2,
+			},
+		}
+		if _, err := transformer.Transform("MyFunction", fn, nil); err == nil {
+			t.Error("expected error when AutoPublishAlias is not set")
+		}
+	})
+
+	t.Run("missing capacity", func(t *testing.T) {
+		fn := &Function{
+			Handler:                           "index.handler",
+			Runtime:                           "nodejs18.x",
+			CodeUri:                           "s3://bucket/code.zip",
+			AutoPublishAlias:                  "live",
+			ProvisionedConcurrencyAutoScaling: map[string]interface{}{"MinCapacity": 1},
+		}
+		if _, err := transformer.Transform("MyFunction", fn, nil); err == nil {
+			t.Error("expected error when MaxCapacity is missing")
+		}
+	})
+
+	t.Run("absent by default", func(t *testing.T) {
+		fn := &Function{
+			Handler:          "index.handler",
+			Runtime:          "nodejs18.x",
+			CodeUri:          "s3://bucket/code.zip",
+			AutoPublishAlias: "live",
+		}
+		resources, err := transformer.Transform("MyFunction", fn, nil)
+		if err != nil {
+			t.Fatalf("Transform failed: %v", err)
+		}
+		if _, ok := resources["MyFunctionAliasliveScalableTarget"]; ok {
+			t.Error("should not create ScalableTarget without ProvisionedConcurrencyAutoScaling")
+		}
+	})
+}
@@ -0,0 +1,331 @@
+// Package sam provides SAM resource transformers.
+package sam
+
+import (
+	"fmt"
+	"strings"
+
+	samerrors "github.com/lex00/aws-sam-translator-go/pkg/errors"
+	"github.com/lex00/aws-sam-translator-go/pkg/intrinsics"
+)
+
+// validate checks cross-property constraints on a SAM Function that
+// CloudFormation would otherwise reject at deploy time.
+func (t *FunctionTransformer) validate(logicalID string, f *Function) error {
+	// Lambda can only mount EFS from within a VPC
+	if len(f.FileSystemConfigs) > 0 && f.VpcConfig == nil {
+		return &samerrors.InvalidResourceException{
+			ResourceID: logicalID,
+			Message:    "FileSystemConfigs requires VpcConfig to be set",
+		}
+	}
+
+	if err := t.validateVpcConfig(logicalID, f.VpcConfig); err != nil {
+		return err
+	}
+
+	// RecursiveLoop is a fixed enum
+	if f.RecursiveLoop != "" && !containsString(validRecursiveLoopValues, f.RecursiveLoop) {
+		return &samerrors.InvalidResourceException{
+			ResourceID: logicalID,
+			Message: fmt.Sprintf("RecursiveLoop must be one of [%s], got '%s'",
+				strings.Join(validRecursiveLoopValues, ", "), f.RecursiveLoop),
+		}
+	}
+
+	// Intrinsic values are resolved at deploy time, so only literals are checked
+	if reserved, ok := numberValue(f.ReservedConcurrentExecutions); ok && reserved < 0 {
+		return &samerrors.InvalidResourceException{
+			ResourceID: logicalID,
+			Message:    fmt.Sprintf("ReservedConcurrentExecutions must not be negative, got %v", f.ReservedConcurrentExecutions),
+		}
+	}
+
+	if err := t.validateDeadLetterQueue(logicalID, f.DeadLetterQueue); err != nil {
+		return err
+	}
+
+	if err := t.validateRuntimeManagementConfig(logicalID, f.RuntimeManagementConfig); err != nil {
+		return err
+	}
+
+	if err := t.validateLoggingConfig(logicalID, f.LoggingConfig); err != nil {
+		return err
+	}
+
+	if err := t.validateSnapStart(logicalID, f); err != nil {
+		return err
+	}
+
+	if err := t.validateProvisionedConcurrencyAutoScaling(logicalID, f); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateProvisionedConcurrencyAutoScaling requires an alias to scale and
+// both capacities, and checks literal capacities are at least 1 with
+// MinCapacity no greater than MaxCapacity.
+func (t *FunctionTransformer) validateProvisionedConcurrencyAutoScaling(logicalID string, f *Function) error {
+	config := f.ProvisionedConcurrencyAutoScaling
+	if config == nil {
+		return nil
+	}
+
+	// Auto scaling targets the alias, so one must be published
+	if f.AutoPublishAlias == "" {
+		return &samerrors.InvalidResourceException{
+			ResourceID: logicalID,
+			Message:    "ProvisionedConcurrencyAutoScaling requires AutoPublishAlias",
+		}
+	}
+
+	minCapacity, hasMin := config["MinCapacity"]
+	maxCapacity, hasMax := config["MaxCapacity"]
+	if !hasMin || !hasMax {
+		return &samerrors.InvalidResourceException{
+			ResourceID: logicalID,
+			Message:    "ProvisionedConcurrencyAutoScaling requires MinCapacity and MaxCapacity",
+		}
+	}
+
+	// Intrinsic capacities are resolved at deploy time, so only literals are checked
+	minValue, minIsNumber := numberValue(minCapacity)
+	maxValue, maxIsNumber := numberValue(maxCapacity)
+	if (minIsNumber && minValue < 1) || (maxIsNumber && maxValue < 1) {
+		return &samerrors.InvalidResourceException{
+			ResourceID: logicalID,
+			Message:    fmt.Sprintf("ProvisionedConcurrencyAutoScaling MinCapacity and MaxCapacity must be at least 1, got %v and %v", minCapacity, maxCapacity),
+		}
+	}
+	if minIsNumber && maxIsNumber && minValue > maxValue {
+		return &samerrors.InvalidResourceException{
+			ResourceID: logicalID,
+			Message:    fmt.Sprintf("ProvisionedConcurrencyAutoScaling MinCapacity (%v) must not be greater than MaxCapacity (%v)", minValue, maxValue),
+		}
+	}
+
+	return nil
+}
+
+// validateVpcConfig requires a non-empty VpcConfig to list both subnets and
+// security groups. A VpcConfig given as an intrinsic is left for CloudFormation.
+func (t *FunctionTransformer) validateVpcConfig(logicalID string, config map[string]interface{}) error {
+	if len(config) == 0 || intrinsics.IsIntrinsic(config) {
+		return nil
+	}
+
+	var missing []string
+	for _, key := range []string{"SubnetIds", "SecurityGroupIds"} {
+		if value, ok := config[key]; !ok || value == nil {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return &samerrors.InvalidResourceException{
+			ResourceID: logicalID,
+			Message:    fmt.Sprintf("VpcConfig must include SubnetIds and SecurityGroupIds; missing %s", strings.Join(missing, ", ")),
+		}
+	}
+
+	return nil
+}
+
+// validRecursiveLoopValues are the accepted values for RecursiveLoop.
+var validRecursiveLoopValues = []string{"Allow", "Terminate"}
+
+// validDeadLetterQueueTypes are the accepted values for DeadLetterQueue.Type.
+var validDeadLetterQueueTypes = []string{"SNS", "SQS"}
+
+// validateDeadLetterQueue checks DeadLetterQueue.Type, which decides the
+// permission granted and, without a TargetArn, the resource generated.
+func (t *FunctionTransformer) validateDeadLetterQueue(logicalID string, config map[string]interface{}) error {
+	if config == nil {
+		return nil
+	}
+
+	value, hasType := config["Type"]
+	if !hasType {
+		if _, hasTarget := config["TargetArn"]; !hasTarget {
+			return &samerrors.InvalidResourceException{
+				ResourceID: logicalID,
+				Message:    "DeadLetterQueue must specify Type when TargetArn is not set",
+			}
+		}
+		return nil
+	}
+
+	dlqType, ok := value.(string)
+	if !ok {
+		return &samerrors.InvalidResourceException{
+			ResourceID: logicalID,
+			Message:    "Property 'DeadLetterQueue.Type' should be a string.",
+		}
+	}
+	if !containsString(validDeadLetterQueueTypes, dlqType) {
+		return &samerrors.InvalidResourceException{
+			ResourceID: logicalID,
+			Message: fmt.Sprintf("DeadLetterQueue.Type must be one of [%s], got '%s'",
+				strings.Join(validDeadLetterQueueTypes, ", "), dlqType),
+		}
+	}
+
+	return nil
+}
+
+// validUpdateRuntimeOnValues are the accepted values for RuntimeManagementConfig.UpdateRuntimeOn.
+var validUpdateRuntimeOnValues = []string{"Auto", "FunctionUpdate", "Manual"}
+
+// validateRuntimeManagementConfig checks UpdateRuntimeOn and its RuntimeVersionArn requirement.
+// Intrinsic values are left for CloudFormation to resolve.
+func (t *FunctionTransformer) validateRuntimeManagementConfig(logicalID string, config map[string]interface{}) error {
+	if config == nil {
+		return nil
+	}
+
+	updateOn, ok := config["UpdateRuntimeOn"].(string)
+	if !ok {
+		return nil
+	}
+
+	if !containsString(validUpdateRuntimeOnValues, updateOn) {
+		return &samerrors.InvalidResourceException{
+			ResourceID: logicalID,
+			Message: fmt.Sprintf("RuntimeManagementConfig.UpdateRuntimeOn must be one of [%s], got '%s'",
+				strings.Join(validUpdateRuntimeOnValues, ", "), updateOn),
+		}
+	}
+
+	if updateOn == "Manual" {
+		if arn, exists := config["RuntimeVersionArn"]; !exists || arn == nil || arn == "" {
+			return &samerrors.InvalidResourceException{
+				ResourceID: logicalID,
+				Message:    "RuntimeManagementConfig.RuntimeVersionArn is required when UpdateRuntimeOn is 'Manual'",
+			}
+		}
+	}
+
+	return nil
+}
+
+// validLogFormatValues are the accepted values for LoggingConfig.LogFormat.
+var validLogFormatValues = []string{"JSON", "Text"}
+
+// validApplicationLogLevelValues are the accepted values for LoggingConfig.ApplicationLogLevel.
+var validApplicationLogLevelValues = []string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+
+// validSystemLogLevelValues are the accepted values for LoggingConfig.SystemLogLevel.
+var validSystemLogLevelValues = []string{"DEBUG", "INFO", "WARN"}
+
+// validateLoggingConfig checks the LogFormat and log level enums, and that log
+// levels are only set with the JSON format, which Lambda requires for them.
+// Intrinsic values are left for CloudFormation to resolve.
+func (t *FunctionTransformer) validateLoggingConfig(logicalID string, config map[string]interface{}) error {
+	if config == nil {
+		return nil
+	}
+
+	logFormat, formatIsString := config["LogFormat"].(string)
+	if formatIsString && !containsString(validLogFormatValues, logFormat) {
+		return &samerrors.InvalidResourceException{
+			ResourceID: logicalID,
+			Message: fmt.Sprintf("LoggingConfig.LogFormat must be one of [%s], got '%s'",
+				strings.Join(validLogFormatValues, ", "), logFormat),
+		}
+	}
+
+	levels := []struct {
+		key    string
+		values []string
+	}{
+		{"ApplicationLogLevel", validApplicationLogLevelValues},
+		{"SystemLogLevel", validSystemLogLevelValues},
+	}
+	for _, level := range levels {
+		value, ok := config[level.key]
+		if !ok {
+			continue
+		}
+
+		// LogFormat defaults to Text, so an unset format rejects log levels too
+		_, formatSet := config["LogFormat"]
+		if !formatSet || (formatIsString && logFormat != "JSON") {
+			return &samerrors.InvalidResourceException{
+				ResourceID: logicalID,
+				Message:    fmt.Sprintf("LoggingConfig.%s requires LogFormat to be 'JSON'", level.key),
+			}
+		}
+
+		if str, ok := value.(string); ok && !containsString(level.values, str) {
+			return &samerrors.InvalidResourceException{
+				ResourceID: logicalID,
+				Message: fmt.Sprintf("LoggingConfig.%s must be one of [%s], got '%s'",
+					level.key, strings.Join(level.values, ", "), str),
+			}
+		}
+	}
+
+	return nil
+}
+
+// validSnapStartApplyOnValues are the accepted values for SnapStart.ApplyOn.
+var validSnapStartApplyOnValues = []string{"PublishedVersions", "None"}
+
+// snapStartRuntimes are the runtimes that support SnapStart.
+var snapStartRuntimes = []string{
+	"java11", "java17", "java21",
+	"python3.12", "python3.13",
+	"dotnet8",
+}
+
+// validateSnapStart checks SnapStart.ApplyOn and, when enabled, runtime support.
+// Publishing versions requires AutoPublishAlias, otherwise SnapStart never takes effect.
+func (t *FunctionTransformer) validateSnapStart(logicalID string, f *Function) error {
+	if f.SnapStart == nil {
+		return nil
+	}
+
+	applyOn, ok := f.SnapStart["ApplyOn"].(string)
+	if !ok {
+		return nil
+	}
+
+	if !containsString(validSnapStartApplyOnValues, applyOn) {
+		return &samerrors.InvalidResourceException{
+			ResourceID: logicalID,
+			Message: fmt.Sprintf("SnapStart.ApplyOn must be one of [%s], got '%s'",
+				strings.Join(validSnapStartApplyOnValues, ", "), applyOn),
+		}
+	}
+
+	if applyOn != "PublishedVersions" {
+		return nil
+	}
+
+	if f.AutoPublishAlias == "" {
+		return &samerrors.InvalidResourceException{
+			ResourceID: logicalID,
+			Message:    "SnapStart with ApplyOn 'PublishedVersions' requires AutoPublishAlias",
+		}
+	}
+
+	if t.ValidateSnapStartRuntime && f.Runtime != "" && !containsString(snapStartRuntimes, f.Runtime) {
+		return &samerrors.InvalidResourceException{
+			ResourceID: logicalID,
+			Message:    fmt.Sprintf("SnapStart is not supported for runtime '%s'", f.Runtime),
+		}
+	}
+
+	return nil
+}
+
+// containsString reports whether s is in values.
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
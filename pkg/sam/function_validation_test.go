@@ -0,0 +1,472 @@
+package sam
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	samerrors "github.com/lex00/aws-sam-translator-go/pkg/errors"
+)
+
+func TestFunctionValidation_FileSystemConfigs(t *testing.T) {
+	tests := []struct {
+		name      string
+		vpcConfig map[string]interface{}
+		wantErr   bool
+	}{
+		{
+			name: "with vpc",
+			vpcConfig: map[string]interface{}{
+				"SecurityGroupIds": []interface{}{"sg-12345678"},
+				"SubnetIds":        []interface{}{"subnet-12345678"},
+			},
+		},
+		{name: "without vpc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewFunctionTransformer()
+			fn := &Function{
+				Handler:   "index.handler",
+				Runtime:   "python3.12",
+				CodeUri:   "s3://bucket/code.zip",
+				VpcConfig: tt.vpcConfig,
+				FileSystemConfigs: []map[string]interface{}{
+					{"Arn": "arn:aws:elasticfilesystem:us-east-1:123456789012:access-point/fsap-123", "LocalMountPath": "/mnt/efs"},
+				},
+			}
+
+			resources, err := transformer.Transform("MyFunction", fn, nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error when FileSystemConfigs is set without VpcConfig")
+				}
+				var invalid *samerrors.InvalidResourceException
+				if !errors.As(err, &invalid) {
+					t.Fatalf("expected InvalidResourceException, got %T: %v", err, err)
+				}
+				if invalid.ResourceID != "MyFunction" {
+					t.Errorf("expected ResourceID 'MyFunction', got %q", invalid.ResourceID)
+				}
+				if !strings.Contains(err.Error(), "VpcConfig") {
+					t.Errorf("expected error to mention VpcConfig, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			props := resources["MyFunction"].(map[string]interface{})["Properties"].(map[string]interface{})
+			if _, ok := props["FileSystemConfigs"]; !ok {
+				t.Error("expected FileSystemConfigs on the function")
+			}
+			if _, ok := props["VpcConfig"]; !ok {
+				t.Error("expected VpcConfig on the function")
+			}
+		})
+	}
+}
+
+func TestFunctionValidation_VpcConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		vpcConfig     map[string]interface{}
+		wantDualStack bool
+		wantErr       string
+	}{
+		{
+			name: "dual stack",
+			vpcConfig: map[string]interface{}{
+				"SecurityGroupIds":        []interface{}{"sg-12345678"},
+				"SubnetIds":               []interface{}{"subnet-12345678"},
+				"Ipv6AllowedForDualStack": true,
+			},
+			wantDualStack: true,
+		},
+		{
+			name:      "intrinsic",
+			vpcConfig: map[string]interface{}{"Fn::If": []interface{}{"UseVpc", map[string]interface{}{}, map[string]interface{}{"Ref": "AWS::NoValue"}}},
+		},
+		{
+			name: "missing subnets",
+			vpcConfig: map[string]interface{}{
+				"SecurityGroupIds": []interface{}{"sg-12345678"},
+			},
+			wantErr: "missing SubnetIds",
+		},
+		{
+			name: "missing security groups",
+			vpcConfig: map[string]interface{}{
+				"SubnetIds": map[string]interface{}{"Ref": "Subnets"},
+			},
+			wantErr: "missing SecurityGroupIds",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewFunctionTransformer()
+			fn := &Function{
+				Handler:   "index.handler",
+				Runtime:   "python3.12",
+				CodeUri:   "s3://bucket/code.zip",
+				VpcConfig: tt.vpcConfig,
+			}
+
+			resources, err := transformer.Transform("MyFunction", fn, nil)
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("expected error containing %q", tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			props := resources["MyFunction"].(map[string]interface{})["Properties"].(map[string]interface{})
+			vpc := props["VpcConfig"].(map[string]interface{})
+			if tt.wantDualStack && vpc["Ipv6AllowedForDualStack"] != true {
+				t.Errorf("expected Ipv6AllowedForDualStack to pass through, got %v", vpc)
+			}
+		})
+	}
+}
+
+func TestFunctionValidation_RecursiveLoop(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "allow", value: "Allow"},
+		{name: "terminate", value: "Terminate"},
+		{name: "invalid", value: "Sometimes", wantErr: true},
+		{name: "wrong case", value: "allow", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewFunctionTransformer()
+			fn := &Function{
+				Handler:       "index.handler",
+				Runtime:       "python3.12",
+				CodeUri:       "s3://bucket/code.zip",
+				RecursiveLoop: tt.value,
+			}
+
+			_, err := transformer.Transform("MyFunction", fn, nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error for invalid RecursiveLoop")
+				}
+				if !strings.Contains(err.Error(), "RecursiveLoop") {
+					t.Errorf("expected error to mention RecursiveLoop, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestFunctionValidation_ReservedConcurrentExecutions(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		wantErr bool
+	}{
+		{name: "zero", value: 0},
+		{name: "positive", value: 100},
+		{name: "intrinsic", value: map[string]interface{}{"Ref": "ReservedConcurrency"}},
+		{name: "negative", value: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewFunctionTransformer()
+			fn := &Function{
+				Handler:                      "index.handler",
+				Runtime:                      "python3.12",
+				CodeUri:                      "s3://bucket/code.zip",
+				ReservedConcurrentExecutions: tt.value,
+			}
+
+			resources, err := transformer.Transform("MyFunction", fn, nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error for negative ReservedConcurrentExecutions")
+				}
+				if !strings.Contains(err.Error(), "ReservedConcurrentExecutions") {
+					t.Errorf("expected error to mention ReservedConcurrentExecutions, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			props := resources["MyFunction"].(map[string]interface{})["Properties"].(map[string]interface{})
+			if !reflect.DeepEqual(props["ReservedConcurrentExecutions"], tt.value) {
+				t.Errorf("expected ReservedConcurrentExecutions %v, got %v", tt.value, props["ReservedConcurrentExecutions"])
+			}
+		})
+	}
+}
+
+func TestFunctionValidation_ProvisionedConcurrencyAutoScaling(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr string
+	}{
+		{name: "valid", config: map[string]interface{}{"MinCapacity": 1, "MaxCapacity": 10}},
+		{name: "equal", config: map[string]interface{}{"MinCapacity": 5, "MaxCapacity": 5}},
+		{
+			name:   "intrinsic",
+			config: map[string]interface{}{"MinCapacity": map[string]interface{}{"Ref": "MinCapacity"}, "MaxCapacity": 2},
+		},
+		{
+			name:    "missing capacity",
+			config:  map[string]interface{}{"MinCapacity": 1},
+			wantErr: "requires MinCapacity and MaxCapacity",
+		},
+		{
+			name:    "min greater than max",
+			config:  map[string]interface{}{"MinCapacity": 10, "MaxCapacity": 2},
+			wantErr: "MinCapacity (10) must not be greater than MaxCapacity (2)",
+		},
+		{
+			name:    "below one",
+			config:  map[string]interface{}{"MinCapacity": 0, "MaxCapacity": 2},
+			wantErr: "must be at least 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewFunctionTransformer()
+			fn := &Function{
+				Handler:                           "index.handler",
+				Runtime:                           "python3.12",
+				CodeUri:                           "s3://bucket/code.zip",
+				AutoPublishAlias:                  "live",
+				ProvisionedConcurrencyAutoScaling: tt.config,
+			}
+
+			_, err := transformer.Transform("MyFunction", fn, nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var invalid *samerrors.InvalidResourceException
+			if !errors.As(err, &invalid) {
+				t.Fatalf("expected InvalidResourceException, got %T: %v", err, err)
+			}
+			if invalid.ResourceID != "MyFunction" {
+				t.Errorf("expected ResourceID 'MyFunction', got %q", invalid.ResourceID)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFunctionValidation_RuntimeManagementConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:   "auto",
+			config: map[string]interface{}{"UpdateRuntimeOn": "Auto"},
+		},
+		{
+			name:   "function update",
+			config: map[string]interface{}{"UpdateRuntimeOn": "FunctionUpdate"},
+		},
+		{
+			name: "manual with arn",
+			config: map[string]interface{}{
+				"UpdateRuntimeOn":   "Manual",
+				"RuntimeVersionArn": "arn:aws:lambda:us-east-1::runtime:abc123",
+			},
+		},
+		{
+			name:   "intrinsic",
+			config: map[string]interface{}{"UpdateRuntimeOn": map[string]interface{}{"Ref": "UpdateMode"}},
+		},
+		{
+			name:    "manual without arn",
+			config:  map[string]interface{}{"UpdateRuntimeOn": "Manual"},
+			wantErr: "RuntimeVersionArn is required",
+		},
+		{
+			name:    "invalid value",
+			config:  map[string]interface{}{"UpdateRuntimeOn": "Never"},
+			wantErr: "UpdateRuntimeOn must be one of",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewFunctionTransformer()
+			fn := &Function{
+				Handler:                 "index.handler",
+				Runtime:                 "python3.12",
+				CodeUri:                 "s3://bucket/code.zip",
+				RuntimeManagementConfig: tt.config,
+			}
+
+			_, err := transformer.Transform("MyFunction", fn, nil)
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("expected error containing %q", tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestFunctionValidation_LoggingConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr string
+	}{
+		{
+			name: "json with levels",
+			config: map[string]interface{}{
+				"LogFormat":           "JSON",
+				"ApplicationLogLevel": "DEBUG",
+				"SystemLogLevel":      "WARN",
+				"LogGroup":            "/orders/service",
+			},
+		},
+		{
+			name:   "text without levels",
+			config: map[string]interface{}{"LogFormat": "Text"},
+		},
+		{
+			name:   "intrinsic format",
+			config: map[string]interface{}{"LogFormat": map[string]interface{}{"Ref": "LogFormat"}, "ApplicationLogLevel": "INFO"},
+		},
+		{
+			name:    "application level with text format",
+			config:  map[string]interface{}{"LogFormat": "Text", "ApplicationLogLevel": "INFO"},
+			wantErr: "ApplicationLogLevel requires LogFormat to be 'JSON'",
+		},
+		{
+			name:    "system level without format",
+			config:  map[string]interface{}{"SystemLogLevel": "INFO"},
+			wantErr: "SystemLogLevel requires LogFormat to be 'JSON'",
+		},
+		{
+			name:    "invalid format",
+			config:  map[string]interface{}{"LogFormat": "XML"},
+			wantErr: "LogFormat must be one of",
+		},
+		{
+			name:    "invalid application level",
+			config:  map[string]interface{}{"LogFormat": "JSON", "ApplicationLogLevel": "VERBOSE"},
+			wantErr: "ApplicationLogLevel must be one of",
+		},
+		{
+			name:    "system level not allowed",
+			config:  map[string]interface{}{"LogFormat": "JSON", "SystemLogLevel": "TRACE"},
+			wantErr: "SystemLogLevel must be one of",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewFunctionTransformer()
+			fn := &Function{
+				Handler:       "index.handler",
+				Runtime:       "python3.12",
+				CodeUri:       "s3://bucket/code.zip",
+				LoggingConfig: tt.config,
+			}
+
+			_, err := transformer.Transform("MyFunction", fn, nil)
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("expected error containing %q", tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestFunctionValidation_SnapStart(t *testing.T) {
+	tests := []struct {
+		name            string
+		runtime         string
+		applyOn         interface{}
+		alias           string
+		validateRuntime bool
+		wantErr         string
+	}{
+		{name: "valid java", runtime: "java21", applyOn: "PublishedVersions", alias: "live"},
+		{name: "none without alias", runtime: "java21", applyOn: "None"},
+		{name: "intrinsic", runtime: "java21", applyOn: map[string]interface{}{"Ref": "ApplyOn"}},
+		{name: "invalid apply on", runtime: "java21", applyOn: "Always", alias: "live", wantErr: "SnapStart.ApplyOn must be one of"},
+		{name: "missing alias", runtime: "java21", applyOn: "PublishedVersions", wantErr: "requires AutoPublishAlias"},
+		{name: "unsupported runtime unchecked", runtime: "nodejs20.x", applyOn: "PublishedVersions", alias: "live"},
+		{name: "unsupported runtime checked", runtime: "nodejs20.x", applyOn: "PublishedVersions", alias: "live", validateRuntime: true, wantErr: "not supported for runtime"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewFunctionTransformer()
+			transformer.ValidateSnapStartRuntime = tt.validateRuntime
+			fn := &Function{
+				Handler:          "com.example.Handler::handleRequest",
+				Runtime:          tt.runtime,
+				CodeUri:          "s3://bucket/code.jar",
+				SnapStart:        map[string]interface{}{"ApplyOn": tt.applyOn},
+				AutoPublishAlias: tt.alias,
+			}
+
+			_, err := transformer.Transform("MyFunction", fn, nil)
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("expected error containing %q", tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
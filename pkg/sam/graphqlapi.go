@@ -3,6 +3,8 @@ package sam
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/lex00/aws-sam-translator-go/pkg/model/iam"
 )
@@ -433,18 +435,11 @@ func (t *GraphQLApiTransformer) buildSchema(logicalID string, api *GraphQLApi) (
 	if api.SchemaInline != "" {
 		props["Definition"] = api.SchemaInline
 	} else if api.SchemaUri != nil {
-		switch uri := api.SchemaUri.(type) {
-		case string:
-			props["DefinitionS3Location"] = uri
-		case map[string]interface{}:
-			// Could be an intrinsic function or S3 location object
-			if bucket, hasBucket := uri["Bucket"]; hasBucket {
-				s3Location := fmt.Sprintf("s3://%v/%v", bucket, uri["Key"])
-				props["DefinitionS3Location"] = s3Location
-			} else {
-				props["DefinitionS3Location"] = uri
-			}
+		location, err := schemaS3Location(api.SchemaUri)
+		if err != nil {
+			return nil, err
 		}
+		props["DefinitionS3Location"] = location
 	}
 
 	return map[string]interface{}{
@@ -453,6 +448,36 @@ func (t *GraphQLApiTransformer) buildSchema(logicalID string, api *GraphQLApi) (
 	}, nil
 }
 
+// schemaS3Location converts a SchemaUri into a DefinitionS3Location value.
+// Strings and intrinsic functions are emitted as-is. A {Bucket, Key} object
+// becomes an s3:// string when both values are literals, or an Fn::Join
+// when either is an intrinsic.
+func schemaS3Location(uri interface{}) (interface{}, error) {
+	location, ok := uri.(map[string]interface{})
+	if !ok {
+		return uri, nil
+	}
+
+	bucket, hasBucket := location["Bucket"]
+	if !hasBucket {
+		return location, nil
+	}
+	key, hasKey := location["Key"]
+	if !hasKey {
+		return nil, fmt.Errorf("SchemaUri with Bucket requires Key")
+	}
+
+	bucketName, bucketIsString := bucket.(string)
+	keyName, keyIsString := key.(string)
+	if bucketIsString && keyIsString {
+		return fmt.Sprintf("s3://%s/%s", bucketName, keyName), nil
+	}
+
+	return map[string]interface{}{
+		"Fn::Join": []interface{}{"", []interface{}{"s3://", bucket, "/", key}},
+	}, nil
+}
+
 // buildDataSources builds data source resources.
 func (t *GraphQLApiTransformer) buildDataSources(logicalID string, api *GraphQLApi) (map[string]interface{}, map[string]interface{}, error) {
 	dataSourceResources := make(map[string]interface{})
@@ -606,6 +631,11 @@ func (t *GraphQLApiTransformer) buildFunctions(logicalID string, api *GraphQLApi
 	for fnName, fn := range api.Functions {
 		fnLogicalID := logicalID + fnName + "Function"
 
+		dsKey, err := resolveDataSourceKey(api, fn.DataSourceName)
+		if err != nil {
+			return nil, fmt.Errorf("function '%s': %w", fnName, err)
+		}
+
 		props := map[string]interface{}{
 			"ApiId": map[string]interface{}{
 				"Fn::GetAtt": []string{logicalID, "ApiId"},
@@ -653,18 +683,43 @@ func (t *GraphQLApiTransformer) buildFunctions(logicalID string, api *GraphQLApi
 		resources[fnLogicalID] = map[string]interface{}{
 			"Type":       TypeAppSyncFunctionConfig,
 			"Properties": props,
-			"DependsOn":  logicalID + fn.DataSourceName + "DataSource",
+			"DependsOn":  logicalID + dsKey + "DataSource",
 		}
 	}
 
 	return resources, nil
 }
 
+// resolveDataSourceKey returns the DataSources map key for name, which may be
+// either the key itself or the Name set on a declared data source.
+func resolveDataSourceKey(api *GraphQLApi, name string) (string, error) {
+	if _, ok := api.DataSources[name]; ok {
+		return name, nil
+	}
+
+	keys := make([]string, 0, len(api.DataSources))
+	for key := range api.DataSources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if api.DataSources[key].Name == name {
+			return key, nil
+		}
+	}
+	return "", fmt.Errorf("DataSourceName '%s' does not match any declared data source", name)
+}
+
 // buildResolvers builds resolver resources.
 func (t *GraphQLApiTransformer) buildResolvers(logicalID string, api *GraphQLApi) (map[string]interface{}, error) {
 	resources := make(map[string]interface{})
 
 	for resolverName, resolver := range api.Resolvers {
+		if err := validateResolver(resolverName, resolver); err != nil {
+			return nil, err
+		}
+
 		resolverLogicalID := logicalID + resolverName + "Resolver"
 
 		props := map[string]interface{}{
@@ -729,7 +784,11 @@ func (t *GraphQLApiTransformer) buildResolvers(logicalID string, api *GraphQLApi
 		// Build dependencies
 		depends := []string{logicalID + "Schema"}
 		if resolver.DataSourceName != "" {
-			depends = append(depends, logicalID+resolver.DataSourceName+"DataSource")
+			dsKey, err := resolveDataSourceKey(api, resolver.DataSourceName)
+			if err != nil {
+				return nil, fmt.Errorf("resolver '%s': %w", resolverName, err)
+			}
+			depends = append(depends, logicalID+dsKey+"DataSource")
 		}
 
 		resources[resolverLogicalID] = map[string]interface{}{
@@ -742,6 +801,27 @@ func (t *GraphQLApiTransformer) buildResolvers(logicalID string, api *GraphQLApi
 	return resources, nil
 }
 
+// validateResolver rejects resolvers that mix VTL mapping templates with
+// JavaScript code, set code without a runtime, or are UNIT resolvers without
+// a data source.
+func validateResolver(name string, resolver GraphQLApiResolver) error {
+	hasTemplate := resolver.RequestMappingTemplate != "" || resolver.ResponseMappingTemplate != "" ||
+		resolver.RequestMappingTemplateS3Location != "" || resolver.ResponseMappingTemplateS3Location != ""
+	hasCode := resolver.Code != "" || resolver.CodeS3Location != ""
+
+	if hasTemplate && hasCode {
+		return fmt.Errorf("resolver '%s' cannot use both VTL mapping templates and Code or CodeS3Location", name)
+	}
+	if hasCode && resolver.Runtime == nil {
+		return fmt.Errorf("resolver '%s' requires Runtime when Code or CodeS3Location is set", name)
+	}
+	isUnit := resolver.Kind == "UNIT" || (resolver.Kind == "" && resolver.PipelineConfig == nil)
+	if isUnit && resolver.DataSourceName == "" {
+		return fmt.Errorf("resolver '%s' of kind UNIT requires DataSourceName", name)
+	}
+	return nil
+}
+
 // buildApiKeys builds API key resources.
 func (t *GraphQLApiTransformer) buildApiKeys(logicalID string, api *GraphQLApi) (map[string]interface{}, error) {
 	resources := make(map[string]interface{})
@@ -811,6 +891,10 @@ func (t *GraphQLApiTransformer) buildCache(logicalID string, api *GraphQLApi) (m
 		props[k] = v
 	}
 
+	if err := validateCache(props); err != nil {
+		return nil, err
+	}
+
 	// Ensure required properties
 	if _, ok := props["ApiCachingBehavior"]; !ok {
 		props["ApiCachingBehavior"] = "FULL_REQUEST_CACHING"
@@ -828,10 +912,58 @@ func (t *GraphQLApiTransformer) buildCache(logicalID string, api *GraphQLApi) (m
 	}, nil
 }
 
-// buildDomainName builds the custom domain name resources.
+// validCacheTypes lists the AppSync API cache instance types.
+var validCacheTypes = []string{
+	"SMALL", "MEDIUM", "LARGE", "XLARGE",
+	"LARGE_2X", "LARGE_4X", "LARGE_8X", "LARGE_12X",
+	"T2_SMALL", "T2_MEDIUM",
+	"R4_LARGE", "R4_XLARGE", "R4_2XLARGE", "R4_4XLARGE", "R4_8XLARGE",
+}
+
+// validCachingBehaviors lists the AppSync ApiCachingBehavior values.
+var validCachingBehaviors = []string{
+	"FULL_REQUEST_CACHING", "PER_RESOLVER_CACHING", "OPERATION_LEVEL_CACHING",
+}
+
+// validateCache checks literal Cache values against the AppSync limits.
+// Intrinsic values are not validated.
+func validateCache(cache map[string]interface{}) error {
+	if cacheType, ok := cache["Type"].(string); ok && !containsString(validCacheTypes, cacheType) {
+		return fmt.Errorf("Cache Type '%s' is not valid; must be one of %s", cacheType, strings.Join(validCacheTypes, ", "))
+	}
+
+	if behavior, ok := cache["ApiCachingBehavior"].(string); ok && !containsString(validCachingBehaviors, behavior) {
+		return fmt.Errorf("Cache ApiCachingBehavior '%s' is not valid; must be one of %s", behavior, strings.Join(validCachingBehaviors, ", "))
+	}
+
+	var ttl float64
+	switch v := cache["Ttl"].(type) {
+	case int:
+		ttl = float64(v)
+	case int64:
+		ttl = float64(v)
+	case float64:
+		ttl = v
+	default:
+		return nil
+	}
+	if ttl < 1 || ttl > 3600 {
+		return fmt.Errorf("Cache Ttl must be between 1 and 3600 seconds, got %v", cache["Ttl"])
+	}
+	return nil
+}
+
+// buildDomainName builds the custom domain name resources. The domain must
+// declare both a DomainName and a CertificateArn.
 func (t *GraphQLApiTransformer) buildDomainName(logicalID string, api *GraphQLApi) (map[string]interface{}, error) {
 	resources := make(map[string]interface{})
 
+	for _, key := range []string{"DomainName", "CertificateArn"} {
+		if v := api.DomainName[key]; v == nil || v == "" {
+			return nil, fmt.Errorf("custom domain requires %s", key)
+		}
+	}
+
 	domainProps := make(map[string]interface{})
 	for k, v := range api.DomainName {
 		domainProps[k] = v
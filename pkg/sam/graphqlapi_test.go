@@ -1,6 +1,8 @@
 package sam
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -232,6 +234,53 @@ func TestGraphQLApiTransformer_WithSchemaUri(t *testing.T) {
 	}
 }
 
+func TestGraphQLApiTransformer_SchemaUriForms(t *testing.T) {
+	tests := []struct {
+		name      string
+		schemaUri interface{}
+		expected  interface{}
+	}{
+		{
+			name:      "literal bucket and key",
+			schemaUri: map[string]interface{}{"Bucket": "my-bucket", "Key": "schema.graphql"},
+			expected:  "s3://my-bucket/schema.graphql",
+		},
+		{
+			name:      "bucket and key with intrinsic",
+			schemaUri: map[string]interface{}{"Bucket": map[string]interface{}{"Ref": "SchemaBucket"}, "Key": "schema.graphql"},
+			expected: map[string]interface{}{
+				"Fn::Join": []interface{}{"", []interface{}{"s3://", map[string]interface{}{"Ref": "SchemaBucket"}, "/", "schema.graphql"}},
+			},
+		},
+		{
+			name:      "Ref URI",
+			schemaUri: map[string]interface{}{"Ref": "SchemaLocation"},
+			expected:  map[string]interface{}{"Ref": "SchemaLocation"},
+		},
+		{
+			name:      "Fn::Sub URI",
+			schemaUri: map[string]interface{}{"Fn::Sub": "s3://${Bucket}/schema.graphql"},
+			expected:  map[string]interface{}{"Fn::Sub": "s3://${Bucket}/schema.graphql"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewGraphQLApiTransformer()
+
+			resources, err := transformer.Transform("MyApi", &GraphQLApi{SchemaUri: tt.schemaUri}, nil)
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			props := resources["MyApiSchema"].(map[string]interface{})["Properties"].(map[string]interface{})
+			if !reflect.DeepEqual(props["DefinitionS3Location"], tt.expected) {
+				t.Errorf("expected DefinitionS3Location %v, got %v", tt.expected, props["DefinitionS3Location"])
+			}
+		})
+	}
+}
+
 func TestGraphQLApiTransformer_WithLambdaDataSource(t *testing.T) {
 	transformer := NewGraphQLApiTransformer()
 
@@ -484,6 +533,91 @@ func TestGraphQLApiTransformer_WithPipelineResolver(t *testing.T) {
 	}
 }
 
+func TestGraphQLApiTransformer_FunctionDataSourceByName(t *testing.T) {
+	transformer := NewGraphQLApiTransformer()
+
+	api := &GraphQLApi{
+		SchemaInline: "type Query { getData: String }",
+		DataSources: map[string]GraphQLApiDataSource{
+			"Lambda": {
+				Type: "AWS_LAMBDA",
+				Name: "DataLambda",
+				LambdaConfig: map[string]interface{}{
+					"LambdaFunctionArn": "arn:aws:lambda:us-east-1:123456789012:function:data",
+				},
+			},
+		},
+		Functions: map[string]GraphQLApiFunction{
+			"GetData": {
+				Name:                   "GetData",
+				DataSourceName:         "DataLambda",
+				RequestMappingTemplate: "{}",
+			},
+		},
+	}
+
+	resources, err := transformer.Transform("MyApi", api, nil)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	fnResource := resources["MyApiGetDataFunction"].(map[string]interface{})
+	if fnResource["DependsOn"] != "MyApiLambdaDataSource" {
+		t.Errorf("expected DependsOn 'MyApiLambdaDataSource', got %v", fnResource["DependsOn"])
+	}
+
+	api.Functions["GetData"] = GraphQLApiFunction{Name: "GetData", DataSourceName: "Missing"}
+	_, err = transformer.Transform("MyApi", api, nil)
+	if err == nil || !strings.Contains(err.Error(), "DataSourceName 'Missing' does not match any declared data source") {
+		t.Errorf("expected undeclared data source error, got %v", err)
+	}
+}
+
+func TestGraphQLApiTransformer_ResolverDataSourceByName(t *testing.T) {
+	transformer := NewGraphQLApiTransformer()
+
+	api := &GraphQLApi{
+		SchemaInline: "type Query { getData: String }",
+		DataSources: map[string]GraphQLApiDataSource{
+			"Lambda": {
+				Type: "AWS_LAMBDA",
+				Name: "DataLambda",
+				LambdaConfig: map[string]interface{}{
+					"LambdaFunctionArn": "arn:aws:lambda:us-east-1:123456789012:function:data",
+				},
+			},
+		},
+		Resolvers: map[string]GraphQLApiResolver{
+			"QueryGetData": {
+				TypeName:                "Query",
+				FieldName:               "getData",
+				DataSourceName:          "DataLambda",
+				RequestMappingTemplate:  "{}",
+				ResponseMappingTemplate: "$util.toJson($ctx.result)",
+			},
+		},
+	}
+
+	resources, err := transformer.Transform("MyApi", api, nil)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	resolverResource := resources["MyApiQueryGetDataResolver"].(map[string]interface{})
+	expected := []string{"MyApiSchema", "MyApiLambdaDataSource"}
+	if !reflect.DeepEqual(resolverResource["DependsOn"], expected) {
+		t.Errorf("expected DependsOn %v, got %v", expected, resolverResource["DependsOn"])
+	}
+
+	resolver := api.Resolvers["QueryGetData"]
+	resolver.DataSourceName = "Missing"
+	api.Resolvers["QueryGetData"] = resolver
+	_, err = transformer.Transform("MyApi", api, nil)
+	if err == nil || !strings.Contains(err.Error(), "resolver 'QueryGetData': DataSourceName 'Missing' does not match any declared data source") {
+		t.Errorf("expected undeclared data source error, got %v", err)
+	}
+}
+
 func TestGraphQLApiTransformer_WithXRayTracing(t *testing.T) {
 	transformer := NewGraphQLApiTransformer()
 
@@ -573,6 +707,61 @@ func TestGraphQLApiTransformer_WithCache(t *testing.T) {
 	}
 }
 
+func TestGraphQLApiTransformer_CacheValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		cache   map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:    "invalid instance type",
+			cache:   map[string]interface{}{"Type": "HUGE"},
+			wantErr: "Cache Type 'HUGE' is not valid",
+		},
+		{
+			name:    "invalid caching behavior",
+			cache:   map[string]interface{}{"ApiCachingBehavior": "SOMETIMES"},
+			wantErr: "Cache ApiCachingBehavior 'SOMETIMES' is not valid",
+		},
+		{
+			name:    "TTL above range",
+			cache:   map[string]interface{}{"Ttl": 7200},
+			wantErr: "Cache Ttl must be between 1 and 3600",
+		},
+		{
+			name:    "TTL below range",
+			cache:   map[string]interface{}{"Ttl": 0},
+			wantErr: "Cache Ttl must be between 1 and 3600",
+		},
+		{
+			name:  "intrinsic TTL",
+			cache: map[string]interface{}{"Ttl": map[string]interface{}{"Ref": "CacheTtl"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewGraphQLApiTransformer()
+
+			api := &GraphQLApi{
+				SchemaInline: "type Query { hello: String }",
+				Cache:        tt.cache,
+			}
+
+			_, err := transformer.Transform("MyApi", api, nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Transform failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestGraphQLApiTransformer_WithTags(t *testing.T) {
 	transformer := NewGraphQLApiTransformer()
 
@@ -666,6 +855,59 @@ func TestGraphQLApiTransformer_DefaultApiKey(t *testing.T) {
 	}
 }
 
+func TestGraphQLApiTransformer_DomainNameValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		domain  map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:    "missing certificate",
+			domain:  map[string]interface{}{"DomainName": "api.example.com"},
+			wantErr: "custom domain requires CertificateArn",
+		},
+		{
+			name:    "empty certificate",
+			domain:  map[string]interface{}{"DomainName": "api.example.com", "CertificateArn": ""},
+			wantErr: "custom domain requires CertificateArn",
+		},
+		{
+			name:    "missing domain name",
+			domain:  map[string]interface{}{"CertificateArn": "arn:aws:acm:us-east-1:123456789012:certificate/abc123"},
+			wantErr: "custom domain requires DomainName",
+		},
+		{
+			name: "intrinsic certificate",
+			domain: map[string]interface{}{
+				"DomainName":     "api.example.com",
+				"CertificateArn": map[string]interface{}{"Ref": "CertificateArn"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewGraphQLApiTransformer()
+
+			api := &GraphQLApi{
+				SchemaInline: "type Query { hello: String }",
+				DomainName:   tt.domain,
+			}
+
+			_, err := transformer.Transform("MyApi", api, nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Transform failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestGraphQLApiTransformer_WithDomainName(t *testing.T) {
 	transformer := NewGraphQLApiTransformer()
 
@@ -830,6 +1072,83 @@ export function response(ctx) { return "Hello, World!"; }`,
 	}
 }
 
+func TestGraphQLApiTransformer_ResolverValidation(t *testing.T) {
+	jsRuntime := map[string]interface{}{
+		"Name":           "APPSYNC_JS",
+		"RuntimeVersion": "1.0.0",
+	}
+
+	tests := []struct {
+		name     string
+		resolver GraphQLApiResolver
+		wantErr  string
+	}{
+		{
+			name: "valid JS resolver",
+			resolver: GraphQLApiResolver{
+				DataSourceName: "None",
+				Runtime:        jsRuntime,
+				CodeS3Location: "s3://bucket/resolver.js",
+			},
+		},
+		{
+			name: "VTL templates mixed with code",
+			resolver: GraphQLApiResolver{
+				DataSourceName:         "None",
+				RequestMappingTemplate: "{}",
+				Runtime:                jsRuntime,
+				Code:                   "export function request(ctx) { return {}; }",
+			},
+			wantErr: "cannot use both VTL mapping templates and Code",
+		},
+		{
+			name: "code without runtime",
+			resolver: GraphQLApiResolver{
+				DataSourceName: "None",
+				Code:           "export function request(ctx) { return {}; }",
+			},
+			wantErr: "requires Runtime",
+		},
+		{
+			name: "UNIT resolver without data source",
+			resolver: GraphQLApiResolver{
+				Kind:                   "UNIT",
+				RequestMappingTemplate: "{}",
+			},
+			wantErr: "requires DataSourceName",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewGraphQLApiTransformer()
+
+			tt.resolver.TypeName = "Query"
+			tt.resolver.FieldName = "hello"
+			api := &GraphQLApi{
+				SchemaInline: "type Query { hello: String }",
+				DataSources: map[string]GraphQLApiDataSource{
+					"None": {Type: "NONE", Name: "NoneDataSource"},
+				},
+				Resolvers: map[string]GraphQLApiResolver{
+					"QueryHello": tt.resolver,
+				},
+			}
+
+			_, err := transformer.Transform("MyApi", api, nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Transform failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestGraphQLApiTransformer_WithExplicitServiceRole(t *testing.T) {
 	transformer := NewGraphQLApiTransformer()
 
@@ -4,7 +4,10 @@ package sam
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
+
+	"github.com/lex00/aws-sam-translator-go/pkg/intrinsics"
 )
 
 // HttpApi represents an AWS::Serverless::HttpApi resource.
@@ -75,6 +78,10 @@ type HttpApiAccessLogSettings struct {
 
 	// Format is the log format string.
 	Format interface{} `json:"Format,omitempty" yaml:"Format,omitempty"`
+
+	// CreateLogGroup requests an AWS::Logs::LogGroup to be generated as the
+	// destination when DestinationArn is not set.
+	CreateLogGroup bool `json:"CreateLogGroup,omitempty" yaml:"CreateLogGroup,omitempty"`
 }
 
 // HttpApiRouteSettings specifies route settings for HTTP API.
@@ -123,6 +130,10 @@ func NewHttpApiTransformer() *HttpApiTransformer {
 
 // Transform converts a SAM HttpApi to CloudFormation resources.
 func (t *HttpApiTransformer) Transform(logicalID string, api *HttpApi, ctx *TransformContext) (map[string]interface{}, error) {
+	if err := t.validateRouteSettings(api); err != nil {
+		return nil, err
+	}
+
 	resources := make(map[string]interface{})
 
 	// Build the API Gateway V2 API resource
@@ -146,6 +157,17 @@ func (t *HttpApiTransformer) Transform(logicalID string, api *HttpApi, ctx *Tran
 		"Properties": stageProps,
 	}
 
+	// Build the access log group if auto-creation was requested. HTTP APIs
+	// write to CloudWatch Logs through a service-linked role, so unlike REST
+	// APIs no account-level AWS::ApiGateway::Account role is needed.
+	if t.createsAccessLogGroup(api) {
+		logGroupLogicalID := logicalID + "AccessLogGroup"
+		resources[logGroupLogicalID] = map[string]interface{}{
+			"Type":       "AWS::Logs::LogGroup",
+			"Properties": map[string]interface{}{},
+		}
+	}
+
 	// Build authorizers if configured
 	if api.Auth != nil && len(api.Auth.Authorizers) > 0 {
 		authorizerResources, err := t.buildAuthorizers(logicalID, api.Auth)
@@ -255,12 +277,16 @@ func (t *HttpApiTransformer) buildStageProperties(apiLogicalID string, api *Http
 		accessLogSettings := make(map[string]interface{})
 		if api.AccessLogSettings.DestinationArn != nil {
 			accessLogSettings["DestinationArn"] = api.AccessLogSettings.DestinationArn
+		} else if t.createsAccessLogGroup(api) {
+			accessLogSettings["DestinationArn"] = map[string]interface{}{
+				"Fn::GetAtt": []interface{}{apiLogicalID + "AccessLogGroup", "Arn"},
+			}
 		}
 		if api.AccessLogSettings.Format != nil {
 			accessLogSettings["Format"] = api.AccessLogSettings.Format
 		} else {
 			// Default format if destination is set but format is not
-			if api.AccessLogSettings.DestinationArn != nil {
+			if accessLogSettings["DestinationArn"] != nil {
 				accessLogSettings["Format"] = t.defaultAccessLogFormat()
 			}
 		}
@@ -304,6 +330,91 @@ func (t *HttpApiTransformer) buildStageProperties(apiLogicalID string, api *Http
 	return props
 }
 
+// validRouteKeyMethods are the HTTP methods allowed in an HTTP API route key.
+var validRouteKeyMethods = []string{"ANY", "DELETE", "GET", "HEAD", "OPTIONS", "PATCH", "POST", "PUT"}
+
+// validateRouteSettings checks that RouteSettings keys are well-formed route keys
+// and that throttling limits in RouteSettings and DefaultRouteSettings are non-negative.
+func (t *HttpApiTransformer) validateRouteSettings(api *HttpApi) error {
+	if api.DefaultRouteSettings != nil {
+		if err := validateThrottlingLimit("DefaultRouteSettings", "ThrottlingBurstLimit", api.DefaultRouteSettings.ThrottlingBurstLimit); err != nil {
+			return err
+		}
+		if err := validateThrottlingLimit("DefaultRouteSettings", "ThrottlingRateLimit", api.DefaultRouteSettings.ThrottlingRateLimit); err != nil {
+			return err
+		}
+	}
+
+	keys := make([]string, 0, len(api.RouteSettings))
+	for key := range api.RouteSettings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !isValidRouteKey(key) {
+			return fmt.Errorf("invalid RouteSettings key '%s': must be '$default' or in the form 'METHOD /path'", key)
+		}
+		settings, ok := api.RouteSettings[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		context := fmt.Sprintf("RouteSettings '%s'", key)
+		if err := validateThrottlingLimit(context, "ThrottlingBurstLimit", settings["ThrottlingBurstLimit"]); err != nil {
+			return err
+		}
+		if err := validateThrottlingLimit(context, "ThrottlingRateLimit", settings["ThrottlingRateLimit"]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isValidRouteKey reports whether key is "$default" or "METHOD /path".
+func isValidRouteKey(key string) bool {
+	if key == "$default" {
+		return true
+	}
+	parts := strings.SplitN(key, " ", 2)
+	if len(parts) != 2 {
+		return false
+	}
+	method, path := parts[0], parts[1]
+	if !containsString(validRouteKeyMethods, method) {
+		return false
+	}
+	return strings.HasPrefix(path, "/") && !strings.ContainsAny(path, " \t")
+}
+
+// validateThrottlingLimit checks that a numeric throttling limit is non-negative.
+// Intrinsic functions and other non-numeric values are left for CloudFormation.
+func validateThrottlingLimit(context, name string, value interface{}) error {
+	var n float64
+	switch v := value.(type) {
+	case int:
+		n = float64(v)
+	case int64:
+		n = float64(v)
+	case float64:
+		n = v
+	default:
+		return nil
+	}
+	if n < 0 {
+		return fmt.Errorf("%s: %s must be non-negative, got %v", context, name, value)
+	}
+	return nil
+}
+
+// createsAccessLogGroup reports whether an access log group should be generated
+// for the API: CreateLogGroup is set and no DestinationArn was provided.
+func (t *HttpApiTransformer) createsAccessLogGroup(api *HttpApi) bool {
+	return api.AccessLogSettings != nil &&
+		api.AccessLogSettings.CreateLogGroup &&
+		api.AccessLogSettings.DestinationArn == nil
+}
+
 // defaultAccessLogFormat returns the default access log format for HTTP API.
 func (t *HttpApiTransformer) defaultAccessLogFormat() string {
 	return `{"requestId":"$context.requestId","ip":"$context.identity.sourceIp","requestTime":"$context.requestTime","httpMethod":"$context.httpMethod","routeKey":"$context.routeKey","status":"$context.status","protocol":"$context.protocol","responseLength":"$context.responseLength"}`
@@ -507,36 +618,16 @@ func (t *HttpApiTransformer) buildAuthorizers(apiLogicalID string, auth *HttpApi
 
 // buildLambdaAuthorizerUri builds the authorizer URI for a Lambda function.
 func (t *HttpApiTransformer) buildLambdaAuthorizerUri(functionArn interface{}) interface{} {
-	switch arn := functionArn.(type) {
-	case string:
-		return map[string]interface{}{
-			"Fn::Sub": fmt.Sprintf("arn:${AWS::Partition}:apigateway:${AWS::Region}:lambda:path/2015-03-31/functions/%s/invocations", arn),
-		}
-	case map[string]interface{}:
-		if _, hasRef := arn["Ref"]; hasRef {
-			return map[string]interface{}{
-				"Fn::Sub": []interface{}{
-					"arn:${AWS::Partition}:apigateway:${AWS::Region}:lambda:path/2015-03-31/functions/${FunctionArn}/invocations",
-					map[string]interface{}{
-						"FunctionArn": map[string]interface{}{
-							"Fn::GetAtt": []interface{}{arn["Ref"], "Arn"},
-						},
-					},
-				},
-			}
-		}
-		if getAtt, hasGetAtt := arn["Fn::GetAtt"]; hasGetAtt {
-			return map[string]interface{}{
-				"Fn::Sub": []interface{}{
-					"arn:${AWS::Partition}:apigateway:${AWS::Region}:lambda:path/2015-03-31/functions/${FunctionArn}/invocations",
-					map[string]interface{}{
-						"FunctionArn": map[string]interface{}{"Fn::GetAtt": getAtt},
-					},
-				},
-			}
+	// A Ref to a function yields its name, so reference its ARN instead
+	if m, ok := functionArn.(map[string]interface{}); ok && len(m) == 1 {
+		if ref, ok := m["Ref"].(string); ok {
+			functionArn = intrinsics.NewGetAtt(ref, "Arn")
 		}
 	}
-	return functionArn
+	return intrinsics.NewSub(
+		"arn:${AWS::Partition}:apigateway:${AWS::Region}:lambda:path/2015-03-31/functions/${FunctionArn}/invocations",
+		"FunctionArn", functionArn,
+	)
 }
 
 // buildDomainResources builds custom domain resources.
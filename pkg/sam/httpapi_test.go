@@ -1,6 +1,7 @@
 package sam
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -292,6 +293,78 @@ func TestHttpApiTransformer_Transform_WithAccessLogSettingsDefaultFormat(t *test
 	}
 }
 
+func TestHttpApiTransformer_Transform_WithAccessLogGroupCreation(t *testing.T) {
+	transformer := NewHttpApiTransformer()
+
+	api := &HttpApi{
+		AccessLogSettings: &HttpApiAccessLogSettings{
+			CreateLogGroup: true,
+		},
+	}
+
+	ctx := &TransformContext{}
+	resources, err := transformer.Transform("MyHttpApi", api, ctx)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	logGroup, ok := resources["MyHttpApiAccessLogGroup"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected MyHttpApiAccessLogGroup resource to be created")
+	}
+	if logGroup["Type"] != "AWS::Logs::LogGroup" {
+		t.Errorf("expected Type AWS::Logs::LogGroup, got %v", logGroup["Type"])
+	}
+
+	stageResource := resources["MyHttpApiStage"].(map[string]interface{})
+	stageProps := stageResource["Properties"].(map[string]interface{})
+
+	accessLog := stageProps["AccessLogSettings"].(map[string]interface{})
+	destArn := accessLog["DestinationArn"].(map[string]interface{})
+	getAtt := destArn["Fn::GetAtt"].([]interface{})
+	if getAtt[0] != "MyHttpApiAccessLogGroup" || getAtt[1] != "Arn" {
+		t.Errorf("expected DestinationArn Fn::GetAtt [MyHttpApiAccessLogGroup, Arn], got %v", getAtt)
+	}
+	if accessLog["Format"] == nil || accessLog["Format"] == "" {
+		t.Error("expected default Format to be set for the created log group")
+	}
+
+	for key, value := range resources {
+		if resource := value.(map[string]interface{}); resource["Type"] == "AWS::ApiGateway::Account" {
+			t.Errorf("expected no AWS::ApiGateway::Account for an HTTP API, got %s", key)
+		}
+	}
+}
+
+func TestHttpApiTransformer_Transform_WithAccessLogGroupCreationAndDestination(t *testing.T) {
+	transformer := NewHttpApiTransformer()
+
+	api := &HttpApi{
+		AccessLogSettings: &HttpApiAccessLogSettings{
+			DestinationArn: "arn:aws:logs:us-east-1:123456789:log-group:my-log-group",
+			CreateLogGroup: true,
+		},
+	}
+
+	ctx := &TransformContext{}
+	resources, err := transformer.Transform("MyHttpApi", api, ctx)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if _, ok := resources["MyHttpApiAccessLogGroup"]; ok {
+		t.Error("expected no log group to be created when DestinationArn is set")
+	}
+
+	stageResource := resources["MyHttpApiStage"].(map[string]interface{})
+	stageProps := stageResource["Properties"].(map[string]interface{})
+
+	accessLog := stageProps["AccessLogSettings"].(map[string]interface{})
+	if accessLog["DestinationArn"] != "arn:aws:logs:us-east-1:123456789:log-group:my-log-group" {
+		t.Errorf("expected provided DestinationArn, got %v", accessLog["DestinationArn"])
+	}
+}
+
 func TestHttpApiTransformer_Transform_WithDefaultRouteSettings(t *testing.T) {
 	transformer := NewHttpApiTransformer()
 
@@ -351,6 +424,88 @@ func TestHttpApiTransformer_Transform_WithRouteSettings(t *testing.T) {
 	}
 }
 
+func TestHttpApiTransformer_Transform_RouteSettingsValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		api     *HttpApi
+		wantErr string
+	}{
+		{
+			name: "valid route keys",
+			api: &HttpApi{
+				RouteSettings: map[string]interface{}{
+					"$default":          map[string]interface{}{"ThrottlingRateLimit": 10.5},
+					"POST /users/{id}":  map[string]interface{}{"ThrottlingBurstLimit": 0},
+					"ANY /{proxy+}":     map[string]interface{}{"DetailedMetricsEnabled": true},
+					"GET /items/search": map[string]interface{}{"ThrottlingBurstLimit": map[string]interface{}{"Ref": "Burst"}},
+				},
+			},
+		},
+		{
+			name: "missing method",
+			api: &HttpApi{
+				RouteSettings: map[string]interface{}{
+					"/users": map[string]interface{}{"ThrottlingBurstLimit": 100},
+				},
+			},
+			wantErr: "invalid RouteSettings key '/users'",
+		},
+		{
+			name: "unknown method",
+			api: &HttpApi{
+				RouteSettings: map[string]interface{}{
+					"FETCH /users": map[string]interface{}{},
+				},
+			},
+			wantErr: "invalid RouteSettings key 'FETCH /users'",
+		},
+		{
+			name: "path without leading slash",
+			api: &HttpApi{
+				RouteSettings: map[string]interface{}{
+					"GET users": map[string]interface{}{},
+				},
+			},
+			wantErr: "invalid RouteSettings key 'GET users'",
+		},
+		{
+			name: "negative route burst limit",
+			api: &HttpApi{
+				RouteSettings: map[string]interface{}{
+					"GET /users": map[string]interface{}{"ThrottlingBurstLimit": -1},
+				},
+			},
+			wantErr: "RouteSettings 'GET /users': ThrottlingBurstLimit must be non-negative",
+		},
+		{
+			name: "negative default rate limit",
+			api: &HttpApi{
+				DefaultRouteSettings: &HttpApiRouteSettings{ThrottlingRateLimit: -5.0},
+			},
+			wantErr: "DefaultRouteSettings: ThrottlingRateLimit must be non-negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewHttpApiTransformer()
+			_, err := transformer.Transform("MyHttpApi", tt.api, &TransformContext{})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestHttpApiTransformer_Transform_WithStageVariables(t *testing.T) {
 	transformer := NewHttpApiTransformer()
 
@@ -405,6 +560,17 @@ func TestHttpApiTransformer_Transform_WithTags(t *testing.T) {
 	if tags["Project"] != "demo" {
 		t.Errorf("expected Project tag 'demo', got %v", tags["Project"])
 	}
+
+	stage := resources["MyHttpApiStage"].(map[string]interface{})
+	stageProps := stage["Properties"].(map[string]interface{})
+
+	stageTags, ok := stageProps["Tags"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected Tags map on stage, got %v", stageProps["Tags"])
+	}
+	if stageTags["Environment"] != "test" || stageTags["Project"] != "demo" {
+		t.Errorf("expected stage tags to match API tags, got %v", stageTags)
+	}
 }
 
 func TestHttpApiTransformer_Transform_WithDefinitionBodyInline(t *testing.T) {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/lex00/aws-sam-translator-go/pkg/intrinsics"
 	"github.com/lex00/aws-sam-translator-go/pkg/model/iam"
 )
 
@@ -57,7 +58,8 @@ type TracingConfig struct {
 	Enabled bool `json:"Enabled,omitempty" yaml:"Enabled,omitempty"`
 }
 
-// LoggingConfig specifies logging configuration for state machines.
+// LoggingConfig specifies logging configuration for state machines. When
+// logging is enabled without Destinations, a log group is created for it.
 type LoggingConfig struct {
 	Level                string        `json:"Level,omitempty" yaml:"Level,omitempty"`
 	IncludeExecutionData bool          `json:"IncludeExecutionData,omitempty" yaml:"IncludeExecutionData,omitempty"`
@@ -76,6 +78,10 @@ func NewStateMachineTransformer() *StateMachineTransformer {
 func (t *StateMachineTransformer) Transform(logicalID string, sm *StateMachine) (map[string]interface{}, error) {
 	resources := make(map[string]interface{})
 
+	if sm.Logging != nil && sm.Logging.Level != "" && !containsString(stateMachineLogLevels, sm.Logging.Level) {
+		return nil, fmt.Errorf("Logging Level must be one of [%s], got '%s'", strings.Join(stateMachineLogLevels, ", "), sm.Logging.Level)
+	}
+
 	// Build the state machine properties
 	props := make(map[string]interface{})
 
@@ -90,6 +96,13 @@ func (t *StateMachineTransformer) Transform(logicalID string, sm *StateMachine)
 	}
 
 	// Handle definition
+	if sm.Definition != nil && sm.DefinitionUri != nil {
+		return nil, fmt.Errorf("specify either 'Definition' or 'DefinitionUri' property and not both")
+	}
+	if sm.Definition == nil && sm.DefinitionUri == nil {
+		return nil, fmt.Errorf("either 'Definition' or 'DefinitionUri' property must be specified")
+	}
+
 	if sm.Definition != nil {
 		// Convert inline definition to DefinitionString with Fn::Join
 		defString, err := t.convertDefinitionToString(sm.Definition)
@@ -148,7 +161,7 @@ func (t *StateMachineTransformer) Transform(logicalID string, sm *StateMachine)
 			"Enabled": sm.Tracing.Enabled,
 		}
 		// Add X-Ray policy to the generated role
-		if roleLogicalID != "" {
+		if sm.Tracing.Enabled && roleLogicalID != "" {
 			t.addXRayPolicyToRole(resources, roleLogicalID)
 		}
 	}
@@ -162,6 +175,19 @@ func (t *StateMachineTransformer) Transform(logicalID string, sm *StateMachine)
 		loggingConfig["IncludeExecutionData"] = sm.Logging.IncludeExecutionData
 		if len(sm.Logging.Destinations) > 0 {
 			loggingConfig["Destinations"] = sm.Logging.Destinations
+		} else if loggingEnabled(sm.Logging) {
+			logGroupLogicalID := logicalID + "LogGroup"
+			resources[logGroupLogicalID] = map[string]interface{}{
+				"Type":       "AWS::Logs::LogGroup",
+				"Properties": map[string]interface{}{},
+			}
+			loggingConfig["Destinations"] = []interface{}{
+				map[string]interface{}{
+					"CloudWatchLogsLogGroup": map[string]interface{}{
+						"LogGroupArn": intrinsics.NewGetAtt(logGroupLogicalID, "Arn"),
+					},
+				},
+			}
 		}
 		props["LoggingConfiguration"] = loggingConfig
 	}
@@ -214,12 +240,14 @@ func (t *StateMachineTransformer) buildDefinitionS3Location(definitionUri interf
 		return t.parseDefinitionUri(uri)
 	case map[string]interface{}:
 		// Already an S3 location object
-		s3Location := make(map[string]interface{})
-		if bucket, ok := uri["Bucket"]; ok {
-			s3Location["Bucket"] = bucket
+		bucket, hasBucket := uri["Bucket"]
+		key, hasKey := uri["Key"]
+		if !hasBucket || !hasKey {
+			return nil, fmt.Errorf("'DefinitionUri' requires Bucket and Key properties to be specified")
 		}
-		if key, ok := uri["Key"]; ok {
-			s3Location["Key"] = key
+		s3Location := map[string]interface{}{
+			"Bucket": bucket,
+			"Key":    key,
 		}
 		if version, ok := uri["Version"]; ok {
 			s3Location["Version"] = version
@@ -230,7 +258,8 @@ func (t *StateMachineTransformer) buildDefinitionS3Location(definitionUri interf
 	}
 }
 
-// parseDefinitionUri parses an S3 URI string into an S3 location map.
+// parseDefinitionUri parses an S3 URI string, with an optional versionId
+// query parameter, into an S3 location map.
 func (t *StateMachineTransformer) parseDefinitionUri(uri string) (map[string]interface{}, error) {
 	if !strings.HasPrefix(uri, "s3://") {
 		return nil, fmt.Errorf("DefinitionUri must be an S3 URI (s3://...): %s", uri)
@@ -238,17 +267,26 @@ func (t *StateMachineTransformer) parseDefinitionUri(uri string) (map[string]int
 
 	// Remove s3:// prefix
 	path := strings.TrimPrefix(uri, "s3://")
+	path, query, hasQuery := strings.Cut(path, "?")
 
 	// Split into bucket and key
 	parts := strings.SplitN(path, "/", 2)
-	if len(parts) < 2 {
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
 		return nil, fmt.Errorf("invalid S3 URI (missing key): %s", uri)
 	}
 
-	return map[string]interface{}{
+	location := map[string]interface{}{
 		"Bucket": parts[0],
 		"Key":    parts[1],
-	}, nil
+	}
+	if hasQuery {
+		version, ok := strings.CutPrefix(query, "versionId=")
+		if !ok || version == "" {
+			return nil, fmt.Errorf("invalid S3 URI (only a versionId query parameter is supported): %s", uri)
+		}
+		location["Version"] = version
+	}
+	return location, nil
 }
 
 // generateRole creates an IAM role for the state machine.
@@ -274,9 +312,39 @@ func (t *StateMachineTransformer) generateRole(logicalID string, sm *StateMachin
 		}
 	}
 
+	// Grant delivery of execution logs to CloudWatch Logs
+	if loggingEnabled(sm.Logging) {
+		role.AddInlinePolicy(logicalID+"LogDeliveryPolicy", iam.NewPolicyDocument().AddStatement(
+			iam.NewAllowStatement().WithActions(stateMachineLogDeliveryActions...).WithAllResources(),
+		))
+	}
+
 	return role, nil
 }
 
+// stateMachineLogLevels lists the valid state machine Logging levels.
+var stateMachineLogLevels = []string{"ALL", "ERROR", "FATAL", "OFF"}
+
+// stateMachineLogDeliveryActions are the CloudWatch Logs actions Step Functions
+// needs to deliver execution logs. Log delivery does not support resource-level
+// permissions, so they are granted on all resources.
+var stateMachineLogDeliveryActions = []string{
+	"logs:CreateLogDelivery",
+	"logs:GetLogDelivery",
+	"logs:UpdateLogDelivery",
+	"logs:DeleteLogDelivery",
+	"logs:ListLogDeliveries",
+	"logs:PutResourcePolicy",
+	"logs:DescribeResourcePolicies",
+	"logs:DescribeLogGroups",
+}
+
+// loggingEnabled reports whether logging sends execution logs at some level.
+// An unset Level defaults to OFF.
+func loggingEnabled(logging *LoggingConfig) bool {
+	return logging != nil && logging.Level != "" && logging.Level != "OFF"
+}
+
 // addPoliciesToRole adds policies from the SAM StateMachine to the role.
 func (t *StateMachineTransformer) addPoliciesToRole(role *iam.Role, logicalID string, policies interface{}) error {
 	switch p := policies.(type) {
@@ -371,7 +439,9 @@ func (t *StateMachineTransformer) buildStatementFromMap(m map[string]interface{}
 	return stmt
 }
 
-// addXRayPolicyToRole adds the X-Ray managed policy to the role.
+// addXRayPolicyToRole adds the X-Ray managed policy to the role. As in SAM,
+// the AWSXrayWriteOnlyAccess managed policy grants xray:PutTraceSegments,
+// xray:PutTelemetryRecords and the sampling rule actions.
 func (t *StateMachineTransformer) addXRayPolicyToRole(resources map[string]interface{}, roleLogicalID string) {
 	roleResource, ok := resources[roleLogicalID].(map[string]interface{})
 	if !ok {
@@ -2,6 +2,8 @@ package sam
 
 import (
 	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -198,6 +200,55 @@ func TestStateMachineTransformer_Transform_WithTags(t *testing.T) {
 	}
 }
 
+func TestStateMachineTransformer_Transform_Tracing(t *testing.T) {
+	transformer := NewStateMachineTransformer()
+	const xrayPolicy = "arn:aws:iam::aws:policy/AWSXrayWriteOnlyAccess"
+
+	tests := []struct {
+		name          string
+		enabled       bool
+		role          string
+		expectXRayArn bool
+	}{
+		{name: "enabled", enabled: true, expectXRayArn: true},
+		{name: "disabled", enabled: false},
+		{name: "enabled with explicit role", enabled: true, role: "arn:aws:iam::123456789012:role/MyRole"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources, err := transformer.Transform("MyStateMachine", &StateMachine{
+				Definition: map[string]interface{}{"StartAt": "Hello"},
+				Role:       tt.role,
+				Tracing:    &TracingConfig{Enabled: tt.enabled},
+			})
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			props := resources["MyStateMachine"].(map[string]interface{})["Properties"].(map[string]interface{})
+			expectedTracing := map[string]interface{}{"Enabled": tt.enabled}
+			if !reflect.DeepEqual(props["TracingConfiguration"], expectedTracing) {
+				t.Errorf("expected TracingConfiguration %v, got %v", expectedTracing, props["TracingConfiguration"])
+			}
+
+			hasXRayArn := false
+			if role, ok := resources["MyStateMachineRole"].(map[string]interface{}); ok {
+				roleProps := role["Properties"].(map[string]interface{})
+				managedPolicies, _ := roleProps["ManagedPolicyArns"].([]interface{})
+				for _, arn := range managedPolicies {
+					if arn == xrayPolicy {
+						hasXRayArn = true
+					}
+				}
+			}
+			if hasXRayArn != tt.expectXRayArn {
+				t.Errorf("expected X-Ray managed policy = %v, got %v", tt.expectXRayArn, hasXRayArn)
+			}
+		})
+	}
+}
+
 func TestStateMachineTransformer_Transform_WithInlinePolicies(t *testing.T) {
 	transformer := NewStateMachineTransformer()
 
@@ -453,6 +504,115 @@ func TestStateMachineTransformer_Transform_DefinitionUriObject(t *testing.T) {
 	}
 }
 
+func TestStateMachineTransformer_Transform_DefinitionForms(t *testing.T) {
+	transformer := NewStateMachineTransformer()
+	definition := map[string]interface{}{
+		"StartAt": "Hello",
+		"States": map[string]interface{}{
+			"Hello": map[string]interface{}{"Type": "Pass", "End": true},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		sm               *StateMachine
+		expectedLocation map[string]interface{}
+	}{
+		{
+			name: "S3 URI",
+			sm:   &StateMachine{DefinitionUri: "s3://my-bucket/definition.asl.json"},
+			expectedLocation: map[string]interface{}{
+				"Bucket": "my-bucket",
+				"Key":    "definition.asl.json",
+			},
+		},
+		{
+			name: "S3 URI with version",
+			sm:   &StateMachine{DefinitionUri: "s3://my-bucket/definition.asl.json?versionId=3"},
+			expectedLocation: map[string]interface{}{
+				"Bucket":  "my-bucket",
+				"Key":     "definition.asl.json",
+				"Version": "3",
+			},
+		},
+		{
+			name: "inline definition",
+			sm: &StateMachine{
+				Definition:              definition,
+				DefinitionSubstitutions: map[string]interface{}{"Name": "value"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources, err := transformer.Transform("StateMachine", tt.sm)
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+			props := resources["StateMachine"].(map[string]interface{})["Properties"].(map[string]interface{})
+
+			if tt.expectedLocation != nil {
+				if !reflect.DeepEqual(props["DefinitionS3Location"], tt.expectedLocation) {
+					t.Errorf("expected DefinitionS3Location %v, got %v", tt.expectedLocation, props["DefinitionS3Location"])
+				}
+				if _, ok := props["DefinitionString"]; ok {
+					t.Error("expected no DefinitionString for DefinitionUri")
+				}
+				return
+			}
+
+			if _, ok := props["DefinitionString"].(map[string]interface{})["Fn::Join"]; !ok {
+				t.Errorf("expected DefinitionString Fn::Join, got %v", props["DefinitionString"])
+			}
+			if !reflect.DeepEqual(props["DefinitionSubstitutions"], tt.sm.DefinitionSubstitutions) {
+				t.Errorf("expected DefinitionSubstitutions %v, got %v", tt.sm.DefinitionSubstitutions, props["DefinitionSubstitutions"])
+			}
+		})
+	}
+}
+
+func TestStateMachineTransformer_Transform_DefinitionValidation(t *testing.T) {
+	transformer := NewStateMachineTransformer()
+
+	tests := []struct {
+		name    string
+		sm      *StateMachine
+		wantErr string
+	}{
+		{
+			name: "both Definition and DefinitionUri",
+			sm: &StateMachine{
+				Definition:    map[string]interface{}{"StartAt": "Hello"},
+				DefinitionUri: "s3://my-bucket/definition.asl.json",
+			},
+			wantErr: "specify either 'Definition' or 'DefinitionUri' property and not both",
+		},
+		{
+			name:    "neither Definition nor DefinitionUri",
+			sm:      &StateMachine{},
+			wantErr: "either 'Definition' or 'DefinitionUri' property must be specified",
+		},
+		{
+			name:    "DefinitionUri object without Key",
+			sm:      &StateMachine{DefinitionUri: map[string]interface{}{"Bucket": "my-bucket"}},
+			wantErr: "'DefinitionUri' requires Bucket and Key properties to be specified",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := transformer.Transform("StateMachine", tt.sm)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestStateMachineTransformer_Transform_WithLogging(t *testing.T) {
 	transformer := NewStateMachineTransformer()
 
@@ -498,6 +658,104 @@ func TestStateMachineTransformer_Transform_WithLogging(t *testing.T) {
 	}
 }
 
+func TestStateMachineTransformer_Transform_LoggingPermissions(t *testing.T) {
+	transformer := NewStateMachineTransformer()
+	definition := map[string]interface{}{"StartAt": "Hello", "States": map[string]interface{}{}}
+	destinations := []interface{}{
+		map[string]interface{}{
+			"CloudWatchLogsLogGroup": map[string]interface{}{
+				"LogGroupArn": map[string]interface{}{"Fn::GetAtt": []interface{}{"MyLogGroup", "Arn"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name                 string
+		logging              *LoggingConfig
+		expectPolicy         bool
+		expectLogGroup       bool
+		expectedDestinations interface{}
+	}{
+		{
+			name:                 "explicit destination",
+			logging:              &LoggingConfig{Level: "ERROR", Destinations: destinations},
+			expectPolicy:         true,
+			expectedDestinations: destinations,
+		},
+		{
+			name:           "auto-created log group",
+			logging:        &LoggingConfig{Level: "ALL", IncludeExecutionData: true},
+			expectPolicy:   true,
+			expectLogGroup: true,
+			expectedDestinations: []interface{}{
+				map[string]interface{}{
+					"CloudWatchLogsLogGroup": map[string]interface{}{
+						"LogGroupArn": map[string]interface{}{"Fn::GetAtt": []interface{}{"StateMachineLogGroup", "Arn"}},
+					},
+				},
+			},
+		},
+		{
+			name:    "logging off",
+			logging: &LoggingConfig{Level: "OFF"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources, err := transformer.Transform("StateMachine", &StateMachine{Definition: definition, Logging: tt.logging})
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			props := resources["StateMachine"].(map[string]interface{})["Properties"].(map[string]interface{})
+			loggingConfig := props["LoggingConfiguration"].(map[string]interface{})
+			if loggingConfig["Level"] != tt.logging.Level {
+				t.Errorf("expected Level %s, got %v", tt.logging.Level, loggingConfig["Level"])
+			}
+			if loggingConfig["IncludeExecutionData"] != tt.logging.IncludeExecutionData {
+				t.Errorf("expected IncludeExecutionData %v, got %v", tt.logging.IncludeExecutionData, loggingConfig["IncludeExecutionData"])
+			}
+			if !reflect.DeepEqual(loggingConfig["Destinations"], tt.expectedDestinations) {
+				t.Errorf("expected Destinations %v, got %v", tt.expectedDestinations, loggingConfig["Destinations"])
+			}
+
+			_, hasLogGroup := resources["StateMachineLogGroup"]
+			if hasLogGroup != tt.expectLogGroup {
+				t.Errorf("expected log group created = %v, got %v", tt.expectLogGroup, hasLogGroup)
+			}
+
+			roleProps := resources["StateMachineRole"].(map[string]interface{})["Properties"].(map[string]interface{})
+			policies, _ := roleProps["Policies"].([]map[string]interface{})
+			hasPolicy := false
+			for _, policy := range policies {
+				if policy["PolicyName"] == "StateMachineLogDeliveryPolicy" {
+					hasPolicy = true
+					encoded, _ := json.Marshal(policy["PolicyDocument"])
+					if !strings.Contains(string(encoded), "logs:CreateLogDelivery") {
+						t.Errorf("expected log delivery actions, got %s", encoded)
+					}
+				}
+			}
+			if hasPolicy != tt.expectPolicy {
+				t.Errorf("expected log delivery policy = %v, got %v", tt.expectPolicy, hasPolicy)
+			}
+		})
+	}
+}
+
+func TestStateMachineTransformer_Transform_InvalidLoggingLevel(t *testing.T) {
+	transformer := NewStateMachineTransformer()
+
+	_, err := transformer.Transform("StateMachine", &StateMachine{
+		Definition: map[string]interface{}{"StartAt": "Hello"},
+		Logging:    &LoggingConfig{Level: "DEBUG"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "Logging Level must be one of [ALL, ERROR, FATAL, OFF]") {
+		t.Errorf("expected invalid Logging Level error, got %v", err)
+	}
+}
+
 func TestStateMachineTransformer_Transform_WithRolePath(t *testing.T) {
 	transformer := NewStateMachineTransformer()
 
@@ -152,8 +152,14 @@ func BenchmarkTransformAllFixtures(b *testing.B) {
 	}
 }
 
-// BenchmarkNewTranslator benchmarks translator creation.
+// BenchmarkNewTranslator benchmarks translator creation. The policy templates
+// and connector profiles are built once per process, so after the first call
+// a new translator only allocates its own lookup maps.
 func BenchmarkNewTranslator(b *testing.B) {
+	_ = New()
+	b.ReportAllocs()
+	b.ResetTimer()
+
 	for i := 0; i < b.N; i++ {
 		_ = New()
 	}
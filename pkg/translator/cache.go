@@ -0,0 +1,125 @@
+package translator
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+)
+
+// transformCache is a fixed-size LRU cache of TransformBytes results keyed by
+// a hash of the input bytes and the translator options. Caching is only sound
+// because transforms are deterministic: the same input and options always
+// produce the same output bytes.
+type transformCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+
+	// hits and misses count lookups, for tests and diagnostics
+	hits   int
+	misses int
+}
+
+// cacheEntry is a cached transform result.
+type cacheEntry struct {
+	key         string
+	output      []byte
+	warnings    []string
+	diagnostics []Diagnostic
+}
+
+// newTransformCache creates a cache holding at most size entries.
+func newTransformCache(size int) *transformCache {
+	return &transformCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// WithCache enables an in-memory cache of up to size TransformBytes results,
+// so tools that repeatedly transform the same template skip the transform on
+// a hit. A size of zero or less disables the cache. Plugins registered with
+// RegisterPlugin are not part of the cache key, so register them before the
+// first transform. WithCache returns t to allow chaining.
+func (t *Translator) WithCache(size int) *Translator {
+	if size <= 0 {
+		t.cache = nil
+		return t
+	}
+	t.cache = newTransformCache(size)
+	return t
+}
+
+// cacheKey hashes the input bytes together with the options that affect the output.
+func cacheKey(input []byte, opts Options) (string, error) {
+	// encoding/json sorts map keys, so FeatureToggles encode deterministically
+	encodedOpts, err := json.Marshal(opts)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	h.Write(encodedOpts)
+	h.Write([]byte{0})
+	h.Write(input)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// get returns a copy of the cached output for key, with its warnings and
+// diagnostics as a transform state.
+func (c *transformCache) get(key string) ([]byte, *transformState, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, nil, false
+	}
+	entry, ok := elem.Value.(*cacheEntry)
+	if !ok {
+		c.misses++
+		return nil, nil, false
+	}
+	c.hits++
+	c.order.MoveToFront(elem)
+
+	state := &transformState{
+		warnings:    append([]string(nil), entry.warnings...),
+		diagnostics: append([]Diagnostic(nil), entry.diagnostics...),
+	}
+	return append([]byte(nil), entry.output...), state, true
+}
+
+// put stores a copy of output and the warnings and diagnostics of state under
+// key, evicting the least recently used entry when the cache is full.
+func (c *transformCache) put(key string, output []byte, state *transformState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{
+		key:         key,
+		output:      append([]byte(nil), output...),
+		warnings:    append([]string(nil), state.warnings...),
+		diagnostics: append([]Diagnostic(nil), state.diagnostics...),
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		if entry, ok := oldest.Value.(*cacheEntry); ok {
+			delete(c.entries, entry.key)
+		}
+	}
+}
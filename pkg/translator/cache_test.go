@@ -0,0 +1,97 @@
+package translator
+
+import (
+	"bytes"
+	"testing"
+)
+
+const cacheTestTemplate = `
+Transform: AWS::Serverless-2016-10-31
+Resources:
+  MyFunction:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: index.handler
+      Runtime: nodejs18.x
+      CodeUri: s3://bucket/key
+      Unknown: true
+`
+
+func TestTransformBytesCacheHit(t *testing.T) {
+	tr := New().WithCache(4)
+
+	first, err := tr.TransformBytes([]byte(cacheTestTemplate))
+	if err != nil {
+		t.Fatalf("TransformBytes failed: %v", err)
+	}
+	// Clear the first transform's warnings so the hit must restore them
+	tr.setWarnings(&transformState{})
+	second, err := tr.TransformBytes([]byte(cacheTestTemplate))
+	if err != nil {
+		t.Fatalf("TransformBytes failed: %v", err)
+	}
+
+	if !bytes.Equal(first, second) {
+		t.Error("expected cache hit to return identical bytes")
+	}
+	if tr.cache.hits != 1 || tr.cache.misses != 1 {
+		t.Errorf("expected 1 hit and 1 miss, got %d hits and %d misses", tr.cache.hits, tr.cache.misses)
+	}
+	if len(tr.Warnings()) != 1 {
+		t.Errorf("expected cached warnings to be restored, got %v", tr.Warnings())
+	}
+	if diagnostics := tr.Diagnostics(); len(diagnostics) != 1 || diagnostics[0].Path != "Properties.Unknown" {
+		t.Errorf("expected cached diagnostics to be restored, got %v", diagnostics)
+	}
+
+	// Mutating a returned result must not affect the cached copy
+	second[0] = 'x'
+	third, err := tr.TransformBytes([]byte(cacheTestTemplate))
+	if err != nil {
+		t.Fatalf("TransformBytes failed: %v", err)
+	}
+	if !bytes.Equal(first, third) {
+		t.Error("expected cached bytes to be unaffected by caller mutation")
+	}
+}
+
+func TestTransformBytesCacheMissOnOptionsChange(t *testing.T) {
+	tr := New().WithCache(4)
+	other := NewWithOptions(Options{Region: "cn-north-1", Partition: "aws-cn"})
+	other.cache = tr.cache
+
+	if _, err := tr.TransformBytes([]byte(cacheTestTemplate)); err != nil {
+		t.Fatalf("TransformBytes failed: %v", err)
+	}
+	if _, err := other.TransformBytes([]byte(cacheTestTemplate)); err != nil {
+		t.Fatalf("TransformBytes failed: %v", err)
+	}
+
+	if tr.cache.hits != 0 || tr.cache.misses != 2 {
+		t.Errorf("expected different options to miss the cache, got %d hits and %d misses", tr.cache.hits, tr.cache.misses)
+	}
+}
+
+func TestTransformCacheEviction(t *testing.T) {
+	cache := newTransformCache(2)
+	cache.put("a", []byte("1"), &transformState{})
+	cache.put("b", []byte("2"), &transformState{})
+	cache.get("a")
+	cache.put("c", []byte("3"), &transformState{})
+
+	if _, _, ok := cache.get("b"); ok {
+		t.Error("expected least recently used entry to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, _, ok := cache.get(key); !ok {
+			t.Errorf("expected entry %q to be cached", key)
+		}
+	}
+}
+
+func TestWithCacheDisabled(t *testing.T) {
+	tr := New().WithCache(4).WithCache(0)
+	if tr.cache != nil {
+		t.Error("expected WithCache(0) to disable the cache")
+	}
+}
@@ -312,6 +312,8 @@ func runSuccessFixtureCheck(fixture testFixture) error {
 		AccountID: "123456789012",
 		StackName: "sam-app",
 		Partition: "aws",
+		// The Python fixtures do not all declare the SAM transform
+		TransformHeader: TransformHeaderIgnore,
 	})
 
 	output, err := tr.TransformBytes(input)
@@ -418,6 +420,8 @@ func runErrorFixtureCheck(fixture testFixture) error {
 		AccountID: "123456789012",
 		StackName: "sam-app",
 		Partition: "aws",
+		// The Python fixtures do not all declare the SAM transform
+		TransformHeader: TransformHeaderIgnore,
 	})
 
 	_, err = tr.TransformBytes(input)
@@ -443,6 +447,8 @@ func runPartitionFixtureCheck(fixture testFixture, partition, region string) err
 		AccountID: "123456789012",
 		StackName: "sam-app",
 		Partition: partition,
+		// The Python fixtures do not all declare the SAM transform
+		TransformHeader: TransformHeaderIgnore,
 	})
 
 	output, err := tr.TransformBytes(input)
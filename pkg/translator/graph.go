@@ -0,0 +1,198 @@
+package translator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lex00/aws-sam-translator-go/pkg/parser"
+	"github.com/lex00/aws-sam-translator-go/pkg/types"
+)
+
+// Dependency kinds recorded on graph edges.
+const (
+	EdgeDependsOn = "DependsOn"
+	EdgeRef       = "Ref"
+	EdgeGetAtt    = "GetAtt"
+)
+
+// GraphNode is a resource in a ResourceGraph.
+type GraphNode struct {
+	LogicalID string `json:"logicalId"`
+	Type      string `json:"type"`
+}
+
+// GraphEdge records that the From resource depends on the To resource.
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	// Kind is EdgeDependsOn, EdgeRef or EdgeGetAtt. References made through
+	// Fn::Sub variables are recorded as EdgeRef or EdgeGetAtt.
+	Kind string `json:"kind"`
+}
+
+// ResourceGraph is the dependency graph of a CloudFormation template's
+// resources, with nodes sorted by logical ID and edges sorted by endpoints.
+type ResourceGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// Graph transforms a YAML or JSON SAM template and returns the dependency
+// graph of the resulting resources, including the ones the transform
+// generated.
+func (t *Translator) Graph(input []byte) (*ResourceGraph, error) {
+	raw, err := parser.New().ParseRaw(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	output, state, err := t.transformRaw(raw)
+	t.setWarnings(state)
+	if err != nil {
+		return nil, err
+	}
+	return NewResourceGraph(output), nil
+}
+
+// NewResourceGraph builds the dependency graph of a template's resources from
+// their DependsOn attributes and the Ref, Fn::GetAtt and Fn::Sub references in
+// their properties. References to parameters and pseudo parameters are not
+// edges.
+func NewResourceGraph(template *types.Template) *ResourceGraph {
+	graph := &ResourceGraph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	seen := make(map[GraphEdge]bool)
+	addEdge := func(from, to, kind string) {
+		edge := GraphEdge{From: from, To: to, Kind: kind}
+		if _, exists := template.Resources[to]; !exists || seen[edge] {
+			return
+		}
+		seen[edge] = true
+		graph.Edges = append(graph.Edges, edge)
+	}
+
+	for id, res := range template.Resources {
+		graph.Nodes = append(graph.Nodes, GraphNode{LogicalID: id, Type: res.Type})
+
+		switch dependsOn := res.DependsOn.(type) {
+		case string:
+			addEdge(id, dependsOn, EdgeDependsOn)
+		case []string:
+			for _, dep := range dependsOn {
+				addEdge(id, dep, EdgeDependsOn)
+			}
+		case []interface{}:
+			for _, dep := range dependsOn {
+				if name, ok := dep.(string); ok {
+					addEdge(id, name, EdgeDependsOn)
+				}
+			}
+		}
+
+		collectReferences(res.Properties, func(to, kind string) { addEdge(id, to, kind) })
+	}
+
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].LogicalID < graph.Nodes[j].LogicalID
+	})
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Kind < b.Kind
+	})
+	return graph
+}
+
+// collectReferences calls add with the target and kind of every Ref,
+// Fn::GetAtt and Fn::Sub variable in value.
+func collectReferences(value interface{}, add func(to, kind string)) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 1 {
+			if ref, ok := v["Ref"].(string); ok {
+				add(ref, EdgeRef)
+				return
+			}
+			switch getAtt := v["Fn::GetAtt"].(type) {
+			case string:
+				target, _, _ := strings.Cut(getAtt, ".")
+				add(target, EdgeGetAtt)
+				return
+			case []interface{}:
+				if len(getAtt) > 0 {
+					if target, ok := getAtt[0].(string); ok {
+						add(target, EdgeGetAtt)
+					}
+				}
+				return
+			case []string:
+				if len(getAtt) > 0 {
+					add(getAtt[0], EdgeGetAtt)
+				}
+				return
+			}
+			switch sub := v["Fn::Sub"].(type) {
+			case string:
+				collectSubReferences(sub, nil, add)
+				return
+			case []interface{}:
+				if len(sub) == 2 {
+					vars, _ := sub[1].(map[string]interface{})
+					if str, ok := sub[0].(string); ok {
+						collectSubReferences(str, vars, add)
+					}
+					collectReferences(sub[1], add)
+				}
+				return
+			}
+		}
+		for _, item := range v {
+			collectReferences(item, add)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectReferences(item, add)
+		}
+	case []map[string]interface{}:
+		for _, item := range v {
+			collectReferences(item, add)
+		}
+	}
+}
+
+// collectSubReferences calls add for each ${Name} or ${Name.Attribute}
+// variable of an Fn::Sub string that is not defined in vars.
+func collectSubReferences(sub string, vars map[string]interface{}, add func(to, kind string)) {
+	for _, match := range subVariablePattern.FindAllStringSubmatch(sub, -1) {
+		name, attribute, hasAttribute := strings.Cut(match[1], ".")
+		if _, isVar := vars[name]; isVar {
+			continue
+		}
+		if hasAttribute && attribute != "" {
+			add(name, EdgeGetAtt)
+		} else {
+			add(name, EdgeRef)
+		}
+	}
+}
+
+// DOT renders the graph in Graphviz DOT format, labelling each node with its
+// logical ID and type and each edge with its kind.
+func (g *ResourceGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph resources {\n")
+	b.WriteString("  rankdir=LR;\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&b, "  %q [label=%q];\n", node.LogicalID, node.LogicalID+"\n"+node.Type)
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", edge.From, edge.To, edge.Kind)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
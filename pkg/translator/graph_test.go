@@ -0,0 +1,117 @@
+package translator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lex00/aws-sam-translator-go/pkg/types"
+)
+
+const graphTestTemplate = `
+Transform: AWS::Serverless-2016-10-31
+Parameters:
+  Stage:
+    Type: String
+Resources:
+  MyFunction:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: index.handler
+      Runtime: nodejs18.x
+      CodeUri: s3://bucket/key
+      Environment:
+        Variables:
+          STAGE: !Ref Stage
+      Events:
+        GetItems:
+          Type: Api
+          Properties:
+            Path: /items
+            Method: get
+`
+
+func TestGraph(t *testing.T) {
+	graph, err := New().Graph([]byte(graphTestTemplate))
+	if err != nil {
+		t.Fatalf("Graph failed: %v", err)
+	}
+
+	nodes := make(map[string]string)
+	for _, node := range graph.Nodes {
+		nodes[node.LogicalID] = node.Type
+	}
+	if nodes["MyFunction"] != "AWS::Lambda::Function" {
+		t.Errorf("MyFunction node type = %q, want AWS::Lambda::Function", nodes["MyFunction"])
+	}
+
+	edges := make(map[GraphEdge]bool)
+	for _, edge := range graph.Edges {
+		edges[edge] = true
+		if edge.To == "Stage" {
+			t.Errorf("unexpected edge to parameter: %+v", edge)
+		}
+	}
+
+	for _, want := range []GraphEdge{
+		{From: "MyFunction", To: "MyFunctionRole", Kind: EdgeGetAtt},
+		{From: "MyFunctionGetItemsPermission", To: "MyFunction", Kind: EdgeGetAtt},
+		{From: "ServerlessRestApi", To: "MyFunction", Kind: EdgeGetAtt},
+		{From: "ServerlessRestApiStage", To: "ServerlessRestApi", Kind: EdgeRef},
+	} {
+		if !edges[want] {
+			t.Errorf("missing edge %+v in %+v", want, graph.Edges)
+		}
+	}
+}
+
+func TestNewResourceGraph(t *testing.T) {
+	template := &types.Template{
+		Resources: map[string]types.Resource{
+			"Bucket": {Type: "AWS::S3::Bucket"},
+			"Topic":  {Type: "AWS::SNS::Topic"},
+			"Policy": {
+				Type:      "AWS::S3::BucketPolicy",
+				DependsOn: []interface{}{"Topic", "Missing"},
+				Properties: map[string]interface{}{
+					"Bucket": map[string]interface{}{"Ref": "Bucket"},
+					"PolicyDocument": map[string]interface{}{
+						"Resource": map[string]interface{}{"Fn::Sub": "${Bucket.Arn}/*"},
+						"Region":   map[string]interface{}{"Fn::Sub": "${AWS::Region}"},
+						"Name": map[string]interface{}{"Fn::Sub": []interface{}{
+							"${Name}-${Topic}",
+							map[string]interface{}{"Name": "fixed"},
+						}},
+					},
+				},
+			},
+		},
+	}
+
+	graph := NewResourceGraph(template)
+
+	want := []GraphEdge{
+		{From: "Policy", To: "Bucket", Kind: EdgeGetAtt},
+		{From: "Policy", To: "Bucket", Kind: EdgeRef},
+		{From: "Policy", To: "Topic", Kind: EdgeDependsOn},
+		{From: "Policy", To: "Topic", Kind: EdgeRef},
+	}
+	if len(graph.Edges) != len(want) {
+		t.Fatalf("edges = %+v, want %+v", graph.Edges, want)
+	}
+	for i := range want {
+		if graph.Edges[i] != want[i] {
+			t.Errorf("edge %d = %+v, want %+v", i, graph.Edges[i], want[i])
+		}
+	}
+
+	dot := graph.DOT()
+	for _, line := range []string{
+		"digraph resources {",
+		`"Bucket" [label="Bucket\nAWS::S3::Bucket"];`,
+		`"Policy" -> "Topic" [label="DependsOn"];`,
+	} {
+		if !strings.Contains(dot, line) {
+			t.Errorf("DOT output missing %q:\n%s", line, dot)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package translator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/lex00/aws-sam-translator-go/pkg/types"
+)
+
+// canonicalTemplateKeys is the order of top-level template sections in
+// canonical output. Sections not listed follow in sorted order.
+var canonicalTemplateKeys = []string{
+	"AWSTemplateFormatVersion", "Transform", "Description", "Metadata",
+	"Parameters", "Mappings", "Conditions", "Resources", "Outputs",
+}
+
+// canonicalResourceKeys is the order of resource attributes in canonical
+// output. Attributes not listed follow in sorted order.
+var canonicalResourceKeys = []string{
+	"Type", "Condition", "DependsOn", "Metadata", "Properties",
+	"DeletionPolicy", "UpdateReplacePolicy", "UpdatePolicy",
+}
+
+// orderedObject is a JSON object that serializes its keys in a fixed order.
+type orderedObject struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+// MarshalJSON writes the object's keys in order.
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		buf.Write(o.values[key])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// newOrderedObject orders the keys of values by preferred, then sorted.
+func newOrderedObject(values map[string]json.RawMessage, preferred []string) orderedObject {
+	keys := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(preferred))
+	for _, key := range preferred {
+		seen[key] = true
+		if _, ok := values[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	var rest []string
+	for key := range values {
+		if !seen[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return orderedObject{keys: append(keys, rest...), values: values}
+}
+
+// marshalCanonical serializes a template with its top-level sections in
+// canonical order, resources sorted by logical ID and each resource's
+// attributes in canonical order. Nested property maps are sorted by key.
+func marshalCanonical(template *types.Template) ([]byte, error) {
+	encoded, err := json.Marshal(template)
+	if err != nil {
+		return nil, err
+	}
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &sections); err != nil {
+		return nil, err
+	}
+
+	if rawResources, ok := sections["Resources"]; ok {
+		var resources map[string]map[string]json.RawMessage
+		if err := json.Unmarshal(rawResources, &resources); err != nil {
+			return nil, err
+		}
+
+		orderedResources := make(map[string]json.RawMessage, len(resources))
+		for logicalID, attributes := range resources {
+			encodedResource, err := json.Marshal(newOrderedObject(attributes, canonicalResourceKeys))
+			if err != nil {
+				return nil, fmt.Errorf("resource '%s': %w", logicalID, err)
+			}
+			orderedResources[logicalID] = encodedResource
+		}
+
+		// No preferred keys, so resources are sorted by logical ID
+		sections["Resources"], err = json.Marshal(newOrderedObject(orderedResources, nil))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return json.MarshalIndent(newOrderedObject(sections, canonicalTemplateKeys), "", "  ")
+}
@@ -0,0 +1,87 @@
+package translator
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+const keyOrderTestTemplate = `
+Resources:
+  ZFunction:
+    Type: AWS::Serverless::Function
+    Condition: IsProd
+    Properties:
+      Handler: index.handler
+      Runtime: nodejs18.x
+      CodeUri: s3://bucket/key
+Outputs:
+  Arn:
+    Value: !GetAtt ZFunction.Arn
+Conditions:
+  IsProd: !Equals [!Ref Env, prod]
+Parameters:
+  Env:
+    Type: String
+Description: ordered output
+Transform: AWS::Serverless-2016-10-31
+AWSTemplateFormatVersion: "2010-09-09"
+`
+
+// keyPositions returns the byte offset of each key in output, failing when a key is missing.
+func keyPositions(t *testing.T, output []byte, keys ...string) []int {
+	t.Helper()
+	positions := make([]int, len(keys))
+	for i, key := range keys {
+		positions[i] = bytes.Index(output, []byte(`"`+key+`":`))
+		if positions[i] < 0 {
+			t.Fatalf("expected key %q in output:\n%s", key, output)
+		}
+	}
+	return positions
+}
+
+func TestTransformBytesCanonicalKeyOrder(t *testing.T) {
+	tr := NewWithOptions(Options{CanonicalKeyOrder: true})
+
+	output, err := tr.TransformBytes([]byte(keyOrderTestTemplate))
+	if err != nil {
+		t.Fatalf("TransformBytes failed: %v", err)
+	}
+
+	sections := keyPositions(t, output,
+		"AWSTemplateFormatVersion", "Description", "Parameters", "Conditions", "Resources", "Outputs")
+	for i := 1; i < len(sections); i++ {
+		if sections[i] <= sections[i-1] {
+			t.Fatalf("expected top-level sections in canonical order, got:\n%s", output)
+		}
+	}
+
+	// Resources are sorted by logical ID, and attributes follow the canonical order
+	resources := keyPositions(t, output, "ZFunction", "ZFunctionRole")
+	if resources[0] >= resources[1] {
+		t.Errorf("expected resources sorted by logical ID, got:\n%s", output)
+	}
+	attributes := keyPositions(t, output[resources[0]:], "Type", "Condition", "Properties")
+	if attributes[0] >= attributes[1] || attributes[1] >= attributes[2] {
+		t.Errorf("expected resource attributes Type, Condition, Properties, got:\n%s", output[resources[0]:])
+	}
+
+	// Ordering changes only the serialization, not the template
+	plain, err := New().TransformBytes([]byte(keyOrderTestTemplate))
+	if err != nil {
+		t.Fatalf("TransformBytes failed: %v", err)
+	}
+	var orderedValue, plainValue interface{}
+	if err := json.Unmarshal(output, &orderedValue); err != nil {
+		t.Fatalf("canonical output is not valid JSON: %v", err)
+	}
+	if err := json.Unmarshal(plain, &plainValue); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	orderedJSON, _ := json.Marshal(orderedValue)
+	plainJSON, _ := json.Marshal(plainValue)
+	if !bytes.Equal(orderedJSON, plainJSON) {
+		t.Error("expected canonical output to decode to the same template")
+	}
+}
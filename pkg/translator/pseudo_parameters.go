@@ -0,0 +1,153 @@
+package translator
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/lex00/aws-sam-translator-go/pkg/types"
+)
+
+// usePseudoParameters reports whether generated ARNs keep CloudFormation
+// pseudo parameters, which is the default.
+func (o Options) usePseudoParameters() bool {
+	return o.UsePseudoParameters == nil || *o.UsePseudoParameters
+}
+
+// pseudoParameterValues maps the pseudo parameters resolved when
+// UsePseudoParameters is false to the translator's literal values.
+func (t *Translator) pseudoParameterValues() map[string]string {
+	return map[string]string{
+		"AWS::Partition": t.options.Partition,
+		"AWS::Region":    t.options.Region,
+		"AWS::AccountId": t.options.AccountID,
+	}
+}
+
+// resolvePseudoParameters replaces the AWS::Partition, AWS::Region and
+// AWS::AccountId pseudo parameters in the properties of generated resources
+// with literal values. Resources defined directly in the template, which have
+// an empty owner, are left unchanged, as are the values in authored that SAM
+// resources copy into the resources they generate.
+func (t *Translator) resolvePseudoParameters(output *types.Template, owners map[string]string, authored map[uintptr]bool) {
+	values := t.pseudoParameterValues()
+	for id, res := range output.Resources {
+		if owners[id] == "" || res.Properties == nil {
+			continue
+		}
+		props, ok := resolvePseudoParameterValue(res.Properties, values, authored).(map[string]interface{})
+		if !ok {
+			continue
+		}
+		res.Properties = props
+		output.Resources[id] = res
+	}
+}
+
+// templateValues returns the identities of the maps and slices in the
+// template's Globals and in the properties of its SAM resources. Generated
+// resources share these values rather than copying them, so they identify
+// what the user wrote.
+func templateValues(template *types.Template) map[uintptr]bool {
+	authored := make(map[uintptr]bool)
+	var collect func(value interface{})
+	collect = func(value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			authored[reflect.ValueOf(v).Pointer()] = true
+			for _, item := range v {
+				collect(item)
+			}
+		case []interface{}:
+			if len(v) > 0 {
+				authored[reflect.ValueOf(v).Pointer()] = true
+			}
+			for _, item := range v {
+				collect(item)
+			}
+		}
+	}
+	collect(template.Globals)
+	for _, res := range template.Resources {
+		if isSAMResource(res.Type) {
+			collect(res.Properties)
+		}
+	}
+	return authored
+}
+
+// isAuthored reports whether value is one of the authored maps or slices.
+func isAuthored(value interface{}, authored map[uintptr]bool) bool {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		ptr := reflect.ValueOf(value).Pointer()
+		return ptr != 0 && authored[ptr]
+	}
+	return false
+}
+
+// resolvePseudoParameterValue returns a copy of value with pseudo parameter
+// Refs replaced by their literal values and substituted into Fn::Sub strings.
+// An Fn::Sub left without variables collapses to a plain string. Authored
+// values are returned unchanged.
+func resolvePseudoParameterValue(value interface{}, values map[string]string, authored map[uintptr]bool) interface{} {
+	if isAuthored(value, authored) {
+		return value
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 1 {
+			if ref, ok := v["Ref"].(string); ok {
+				if literal, ok := values[ref]; ok {
+					return literal
+				}
+			}
+			if sub, ok := v["Fn::Sub"].(string); ok {
+				sub = substitutePseudoParameters(sub, values)
+				if !strings.Contains(strings.ReplaceAll(sub, "${!", ""), "${") {
+					return strings.ReplaceAll(sub, "${!", "${")
+				}
+				return map[string]interface{}{"Fn::Sub": sub}
+			}
+			if sub, ok := v["Fn::Sub"].([]interface{}); ok && len(sub) == 2 && !isAuthored(sub, authored) {
+				if str, ok := sub[0].(string); ok {
+					return map[string]interface{}{"Fn::Sub": []interface{}{
+						substitutePseudoParameters(str, values),
+						resolvePseudoParameterValue(sub[1], values, authored),
+					}}
+				}
+			}
+		}
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = resolvePseudoParameterValue(item, values, authored)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = resolvePseudoParameterValue(item, values, authored)
+		}
+		return result
+	case []map[string]interface{}:
+		result := make([]map[string]interface{}, len(v))
+		for i, item := range v {
+			if resolved, ok := resolvePseudoParameterValue(item, values, authored).(map[string]interface{}); ok {
+				result[i] = resolved
+			} else {
+				result[i] = item
+			}
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// substitutePseudoParameters replaces ${AWS::...} pseudo parameter variables
+// in an Fn::Sub string. Escaped ${!AWS::...} literals are left unchanged.
+func substitutePseudoParameters(sub string, values map[string]string) string {
+	for name, literal := range values {
+		sub = strings.ReplaceAll(sub, "${"+name+"}", literal)
+	}
+	return sub
+}
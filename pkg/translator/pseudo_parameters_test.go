@@ -0,0 +1,136 @@
+package translator
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const pseudoParametersTestTemplate = `
+Transform: AWS::Serverless-2016-10-31
+Resources:
+  MyFunction:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: index.handler
+      Runtime: nodejs18.x
+      CodeUri: s3://bucket/key
+      Environment:
+        Variables:
+          REGION: !Sub "${AWS::Region}"
+      Policies:
+        - SSMParameterReadPolicy:
+            ParameterName: app/config
+      Events:
+        Get:
+          Type: HttpApi
+          Properties:
+            Path: /items
+            Method: get
+  MyIdentity:
+    Type: AWS::SES::EmailIdentity
+    Properties:
+      EmailIdentity: example.com
+  EmailConnector:
+    Type: AWS::Serverless::Connector
+    Properties:
+      Source:
+        Id: MyFunction
+      Destination:
+        Id: MyIdentity
+      Permissions:
+        - Write
+  OtherTopic:
+    Type: AWS::SNS::Topic
+    Properties:
+      TopicName: !Sub "${AWS::StackName}-${AWS::Region}"
+`
+
+func TestTransformUsePseudoParameters(t *testing.T) {
+	literal := false
+	tests := []struct {
+		name        string
+		opts        Options
+		wantPresent []string
+		wantAbsent  []string
+	}{
+		{
+			name: "pseudo parameters by default",
+			opts: Options{Region: "eu-west-1", AccountID: "111122223333", Partition: "aws"},
+			wantPresent: []string{
+				"arn:${AWS::Partition}:ssm:${AWS::Region}:${AWS::AccountId}:parameter/${parameterName}",
+				"arn:${AWS::Partition}:execute-api:${AWS::Region}:${AWS::AccountId}:",
+				"arn:${AWS::Partition}:ses:${AWS::Region}:${AWS::AccountId}:identity/${Identity}",
+			},
+			wantAbsent: []string{"111122223333"},
+		},
+		{
+			name: "literal context values",
+			opts: Options{Region: "eu-west-1", AccountID: "111122223333", Partition: "aws", UsePseudoParameters: &literal},
+			wantPresent: []string{
+				"arn:aws:ssm:eu-west-1:111122223333:parameter/${parameterName}",
+				"arn:aws:execute-api:eu-west-1:111122223333:",
+				"arn:aws:ses:eu-west-1:111122223333:identity/${Identity}",
+			},
+			wantAbsent: []string{"${AWS::Partition}", "${AWS::Region}", "${AWS::AccountId}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewWithOptions(tt.opts).TransformBytes([]byte(pseudoParametersTestTemplate))
+			if err != nil {
+				t.Fatalf("TransformBytes failed: %v", err)
+			}
+
+			var output map[string]map[string]json.RawMessage
+			if err := json.Unmarshal(result, &output); err != nil {
+				t.Fatalf("failed to parse output: %v", err)
+			}
+			userResource := string(output["Resources"]["OtherTopic"])
+			delete(output["Resources"], "OtherTopic")
+
+			// Values copied from the template into generated resources are kept
+			var function bytes.Buffer
+			if err := json.Compact(&function, output["Resources"]["MyFunction"]); err != nil {
+				t.Fatalf("failed to compact function: %v", err)
+			}
+			if !strings.Contains(function.String(), `"REGION":{"Fn::Sub":"${AWS::Region}"}`) {
+				t.Errorf("expected function environment to keep its pseudo parameter, got %s", function.String())
+			}
+			delete(output["Resources"], "MyFunction")
+			generated, _ := json.Marshal(output["Resources"])
+
+			for _, want := range tt.wantPresent {
+				if !strings.Contains(string(generated), want) {
+					t.Errorf("expected output to contain %q", want)
+				}
+			}
+			for _, unwanted := range tt.wantAbsent {
+				if strings.Contains(string(generated), unwanted) {
+					t.Errorf("expected output not to contain %q", unwanted)
+				}
+			}
+
+			// Resources defined directly in the template are never rewritten
+			if !strings.Contains(userResource, "${AWS::Region}") {
+				t.Errorf("expected template resource to keep its pseudo parameters, got %s", userResource)
+			}
+		})
+	}
+}
+
+func TestUsePseudoParametersRequiresRegionAndAccount(t *testing.T) {
+	literal := false
+	for _, opts := range []Options{
+		{UsePseudoParameters: &literal},
+		{Region: "eu-west-1", UsePseudoParameters: &literal},
+		{AccountID: "111122223333", UsePseudoParameters: &literal},
+	} {
+		_, err := NewWithOptions(opts).TransformBytes([]byte(pseudoParametersTestTemplate))
+		if err == nil || !strings.Contains(err.Error(), "requires both Region and AccountID") {
+			t.Errorf("options %+v: expected an options error, got %v", opts, err)
+		}
+	}
+}
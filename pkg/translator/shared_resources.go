@@ -0,0 +1,198 @@
+package translator
+
+// This file contains the reconciliation of event sources that modify resources
+// shared across functions, such as S3 buckets notifying several functions.
+
+import (
+	"encoding/json"
+	"fmt"
+
+	samerrors "github.com/lex00/aws-sam-translator-go/pkg/errors"
+	"github.com/lex00/aws-sam-translator-go/pkg/sam"
+	"github.com/lex00/aws-sam-translator-go/pkg/types"
+)
+
+// bucketNotification is an S3 notification resolved to its target bucket.
+type bucketNotification struct {
+	bucketID     string
+	notification sam.S3Notification
+}
+
+// collectS3Notifications resolves the buckets targeted by the function's S3
+// events. Each bucket must be an AWS::S3::Bucket referenced from the template.
+func collectS3Notifications(logicalID string, fn *sam.Function, template *types.Template) ([]bucketNotification, error) {
+	var result []bucketNotification
+	for _, notification := range sam.S3Notifications(logicalID, fn) {
+		bucket, _ := notification.Bucket.(map[string]interface{})
+		bucketID, _ := bucket["Ref"].(string)
+		if res, exists := template.Resources[bucketID]; !exists || res.Type != "AWS::S3::Bucket" {
+			return nil, &samerrors.InvalidEventException{
+				ResourceID: logicalID,
+				EventID:    notification.EventName,
+				Message:    "S3 events must reference an S3 bucket in the same template",
+			}
+		}
+		result = append(result, bucketNotification{bucketID: bucketID, notification: notification})
+	}
+	return result, nil
+}
+
+// applyS3Notifications adds the collected notifications to their buckets,
+// keeping any NotificationConfiguration the bucket already declares. Every
+// function subscribed to a bucket is applied to the same copy, so buckets
+// shared across functions receive one configuration per event.
+func applyS3Notifications(output *types.Template, notifications []bucketNotification) {
+	for _, pending := range notifications {
+		bucket, ok := output.Resources[pending.bucketID]
+		if !ok {
+			continue
+		}
+
+		// Copy the maps so the input template is left untouched
+		props := make(map[string]interface{}, len(bucket.Properties)+1)
+		for k, v := range bucket.Properties {
+			props[k] = v
+		}
+		notificationConfig := make(map[string]interface{})
+		if existing, ok := props["NotificationConfiguration"].(map[string]interface{}); ok {
+			for k, v := range existing {
+				notificationConfig[k] = v
+			}
+		}
+		existingConfigs, _ := notificationConfig["LambdaConfigurations"].([]interface{})
+		configs := append([]interface{}{}, existingConfigs...)
+
+		for _, config := range pending.notification.Configurations {
+			if !containsConfiguration(configs, config) {
+				configs = append(configs, config)
+			}
+		}
+
+		notificationConfig["LambdaConfigurations"] = configs
+		props["NotificationConfiguration"] = notificationConfig
+		bucket.Properties = props
+		bucket.DependsOn = appendDependsOn(bucket.DependsOn, pending.notification.PermissionID)
+		output.Resources[pending.bucketID] = bucket
+	}
+}
+
+// containsConfiguration reports whether configs already holds an equivalent
+// configuration. Values are compared by their JSON encoding so configurations
+// written in the template match generated ones.
+func containsConfiguration(configs []interface{}, config interface{}) bool {
+	want, err := json.Marshal(config)
+	if err != nil {
+		return false
+	}
+	for _, existing := range configs {
+		if got, err := json.Marshal(existing); err == nil && string(got) == string(want) {
+			return true
+		}
+	}
+	return false
+}
+
+// userPoolTrigger is a Cognito trigger resolved to its target user pool.
+type userPoolTrigger struct {
+	userPoolID string
+	trigger    sam.CognitoTrigger
+}
+
+// collectCognitoTriggers resolves the user pools targeted by the function's
+// Cognito events. Each user pool must be an AWS::Cognito::UserPool referenced
+// from the template, and each trigger may only be set once per pool, whether
+// in its LambdaConfig, by another event or by another function.
+func (s *transformState) collectCognitoTriggers(logicalID string, fn *sam.Function, template *types.Template) ([]userPoolTrigger, error) {
+	var result []userPoolTrigger
+	for _, trigger := range sam.CognitoTriggers(logicalID, fn) {
+		userPool, _ := trigger.UserPool.(map[string]interface{})
+		ref, hasRef := userPool["Ref"]
+		userPoolID, ok := ref.(string)
+		if hasRef && !ok {
+			return nil, &samerrors.InvalidEventException{
+				ResourceID: logicalID,
+				EventID:    trigger.EventName,
+				Message:    "Ref in UserPool is not a string",
+			}
+		}
+		res, exists := template.Resources[userPoolID]
+		if !exists || res.Type != "AWS::Cognito::UserPool" {
+			return nil, &samerrors.InvalidEventException{
+				ResourceID: logicalID,
+				EventID:    trigger.EventName,
+				Message:    "Cognito events must reference a Cognito user pool in the same template",
+			}
+		}
+
+		lambdaConfig, hasConfig := res.Properties["LambdaConfig"]
+		existing, ok := lambdaConfig.(map[string]interface{})
+		if hasConfig && !ok {
+			return nil, &samerrors.InvalidResourceException{
+				ResourceID: userPoolID,
+				Message:    "Property 'LambdaConfig' should be a map.",
+			}
+		}
+
+		for _, name := range trigger.Triggers {
+			_, defined := existing[name]
+			if defined || s.cognitoTriggerDefined(userPoolID, name, result) {
+				return nil, &samerrors.InvalidEventException{
+					ResourceID: logicalID,
+					EventID:    trigger.EventName,
+					Message:    fmt.Sprintf("Cognito trigger \"%s\" defined multiple times.", name),
+				}
+			}
+		}
+		result = append(result, userPoolTrigger{userPoolID: userPoolID, trigger: trigger})
+	}
+	return result, nil
+}
+
+// cognitoTriggerDefined reports whether name is already set on the user pool
+// by a collected trigger or by one of pending.
+func (s *transformState) cognitoTriggerDefined(userPoolID, name string, pending []userPoolTrigger) bool {
+	for _, triggers := range [][]userPoolTrigger{s.cognitoTriggers, pending} {
+		for _, collected := range triggers {
+			if collected.userPoolID != userPoolID {
+				continue
+			}
+			for _, existing := range collected.trigger.Triggers {
+				if existing == name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// applyCognitoTriggers adds the collected triggers to their user pools'
+// LambdaConfig, keeping any triggers the pool already declares.
+func applyCognitoTriggers(output *types.Template, triggers []userPoolTrigger) {
+	for _, pending := range triggers {
+		userPool, ok := output.Resources[pending.userPoolID]
+		if !ok {
+			continue
+		}
+
+		// Copy the maps so the input template is left untouched
+		props := make(map[string]interface{}, len(userPool.Properties)+1)
+		for k, v := range userPool.Properties {
+			props[k] = v
+		}
+		lambdaConfig := make(map[string]interface{})
+		if existing, ok := props["LambdaConfig"].(map[string]interface{}); ok {
+			for k, v := range existing {
+				lambdaConfig[k] = v
+			}
+		}
+
+		for _, name := range pending.trigger.Triggers {
+			lambdaConfig[name] = pending.trigger.FunctionArn
+		}
+
+		props["LambdaConfig"] = lambdaConfig
+		userPool.Properties = props
+		output.Resources[pending.userPoolID] = userPool
+	}
+}
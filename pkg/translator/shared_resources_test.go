@@ -0,0 +1,312 @@
+package translator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/lex00/aws-sam-translator-go/pkg/types"
+)
+
+// eventFunction builds a function resource with a single event.
+func eventFunction(eventType string, eventProps map[string]interface{}) types.Resource {
+	return types.Resource{
+		Type: "AWS::Serverless::Function",
+		Properties: map[string]interface{}{
+			"Handler": "index.handler",
+			"Runtime": "nodejs18.x",
+			"CodeUri": "s3://bucket/key",
+			"Events": map[string]interface{}{
+				"Upload": map[string]interface{}{
+					"Type":       eventType,
+					"Properties": eventProps,
+				},
+			},
+		},
+	}
+}
+
+func TestTransformSharedS3Bucket(t *testing.T) {
+	s3Event := map[string]interface{}{
+		"Bucket": map[string]interface{}{"Ref": "Images"},
+		"Events": "s3:ObjectCreated:*",
+	}
+	existing := map[string]interface{}{
+		"Event":    "s3:ObjectRemoved:*",
+		"Function": "arn:aws:lambda:us-east-1:123456789012:function:other",
+	}
+	template := &types.Template{
+		Transform: "AWS::Serverless-2016-10-31",
+		Resources: map[string]types.Resource{
+			"FunctionOne": eventFunction("S3", s3Event),
+			"FunctionTwo": eventFunction("S3", s3Event),
+			"Images": {
+				Type: "AWS::S3::Bucket",
+				Properties: map[string]interface{}{
+					"NotificationConfiguration": map[string]interface{}{
+						"LambdaConfigurations": []interface{}{existing},
+					},
+				},
+			},
+		},
+	}
+
+	result, err := New().Transform(template)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	bucket := result.Resources["Images"]
+	notificationConfig := bucket.Properties["NotificationConfiguration"].(map[string]interface{})
+	configs := notificationConfig["LambdaConfigurations"].([]interface{})
+	if len(configs) != 3 {
+		t.Fatalf("expected 3 LambdaConfigurations (existing plus one per function), got %d: %v", len(configs), configs)
+	}
+
+	functions := make(map[string]bool)
+	for _, config := range configs[1:] {
+		fn := config.(map[string]interface{})["Function"].(map[string]interface{})
+		functions[fn["Fn::GetAtt"].([]string)[0]] = true
+	}
+	if !functions["FunctionOne"] || !functions["FunctionTwo"] {
+		t.Errorf("expected notifications for both functions, got %v", configs)
+	}
+
+	dependsOn, _ := bucket.DependsOn.([]interface{})
+	if len(dependsOn) != 2 || dependsOn[0] != "FunctionOneUploadPermission" || dependsOn[1] != "FunctionTwoUploadPermission" {
+		t.Errorf("expected bucket to depend on both permissions, got %v", bucket.DependsOn)
+	}
+
+	// The input template must not be modified
+	inputConfigs := template.Resources["Images"].Properties["NotificationConfiguration"].(map[string]interface{})["LambdaConfigurations"].([]interface{})
+	if len(inputConfigs) != 1 {
+		t.Errorf("expected input bucket to keep 1 configuration, got %d", len(inputConfigs))
+	}
+}
+
+func TestTransformS3NotificationsWithSharedTranslator(t *testing.T) {
+	// Notifications collected by concurrent calls on one Translator must only
+	// be applied to the bucket of the call that collected them
+	tr := New()
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for n := 0; n < 16; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			template := &types.Template{
+				Transform: "AWS::Serverless-2016-10-31",
+				Resources: map[string]types.Resource{
+					"MyFunction": eventFunction("S3", map[string]interface{}{
+						"Bucket": map[string]interface{}{"Ref": "Images"},
+						"Events": "s3:ObjectCreated:*",
+					}),
+					"Images": {Type: "AWS::S3::Bucket"},
+				},
+			}
+			result, err := tr.Transform(template)
+			if err != nil {
+				errs <- err
+				return
+			}
+			bucket := result.Resources["Images"]
+			configs := bucket.Properties["NotificationConfiguration"].(map[string]interface{})["LambdaConfigurations"].([]interface{})
+			if len(configs) != 1 {
+				errs <- fmt.Errorf("expected 1 LambdaConfiguration, got %v", configs)
+			}
+			if dependsOn, _ := bucket.DependsOn.([]interface{}); len(dependsOn) != 1 {
+				errs <- fmt.Errorf("expected bucket to depend on one permission, got %v", bucket.DependsOn)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestTransformS3EventBucketNotInTemplate(t *testing.T) {
+	template := &types.Template{
+		Transform: "AWS::Serverless-2016-10-31",
+		Resources: map[string]types.Resource{
+			"MyFunction": eventFunction("S3", map[string]interface{}{
+				"Bucket": "my-bucket",
+				"Events": "s3:ObjectCreated:*",
+			}),
+		},
+	}
+
+	_, err := New().Transform(template)
+	if err == nil {
+		t.Fatal("expected error for S3 event bucket outside the template")
+	}
+}
+
+func TestTransformSharedSNSTopic(t *testing.T) {
+	snsEvent := map[string]interface{}{
+		"Topic": map[string]interface{}{"Ref": "Notifications"},
+	}
+	template := &types.Template{
+		Transform: "AWS::Serverless-2016-10-31",
+		Resources: map[string]types.Resource{
+			"FunctionOne":   eventFunction("SNS", snsEvent),
+			"FunctionTwo":   eventFunction("SNS", snsEvent),
+			"Notifications": {Type: "AWS::SNS::Topic"},
+		},
+	}
+
+	result, err := New().Transform(template)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	for _, id := range []string{"FunctionOneUploadSubscription", "FunctionTwoUploadSubscription"} {
+		subscription, ok := result.Resources[id]
+		if !ok {
+			t.Errorf("expected subscription %s", id)
+			continue
+		}
+		topicArn, _ := subscription.Properties["TopicArn"].(map[string]interface{})
+		if topicArn["Ref"] != "Notifications" {
+			t.Errorf("expected %s to subscribe to Notifications, got %v", id, subscription.Properties["TopicArn"])
+		}
+	}
+	if _, ok := result.Resources["Notifications"].Properties["Subscription"]; ok {
+		t.Error("expected the shared topic to be left unmodified")
+	}
+}
+
+func TestTransformCognitoTriggers(t *testing.T) {
+	template := &types.Template{
+		Transform: "AWS::Serverless-2016-10-31",
+		Resources: map[string]types.Resource{
+			"SignUpFunction": eventFunction("Cognito", map[string]interface{}{
+				"UserPool": map[string]interface{}{"Ref": "UserPool"},
+				"Trigger":  []interface{}{"PreSignUp", "PostConfirmation"},
+			}),
+			"UserPool": {
+				Type: "AWS::Cognito::UserPool",
+				Properties: map[string]interface{}{
+					"LambdaConfig": map[string]interface{}{
+						"CustomMessage": "arn:aws:lambda:us-east-1:123456789012:function:other",
+					},
+				},
+			},
+		},
+	}
+
+	result, err := New().Transform(template)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	functionArn := map[string]interface{}{"Fn::GetAtt": []string{"SignUpFunction", "Arn"}}
+	expected := map[string]interface{}{
+		"CustomMessage":    "arn:aws:lambda:us-east-1:123456789012:function:other",
+		"PreSignUp":        functionArn,
+		"PostConfirmation": functionArn,
+	}
+	lambdaConfig := result.Resources["UserPool"].Properties["LambdaConfig"]
+	if !reflect.DeepEqual(lambdaConfig, expected) {
+		t.Errorf("expected LambdaConfig %v, got %v", expected, lambdaConfig)
+	}
+	if _, ok := result.Resources["SignUpFunctionCognitoPermission"]; !ok {
+		t.Error("expected a single SignUpFunctionCognitoPermission")
+	}
+
+	// The input template is left untouched
+	if _, ok := template.Resources["UserPool"].Properties["LambdaConfig"].(map[string]interface{})["PreSignUp"]; ok {
+		t.Error("expected input LambdaConfig to be unchanged")
+	}
+}
+
+func TestTransformCognitoTriggersWithSharedTranslator(t *testing.T) {
+	// Triggers collected by concurrent calls on one Translator must not be
+	// seen by the other calls as already defined
+	tr := New()
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for n := 0; n < 16; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			template := &types.Template{
+				Transform: "AWS::Serverless-2016-10-31",
+				Resources: map[string]types.Resource{
+					"SignUpFunction": eventFunction("Cognito", map[string]interface{}{
+						"UserPool": map[string]interface{}{"Ref": "UserPool"},
+						"Trigger":  "PreSignUp",
+					}),
+					"UserPool": {Type: "AWS::Cognito::UserPool"},
+				},
+			}
+			result, err := tr.Transform(template)
+			if err != nil {
+				errs <- err
+				return
+			}
+			lambdaConfig := result.Resources["UserPool"].Properties["LambdaConfig"].(map[string]interface{})
+			if len(lambdaConfig) != 1 {
+				errs <- fmt.Errorf("expected only PreSignUp in LambdaConfig, got %v", lambdaConfig)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestTransformCognitoTriggerErrors(t *testing.T) {
+	userPool := map[string]interface{}{"Ref": "UserPool"}
+	tests := []struct {
+		name      string
+		resources map[string]types.Resource
+		want      string
+	}{
+		{
+			name: "duplicate across functions",
+			resources: map[string]types.Resource{
+				"FunctionOne": eventFunction("Cognito", map[string]interface{}{"UserPool": userPool, "Trigger": "PreSignUp"}),
+				"FunctionTwo": eventFunction("Cognito", map[string]interface{}{"UserPool": userPool, "Trigger": "PreSignUp"}),
+				"UserPool":    {Type: "AWS::Cognito::UserPool"},
+			},
+			want: `Cognito trigger "PreSignUp" defined multiple times.`,
+		},
+		{
+			name: "user pool not in template",
+			resources: map[string]types.Resource{
+				"MyFunction": eventFunction("Cognito", map[string]interface{}{"UserPool": userPool, "Trigger": "PreSignUp"}),
+			},
+			want: "must reference a Cognito user pool in the same template",
+		},
+		{
+			name: "LambdaConfig not a map",
+			resources: map[string]types.Resource{
+				"MyFunction": eventFunction("Cognito", map[string]interface{}{"UserPool": userPool, "Trigger": "PreSignUp"}),
+				"UserPool": {
+					Type:       "AWS::Cognito::UserPool",
+					Properties: map[string]interface{}{"LambdaConfig": []interface{}{"PreSignUp"}},
+				},
+			},
+			want: "Property 'LambdaConfig' should be a map.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := &types.Template{
+				Transform: "AWS::Serverless-2016-10-31",
+				Resources: tt.resources,
+			}
+			_, err := New().Transform(template)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -37,15 +37,11 @@ func (t *Translator) parseFunction(props map[string]interface{}) (*sam.Function,
 	if v, ok := props["Description"].(string); ok {
 		fn.Description = v
 	}
-	if v, ok := props["MemorySize"].(int); ok {
-		fn.MemorySize = v
-	} else if v, ok := props["MemorySize"].(float64); ok {
-		fn.MemorySize = int(v)
+	if v, ok := props["MemorySize"]; ok {
+		fn.MemorySize = integerOrIntrinsic(v)
 	}
-	if v, ok := props["Timeout"].(int); ok {
-		fn.Timeout = v
-	} else if v, ok := props["Timeout"].(float64); ok {
-		fn.Timeout = int(v)
+	if v, ok := props["Timeout"]; ok {
+		fn.Timeout = integerOrIntrinsic(v)
 	}
 	if v, ok := props["Role"]; ok {
 		fn.Role = v
@@ -86,14 +82,11 @@ func (t *Translator) parseFunction(props map[string]interface{}) (*sam.Function,
 	if v, ok := props["ProvisionedConcurrencyConfig"].(map[string]interface{}); ok {
 		fn.ProvisionedConcurrencyConfig = v
 	}
+	if v, ok := props["ProvisionedConcurrencyAutoScaling"].(map[string]interface{}); ok {
+		fn.ProvisionedConcurrencyAutoScaling = v
+	}
 	if v, ok := props["ReservedConcurrentExecutions"]; ok {
-		switch val := v.(type) {
-		case int:
-			fn.ReservedConcurrentExecutions = &val
-		case float64:
-			intVal := int(val)
-			fn.ReservedConcurrentExecutions = &intVal
-		}
+		fn.ReservedConcurrentExecutions = integerOrIntrinsic(v)
 	}
 	if v, ok := props["Tracing"].(string); ok {
 		fn.Tracing = v
@@ -125,6 +118,9 @@ func (t *Translator) parseFunction(props map[string]interface{}) (*sam.Function,
 	if v, ok := props["PermissionsBoundary"]; ok {
 		fn.PermissionsBoundary = v
 	}
+	if v, ok := props["RolePath"].(string); ok {
+		fn.RolePath = v
+	}
 	if v, ok := props["FunctionUrlConfig"].(map[string]interface{}); ok {
 		fn.FunctionUrlConfig = v
 	}
@@ -231,8 +227,12 @@ func (t *Translator) parseStateMachine(props map[string]interface{}) (*sam.State
 		return nil, fmt.Errorf("state machine properties cannot be nil")
 	}
 
-	if v, ok := props["Definition"].(map[string]interface{}); ok {
-		sm.Definition = v
+	if v, ok := props["Definition"]; ok {
+		definition, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Property 'Definition' should be a map.")
+		}
+		sm.Definition = definition
 	}
 	if v, ok := props["DefinitionUri"]; ok {
 		sm.DefinitionUri = v
@@ -435,6 +435,8 @@ func (t *Translator) parseMethodSettings(arr []interface{}) []sam.MethodSettingC
 			}
 			if v, ok := m["ThrottlingRateLimit"].(float64); ok {
 				cfg.ThrottlingRateLimit = v
+			} else if v, ok := m["ThrottlingRateLimit"].(int); ok {
+				cfg.ThrottlingRateLimit = float64(v)
 			}
 			result = append(result, cfg)
 		}
@@ -625,6 +627,9 @@ func (t *Translator) parseHttpApiAccessLogSettings(m map[string]interface{}) *sa
 	if v, ok := m["Format"]; ok {
 		settings.Format = v
 	}
+	if v, ok := m["CreateLogGroup"].(bool); ok {
+		settings.CreateLogGroup = v
+	}
 	return settings
 }
 
@@ -1049,3 +1054,12 @@ func convertToMapSlice(arr []interface{}) []map[string]interface{} {
 	}
 	return result
 }
+
+// integerOrIntrinsic returns numeric values as int and any other value, such
+// as an intrinsic function, unchanged.
+func integerOrIntrinsic(v interface{}) interface{} {
+	if f, ok := v.(float64); ok {
+		return int(f)
+	}
+	return v
+}
@@ -4,9 +4,12 @@ package translator
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"sort"
 	"strings"
+	"sync"
 
+	samerrors "github.com/lex00/aws-sam-translator-go/pkg/errors"
 	"github.com/lex00/aws-sam-translator-go/pkg/parser"
 	"github.com/lex00/aws-sam-translator-go/pkg/plugins"
 	"github.com/lex00/aws-sam-translator-go/pkg/sam"
@@ -20,6 +23,30 @@ const Version = "1.0.0"
 // SAMTransform is the SAM transform identifier.
 const SAMTransform = "AWS::Serverless-2016-10-31"
 
+// TemplateFormatVersion is the only CloudFormation template format version.
+const TemplateFormatVersion = "2010-09-09"
+
+// TransformHeaderMode controls how templates that do not declare the SAM transform are handled.
+type TransformHeaderMode int
+
+const (
+	// TransformHeaderRequire rejects templates that do not declare the SAM
+	// transform. It is the default.
+	TransformHeaderRequire TransformHeaderMode = iota
+
+	// TransformHeaderPassThrough returns templates that do not declare the SAM
+	// transform unchanged.
+	TransformHeaderPassThrough
+
+	// TransformHeaderIgnore opts out of the check and translates the template
+	// whether or not it declares the SAM transform, matching the Python translator.
+	TransformHeaderIgnore
+)
+
+// FeatureValidateSnapStartRuntime is the FeatureToggles key that enables
+// rejecting SnapStart on runtimes that do not support it.
+const FeatureValidateSnapStartRuntime = "ValidateSnapStartRuntime"
+
 // Options configures the translator behavior.
 type Options struct {
 	// Region is the AWS region for resource ARN generation.
@@ -39,6 +66,69 @@ type Options struct {
 
 	// FeatureToggles controls optional transformation features.
 	FeatureToggles map[string]bool
+
+	// DefaultRolePath is the IAM path applied to generated function roles
+	// that do not set RolePath.
+	DefaultRolePath string
+
+	// TransformHeader controls handling of templates without the SAM Transform
+	// header. By default they are rejected.
+	TransformHeader TransformHeaderMode
+
+	// AllowLocalCodeUri emits a placeholder code location for functions whose
+	// CodeUri is a local path, and accepts local ImageUri names, instead of
+	// failing the transform.
+	AllowLocalCodeUri bool
+
+	// UseInlineBasicExecutionPolicy replaces the AWSLambdaBasicExecutionRole
+	// managed policy on generated function roles with an equivalent inline
+	// logging policy scoped to the function's log group. For a function
+	// without FunctionName, the policy references the function and is
+	// generated as a separate AWS::IAM::Policy attached to the role.
+	UseInlineBasicExecutionPolicy bool
+
+	// StrictEvents fails the transform when a function event has an unknown
+	// Type, such as a misspelled Schedule, instead of silently skipping it.
+	StrictEvents bool
+
+	// OmitCreatedByTag leaves out the lambda:createdBy: SAM tag that is added
+	// to every function and its generated role.
+	OmitCreatedByTag bool
+
+	// ValidateRequestParameters attaches the params-only request validator to
+	// generated Api routes whose events declare Required RequestParameters
+	// and no validated RequestModel. SAM does not do this.
+	ValidateRequestParameters bool
+
+	// CanonicalKeyOrder makes TransformBytes emit the top-level template
+	// sections and each resource's attributes in a fixed canonical order,
+	// with resources sorted by logical ID, for readable diffs.
+	CanonicalKeyOrder bool
+
+	// DefaultTemplateFormatVersion sets AWSTemplateFormatVersion to
+	// TemplateFormatVersion on output templates that do not declare one. The
+	// input's version is always preserved.
+	DefaultTemplateFormatVersion bool
+
+	// UsePseudoParameters controls how generated ARNs refer to the partition,
+	// region and account. When nil or true, they use the AWS::Partition,
+	// AWS::Region and AWS::AccountId pseudo parameters, as SAM does. When
+	// false, those are resolved to the literal Partition, Region and
+	// AccountID options in the values the transform generates, including
+	// role policies, permission SourceArns and connector statements. Values
+	// copied from the template are left unchanged. Region and AccountID must
+	// then be set explicitly.
+	UsePseudoParameters *bool
+
+	// ConnectorProfilesPath is a JSON file of additional connector profiles in
+	// the sam.ConnectorProfilesFile format. Its profiles are merged with the
+	// built-in profiles, replacing them for the same source and destination.
+	// The file is read once, when the translator is created.
+	ConnectorProfilesPath string
+
+	// DeprecatedRuntimes lists the Lambda runtimes that produce a deprecation
+	// warning. When nil, DefaultDeprecatedRuntimes is used.
+	DeprecatedRuntimes []string
 }
 
 // Translator transforms SAM templates to CloudFormation.
@@ -57,6 +147,39 @@ type Translator struct {
 	applicationTransformer  *sam.ApplicationTransformer
 	graphQLApiTransformer   *sam.GraphQLApiTransformer
 	connectorTransformer    *sam.ConnectorTransformer
+
+	// warningsMu guards warnings and diagnostics, the warnings of the most
+	// recently completed Transform as returned by Warnings and Diagnostics
+	warningsMu  sync.Mutex
+	warnings    []string
+	diagnostics []Diagnostic
+
+	// deprecatedRuntimes is the set of runtimes that trigger a warning
+	deprecatedRuntimes map[string]bool
+
+	// cache holds recent TransformBytes results when enabled by WithCache
+	cache *transformCache
+
+	// optionsErr is the error from applying options in NewWithOptions,
+	// returned by every Transform
+	optionsErr error
+}
+
+// transformState holds what is collected during a single transform. It is
+// created per call, so a Translator can be shared between goroutines.
+type transformState struct {
+	// warnings as messages for Warnings, and the same warnings as
+	// diagnostics for Validate
+	warnings    []string
+	diagnostics []Diagnostic
+
+	// s3Notifications collected from function S3 events, applied to their
+	// buckets once all resources are transformed
+	s3Notifications []bucketNotification
+
+	// cognitoTriggers collected from function Cognito events, applied to
+	// their user pools once all resources are transformed
+	cognitoTriggers []userPoolTrigger
 }
 
 // Schema returns the CloudFormation schema.
@@ -76,6 +199,12 @@ func New() *Translator {
 
 // NewWithOptions creates a new Translator instance with the specified options.
 func NewWithOptions(opts Options) *Translator {
+	// Literal pseudo parameters must not fall back to the placeholder defaults
+	var optionsErr error
+	if !opts.usePseudoParameters() && (opts.Region == "" || opts.AccountID == "") {
+		optionsErr = fmt.Errorf("UsePseudoParameters false requires both Region and AccountID")
+	}
+
 	// Apply defaults for empty values
 	if opts.Region == "" {
 		opts.Region = "us-east-1"
@@ -104,12 +233,42 @@ func NewWithOptions(opts Options) *Translator {
 		connectorTransformer:    sam.NewConnectorTransformer(),
 	}
 
+	t.functionTransformer.ValidateSnapStartRuntime = opts.FeatureToggles[FeatureValidateSnapStartRuntime]
+	t.functionTransformer.DefaultRolePath = opts.DefaultRolePath
+	t.functionTransformer.AllowLocalCodeUri = opts.AllowLocalCodeUri
+	t.functionTransformer.UseInlineBasicExecutionPolicy = opts.UseInlineBasicExecutionPolicy
+	t.functionTransformer.StrictEvents = opts.StrictEvents
+	t.functionTransformer.OmitCreatedByTag = opts.OmitCreatedByTag
+
+	t.optionsErr = optionsErr
+	if opts.ConnectorProfilesPath != "" && t.optionsErr == nil {
+		t.optionsErr = t.loadConnectorProfiles(opts.ConnectorProfilesPath)
+	}
+
+	deprecatedRuntimes := opts.DeprecatedRuntimes
+	if deprecatedRuntimes == nil {
+		deprecatedRuntimes = DefaultDeprecatedRuntimes
+	}
+	t.deprecatedRuntimes = newPropertySet(deprecatedRuntimes...)
+
 	// Register default plugins
 	t.registerDefaultPlugins()
 
 	return t
 }
 
+// loadConnectorProfiles merges the connector profiles in the JSON file at path.
+func (t *Translator) loadConnectorProfiles(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read connector profiles: %w", err)
+	}
+	if err := t.connectorTransformer.LoadProfiles(data); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
 // registerDefaultPlugins registers the built-in SAM plugins.
 func (t *Translator) registerDefaultPlugins() {
 	t.pluginRegistry.Register(plugins.NewGlobalsPlugin())
@@ -120,7 +279,9 @@ func (t *Translator) registerDefaultPlugins() {
 	}
 	t.pluginRegistry.Register(plugins.NewImplicitRestApiPlugin())
 	t.pluginRegistry.Register(plugins.NewImplicitHttpApiPlugin())
-	t.pluginRegistry.Register(plugins.NewDefaultDefinitionBodyPlugin())
+	definitionBodyPlugin := plugins.NewDefaultDefinitionBodyPlugin()
+	definitionBodyPlugin.ValidateRequestParameters = t.options.ValidateRequestParameters
+	t.pluginRegistry.Register(definitionBodyPlugin)
 }
 
 // RegisterPlugin registers an additional plugin.
@@ -130,6 +291,31 @@ func (t *Translator) RegisterPlugin(p plugins.Plugin) {
 
 // Transform converts a SAM template to CloudFormation.
 func (t *Translator) Transform(template *types.Template) (*types.Template, error) {
+	output, state, err := t.transform(template)
+	t.setWarnings(state)
+	return output, err
+}
+
+// transform converts a SAM template to CloudFormation, returning the state
+// collected along the way so that concurrent calls do not share it.
+func (t *Translator) transform(template *types.Template) (*types.Template, *transformState, error) {
+	state := &transformState{}
+
+	if t.optionsErr != nil {
+		return nil, state, t.optionsErr
+	}
+
+	if !HasSAMTransform(template.Transform) {
+		switch t.options.TransformHeader {
+		case TransformHeaderRequire:
+			return nil, state, &samerrors.InvalidDocumentException{
+				Message: fmt.Sprintf("template does not declare 'Transform: %s'", SAMTransform),
+			}
+		case TransformHeaderPassThrough:
+			return template, state, nil
+		}
+	}
+
 	// Create the output template
 	output := &types.Template{
 		AWSTemplateFormatVersion: template.AWSTemplateFormatVersion,
@@ -141,6 +327,10 @@ func (t *Translator) Transform(template *types.Template) (*types.Template, error
 		Resources:                make(map[string]types.Resource),
 	}
 
+	if output.AWSTemplateFormatVersion == "" && t.options.DefaultTemplateFormatVersion {
+		output.AWSTemplateFormatVersion = TemplateFormatVersion
+	}
+
 	// Handle metadata passthrough
 	if t.options.PassThroughMetadata && template.Metadata != nil {
 		output.Metadata = template.Metadata
@@ -149,9 +339,16 @@ func (t *Translator) Transform(template *types.Template) (*types.Template, error
 	// Handle Transform - remove SAM transform, preserve others
 	output.Transform = t.filterTransform(template.Transform)
 
+	// Plugins such as the default definition body add generated values to SAM
+	// resources, so authored values are recorded before they run
+	var authored map[uintptr]bool
+	if !t.options.usePseudoParameters() {
+		authored = templateValues(template)
+	}
+
 	// Run BeforeTransform plugins
 	if err := t.pluginRegistry.RunBeforeTransform(template); err != nil {
-		return nil, fmt.Errorf("BeforeTransform plugin error: %w", err)
+		return nil, state, fmt.Errorf("BeforeTransform plugin error: %w", err)
 	}
 
 	// Create transform context
@@ -168,6 +365,15 @@ func (t *Translator) Transform(template *types.Template) (*types.Template, error
 	// Track all errors for aggregation
 	var errs []error
 
+	// Track which SAM resource produced each output logical ID; resources
+	// defined directly in the template are recorded with an empty owner
+	owners := make(map[string]string, len(template.Resources))
+	for id, res := range template.Resources {
+		if !isSAMResource(res.Type) {
+			owners[id] = ""
+		}
+	}
+
 	// Transform each resource in order
 	for _, entry := range orderedResources {
 		logicalID := entry.logicalID
@@ -175,9 +381,14 @@ func (t *Translator) Transform(template *types.Template) (*types.Template, error
 
 		if isSAMResource(resource.Type) {
 			// Transform SAM resource
-			newResources, err := t.transformSAMResource(logicalID, resource, ctx, template)
+			newResources, err := t.transformSAMResource(state, logicalID, resource, ctx, template)
 			if err != nil {
-				errs = append(errs, fmt.Errorf("resource '%s': %w", logicalID, err))
+				errs = append(errs, &resourceError{logicalID: logicalID, err: err})
+				continue
+			}
+
+			if err := checkLogicalIDCollisions(logicalID, newResources, owners); err != nil {
+				errs = append(errs, &resourceError{logicalID: logicalID, err: err})
 				continue
 			}
 
@@ -191,43 +402,130 @@ func (t *Translator) Transform(template *types.Template) (*types.Template, error
 		}
 	}
 
+	// Embedded connectors may reference any resource, so they are transformed
+	// once every resource has been
+	embedded := sam.ExtractEmbeddedConnectors(connectorTemplateResources(template))
+	sourceIDs := make([]string, 0, len(embedded))
+	for sourceID := range embedded {
+		sourceIDs = append(sourceIDs, sourceID)
+	}
+	sort.Strings(sourceIDs)
+
+	for _, sourceID := range sourceIDs {
+		newResources, err := t.transformEmbeddedConnectors(sourceID, embedded[sourceID], template)
+		if err != nil {
+			errs = append(errs, &resourceError{logicalID: sourceID, err: err})
+			continue
+		}
+
+		if err := checkLogicalIDCollisions(sourceID, newResources, owners); err != nil {
+			errs = append(errs, &resourceError{logicalID: sourceID, err: err})
+			continue
+		}
+
+		for id, res := range newResources {
+			output.Resources[id] = res
+		}
+	}
+
+	// Apply event source changes to shared resources such as S3 buckets and user pools
+	applyS3Notifications(output, state.s3Notifications)
+	applyCognitoTriggers(output, state.cognitoTriggers)
+
+	if !t.options.usePseudoParameters() {
+		t.resolvePseudoParameters(output, owners, authored)
+	}
+
 	// Run AfterTransform plugins
 	if err := t.pluginRegistry.RunAfterTransform(output); err != nil {
-		return nil, fmt.Errorf("AfterTransform plugin error: %w", err)
+		return nil, state, fmt.Errorf("AfterTransform plugin error: %w", err)
 	}
 
 	// Return aggregated errors if any
 	if len(errs) > 0 {
-		return nil, &TransformError{Errors: errs}
+		return nil, state, &TransformError{Errors: errs}
 	}
 
-	return output, nil
+	return output, state, nil
 }
 
 // TransformBytes parses a YAML/JSON template and transforms it to CloudFormation JSON.
 func (t *Translator) TransformBytes(input []byte) ([]byte, error) {
+	var key string
+	if t.cache != nil {
+		var err error
+		key, err = cacheKey(input, t.options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash options: %w", err)
+		}
+		if output, cached, ok := t.cache.get(key); ok {
+			t.setWarnings(cached)
+			return output, nil
+		}
+	}
+
 	// Parse the input template
-	p := parser.New()
-	template, err := p.Parse(input)
+	raw, err := parser.New().ParseRaw(input)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse template: %w", err)
 	}
 
-	// Transform
-	result, err := t.Transform(template)
+	result, state, err := t.transformRaw(raw)
+	t.setWarnings(state)
 	if err != nil {
 		return nil, err
 	}
 
 	// Marshal to JSON
-	output, err := json.MarshalIndent(result, "", "  ")
+	var output []byte
+	if t.options.CanonicalKeyOrder {
+		output, err = marshalCanonical(result)
+	} else {
+		output, err = json.MarshalIndent(result, "", "  ")
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal output: %w", err)
 	}
 
+	if t.cache != nil {
+		t.cache.put(key, output, state)
+	}
+
 	return output, nil
 }
 
+// TransformMap transforms an already-decoded SAM template, such as one produced
+// by the caller's own YAML parser, and returns the CloudFormation template as a map.
+func (t *Translator) TransformMap(template map[string]interface{}) (map[string]interface{}, error) {
+	result, state, err := t.transformRaw(template)
+	t.setWarnings(state)
+	if err != nil {
+		return nil, err
+	}
+
+	// Round-trip through JSON so the result uses plain maps and slices
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %w", err)
+	}
+	var output map[string]interface{}
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, fmt.Errorf("failed to decode output: %w", err)
+	}
+
+	return output, nil
+}
+
+// transformRaw converts a raw template map to a Template and transforms it.
+func (t *Translator) transformRaw(raw map[string]interface{}) (*types.Template, *transformState, error) {
+	template, err := parser.New().ParseMap(raw)
+	if err != nil {
+		return nil, &transformState{}, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	return t.transform(template)
+}
+
 // resourceEntry holds a resource with its logical ID for sorting.
 type resourceEntry struct {
 	logicalID string
@@ -288,26 +586,32 @@ func getResourceOrder() []string {
 	}
 }
 
+// SupportedResourceTypes returns the SAM resource types the translator
+// transforms, in processing order.
+func SupportedResourceTypes() []string {
+	return getResourceOrder()
+}
+
 // isSAMResource checks if a resource type is a SAM resource.
 func isSAMResource(resourceType string) bool {
 	return strings.HasPrefix(resourceType, "AWS::Serverless::")
 }
 
 // transformSAMResource transforms a single SAM resource.
-func (t *Translator) transformSAMResource(logicalID string, resource types.Resource, ctx *sam.TransformContext, template *types.Template) (map[string]types.Resource, error) {
+func (t *Translator) transformSAMResource(state *transformState, logicalID string, resource types.Resource, ctx *sam.TransformContext, template *types.Template) (map[string]types.Resource, error) {
 	switch resource.Type {
 	case "AWS::Serverless::Function":
-		return t.transformFunction(logicalID, resource, ctx)
+		return t.transformFunction(state, logicalID, resource, ctx, template)
 	case "AWS::Serverless::SimpleTable":
 		return t.transformSimpleTable(logicalID, resource)
 	case "AWS::Serverless::LayerVersion":
-		return t.transformLayerVersion(logicalID, resource)
+		return t.transformLayerVersion(state, logicalID, resource)
 	case "AWS::Serverless::StateMachine":
 		return t.transformStateMachine(logicalID, resource, ctx)
 	case "AWS::Serverless::Api":
-		return t.transformApi(logicalID, resource, ctx)
+		return t.transformApi(state, logicalID, resource, ctx)
 	case "AWS::Serverless::HttpApi":
-		return t.transformHttpApi(logicalID, resource, ctx)
+		return t.transformHttpApi(state, logicalID, resource, ctx)
 	case "AWS::Serverless::Application":
 		return t.transformApplication(logicalID, resource, ctx)
 	case "AWS::Serverless::GraphQLApi":
@@ -320,25 +624,171 @@ func (t *Translator) transformSAMResource(logicalID string, resource types.Resou
 }
 
 // transformFunction transforms an AWS::Serverless::Function resource.
-func (t *Translator) transformFunction(logicalID string, resource types.Resource, ctx *sam.TransformContext) (map[string]types.Resource, error) {
+func (t *Translator) transformFunction(state *transformState, logicalID string, resource types.Resource, ctx *sam.TransformContext, template *types.Template) (map[string]types.Resource, error) {
 	fn, err := t.parseFunction(resource.Properties)
 	if err != nil {
 		return nil, err
 	}
+	state.warnUnknownProperties(logicalID, resource.Properties, functionProperties)
+	t.warnDeprecatedRuntime(state, logicalID, fn.Runtime)
 
 	// Copy resource-level properties
 	fn.Condition = resource.Condition
 	fn.DependsOn = resource.DependsOn
 	fn.Metadata = resource.Metadata
 
+	// EFS mounts fail until the mount targets exist
+	if len(fn.FileSystemConfigs) > 0 && template != nil {
+		fn.DependsOn = appendDependsOn(fn.DependsOn, efsMountTargetIDs(template)...)
+	}
+
+	var notifications []bucketNotification
+	if template != nil {
+		if err := validateSNSTopicRefs(logicalID, fn, template); err != nil {
+			return nil, err
+		}
+		if notifications, err = collectS3Notifications(logicalID, fn, template); err != nil {
+			return nil, err
+		}
+	}
+
 	rawResources, err := t.functionTransformer.Transform(logicalID, fn, ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	// Triggers are checked against earlier functions, so they are collected
+	// once the function's events have been validated
+	if template != nil {
+		triggers, err := state.collectCognitoTriggers(logicalID, fn, template)
+		if err != nil {
+			return nil, err
+		}
+		state.cognitoTriggers = append(state.cognitoTriggers, triggers...)
+	}
+	state.s3Notifications = append(state.s3Notifications, notifications...)
+
 	return t.convertRawResources(rawResources), nil
 }
 
+// efsMountTargetIDs returns the sorted logical IDs of all AWS::EFS::MountTarget resources in the template.
+func efsMountTargetIDs(template *types.Template) []string {
+	var ids []string
+	for id, res := range template.Resources {
+		if res.Type == "AWS::EFS::MountTarget" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// validateSNSTopicRefs rejects SNS events whose Topic is a Ref to a logical ID
+// that is neither a resource nor a parameter in the template. SAM does not create
+// topics implicitly, so the Ref would otherwise fail at deployment.
+func validateSNSTopicRefs(logicalID string, fn *sam.Function, template *types.Template) error {
+	eventNames := make([]string, 0, len(fn.Events))
+	for eventName := range fn.Events {
+		eventNames = append(eventNames, eventName)
+	}
+	sort.Strings(eventNames)
+
+	for _, eventName := range eventNames {
+		event, ok := fn.Events[eventName].(map[string]interface{})
+		if !ok || event["Type"] != "SNS" {
+			continue
+		}
+		props, _ := event["Properties"].(map[string]interface{})
+		topic, _ := props["Topic"].(map[string]interface{})
+		ref, ok := topic["Ref"].(string)
+		if !ok || strings.HasPrefix(ref, "AWS::") {
+			continue
+		}
+		if _, exists := template.Resources[ref]; exists {
+			continue
+		}
+		if _, exists := template.Parameters[ref]; exists {
+			continue
+		}
+		return &samerrors.InvalidEventException{
+			ResourceID: logicalID,
+			EventID:    eventName,
+			Message:    fmt.Sprintf("Topic references '%s', which is not defined in the template; declare an AWS::SNS::Topic named '%s' or pass the topic ARN", ref, ref),
+		}
+	}
+	return nil
+}
+
+// restApiOnlyProperties lists AWS::Serverless::Api properties that HTTP APIs
+// do not support, with a hint for the HttpApi alternative where there is one.
+var restApiOnlyProperties = map[string]string{
+	"BinaryMediaTypes":       "HTTP APIs pass binary payloads to Lambda base64-encoded and need no binary media type configuration",
+	"MinimumCompressionSize": "",
+	"MethodSettings":         "use DefaultRouteSettings or RouteSettings instead",
+	"GatewayResponses":       "",
+	"Models":                 "",
+	"CacheClusterEnabled":    "",
+	"CacheClusterSize":       "",
+}
+
+// validateHttpApiProperties rejects REST API-only properties on an
+// AWS::Serverless::HttpApi, which would otherwise be silently ignored.
+func validateHttpApiProperties(logicalID string, props map[string]interface{}) error {
+	var names []string
+	for name := range props {
+		if _, ok := restApiOnlyProperties[name]; ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+
+	message := fmt.Sprintf("%s is not supported on AWS::Serverless::HttpApi; it only applies to AWS::Serverless::Api", names[0])
+	if hint := restApiOnlyProperties[names[0]]; hint != "" {
+		message += "; " + hint
+	}
+	return &samerrors.InvalidResourceException{ResourceID: logicalID, Message: message}
+}
+
+// appendDependsOn adds logical IDs to an existing DependsOn value, skipping duplicates.
+func appendDependsOn(dependsOn interface{}, ids ...string) interface{} {
+	if len(ids) == 0 {
+		return dependsOn
+	}
+
+	var result []interface{}
+	seen := make(map[string]bool)
+	add := func(v interface{}) {
+		if s, ok := v.(string); ok {
+			if seen[s] {
+				return
+			}
+			seen[s] = true
+		}
+		result = append(result, v)
+	}
+
+	switch v := dependsOn.(type) {
+	case string:
+		add(v)
+	case []interface{}:
+		for _, item := range v {
+			add(item)
+		}
+	case []string:
+		for _, item := range v {
+			add(item)
+		}
+	}
+	for _, id := range ids {
+		add(id)
+	}
+
+	return result
+}
+
 // transformSimpleTable transforms an AWS::Serverless::SimpleTable resource.
 func (t *Translator) transformSimpleTable(logicalID string, resource types.Resource) (map[string]types.Resource, error) {
 	st, err := t.parseSimpleTable(resource.Properties)
@@ -365,11 +815,12 @@ func (t *Translator) transformSimpleTable(logicalID string, resource types.Resou
 }
 
 // transformLayerVersion transforms an AWS::Serverless::LayerVersion resource.
-func (t *Translator) transformLayerVersion(logicalID string, resource types.Resource) (map[string]types.Resource, error) {
+func (t *Translator) transformLayerVersion(state *transformState, logicalID string, resource types.Resource) (map[string]types.Resource, error) {
 	lv, err := t.parseLayerVersion(resource.Properties)
 	if err != nil {
 		return nil, err
 	}
+	state.warnLayerTags(logicalID, resource.Properties)
 
 	rawResources, newLogicalID, err := t.layerVersionTransformer.Transform(logicalID, lv)
 	if err != nil {
@@ -415,11 +866,12 @@ func (t *Translator) transformStateMachine(logicalID string, resource types.Reso
 }
 
 // transformApi transforms an AWS::Serverless::Api resource.
-func (t *Translator) transformApi(logicalID string, resource types.Resource, _ *sam.TransformContext) (map[string]types.Resource, error) {
+func (t *Translator) transformApi(state *transformState, logicalID string, resource types.Resource, _ *sam.TransformContext) (map[string]types.Resource, error) {
 	api, err := t.parseApi(resource.Properties)
 	if err != nil {
 		return nil, err
 	}
+	state.warnUnknownProperties(logicalID, resource.Properties, apiProperties)
 
 	rawResources, err := t.apiTransformer.Transform(logicalID, api)
 	if err != nil {
@@ -440,11 +892,16 @@ func (t *Translator) transformApi(logicalID string, resource types.Resource, _ *
 }
 
 // transformHttpApi transforms an AWS::Serverless::HttpApi resource.
-func (t *Translator) transformHttpApi(logicalID string, resource types.Resource, ctx *sam.TransformContext) (map[string]types.Resource, error) {
+func (t *Translator) transformHttpApi(state *transformState, logicalID string, resource types.Resource, ctx *sam.TransformContext) (map[string]types.Resource, error) {
+	if err := validateHttpApiProperties(logicalID, resource.Properties); err != nil {
+		return nil, err
+	}
+
 	httpApi, err := t.parseHttpApi(resource.Properties)
 	if err != nil {
 		return nil, err
 	}
+	state.warnUnknownProperties(logicalID, resource.Properties, httpApiProperties)
 
 	rawResources, err := t.httpApiTransformer.Transform(logicalID, httpApi, ctx)
 	if err != nil {
@@ -511,16 +968,7 @@ func (t *Translator) transformConnector(logicalID string, resource types.Resourc
 		return nil, err
 	}
 
-	// Convert template resources to map[string]interface{} for connector transformer
-	templateResources := make(map[string]interface{})
-	for id, res := range template.Resources {
-		templateResources[id] = map[string]interface{}{
-			"Type":       res.Type,
-			"Properties": res.Properties,
-		}
-	}
-
-	rawResources, err := t.connectorTransformer.Transform(logicalID, conn, templateResources)
+	rawResources, err := t.connectorTransformer.Transform(logicalID, conn, connectorTemplateResources(template))
 	if err != nil {
 		return nil, err
 	}
@@ -538,6 +986,34 @@ func (t *Translator) transformConnector(logicalID string, resource types.Resourc
 	return result, nil
 }
 
+// transformEmbeddedConnectors transforms the connectors embedded in the
+// resource sourceID, which is the source of each of them.
+func (t *Translator) transformEmbeddedConnectors(sourceID string, connectors map[string]sam.EmbeddedConnector, template *types.Template) (map[string]types.Resource, error) {
+	source := template.Resources[sourceID]
+	rawResources, err := t.connectorTransformer.TransformEmbedded(sourceID, source.Type, connectors, connectorTemplateResources(template))
+	if err != nil {
+		return nil, err
+	}
+	return t.convertRawResources(rawResources), nil
+}
+
+// connectorTemplateResources converts template resources to the generic form
+// used by the connector transformer, including their embedded connectors.
+func connectorTemplateResources(template *types.Template) map[string]interface{} {
+	templateResources := make(map[string]interface{}, len(template.Resources))
+	for id, res := range template.Resources {
+		resource := map[string]interface{}{
+			"Type":       res.Type,
+			"Properties": res.Properties,
+		}
+		if res.Connectors != nil {
+			resource["Connectors"] = res.Connectors
+		}
+		templateResources[id] = resource
+	}
+	return templateResources
+}
+
 // convertRawResources converts a map[string]interface{} to map[string]types.Resource.
 func (t *Translator) convertRawResources(raw map[string]interface{}) map[string]types.Resource {
 	result := make(map[string]types.Resource)
@@ -578,6 +1054,28 @@ func (t *Translator) convertRawResources(raw map[string]interface{}) map[string]
 	return result
 }
 
+// HasSAMTransform reports whether a template Transform value, either a single
+// string or a list, includes the SAM transform.
+func HasSAMTransform(transform interface{}) bool {
+	switch v := transform.(type) {
+	case string:
+		return v == SAMTransform
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok && s == SAMTransform {
+				return true
+			}
+		}
+	case []string:
+		for _, s := range v {
+			if s == SAMTransform {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // filterTransform removes the SAM transform from the Transform value.
 func (t *Translator) filterTransform(transform interface{}) interface{} {
 	if transform == nil {
@@ -590,24 +1088,75 @@ func (t *Translator) filterTransform(transform interface{}) interface{} {
 			return nil
 		}
 		return v
+	case []string:
+		items := make([]interface{}, len(v))
+		for i, s := range v {
+			items[i] = s
+		}
+		return filterTransformList(items)
 	case []interface{}:
-		var filtered []interface{}
-		for _, item := range v {
-			if s, ok := item.(string); ok && s == SAMTransform {
-				continue
-			}
-			filtered = append(filtered, item)
+		return filterTransformList(v)
+	default:
+		return transform
+	}
+}
+
+// filterTransformList removes the SAM transform from a list of macros,
+// keeping every other macro in order. A single remaining macro is returned
+// on its own.
+func filterTransformList(items []interface{}) interface{} {
+	var filtered []interface{}
+	for _, item := range items {
+		if s, ok := item.(string); ok && s == SAMTransform {
+			continue
 		}
-		if len(filtered) == 0 {
-			return nil
+		filtered = append(filtered, item)
+	}
+	if len(filtered) == 0 {
+		return nil
+	}
+	if len(filtered) == 1 {
+		return filtered[0]
+	}
+	return filtered
+}
+
+// sharedLogicalIDs lists generated resources that several SAM resources are
+// expected to emit with the same logical ID.
+var sharedLogicalIDs = map[string]bool{
+	"ServerlessDeploymentApplication": true,
+}
+
+// checkLogicalIDCollisions reports a generated logical ID that was already
+// emitted by a different source, then records logicalID as the owner of the
+// remaining IDs.
+func checkLogicalIDCollisions(logicalID string, generated map[string]types.Resource, owners map[string]string) error {
+	ids := make([]string, 0, len(generated))
+	for id := range generated {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if sharedLogicalIDs[id] {
+			continue
 		}
-		if len(filtered) == 1 {
-			return filtered[0]
+		owner, exists := owners[id]
+		if !exists || owner == logicalID {
+			continue
+		}
+		if owner == "" {
+			return fmt.Errorf("generated logical ID '%s' collides with a resource defined in the template", id)
+		}
+		return fmt.Errorf("generated logical ID '%s' collides with a resource generated by '%s'", id, owner)
+	}
+
+	for _, id := range ids {
+		if !sharedLogicalIDs[id] {
+			owners[id] = logicalID
 		}
-		return filtered
-	default:
-		return transform
 	}
+	return nil
 }
 
 // TransformError aggregates multiple transformation errors.
@@ -626,3 +1175,23 @@ func (e *TransformError) Error() string {
 	}
 	return fmt.Sprintf("multiple errors:\n  - %s", strings.Join(msgs, "\n  - "))
 }
+
+// Unwrap returns the individual resource errors so errors.Is and errors.As
+// can match any of them.
+func (e *TransformError) Unwrap() []error {
+	return e.Errors
+}
+
+// resourceError is a transform error attributed to one template resource.
+type resourceError struct {
+	logicalID string
+	err       error
+}
+
+func (e *resourceError) Error() string {
+	return fmt.Sprintf("resource '%s': %v", e.logicalID, e.err)
+}
+
+func (e *resourceError) Unwrap() error {
+	return e.err
+}
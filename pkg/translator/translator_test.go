@@ -2,10 +2,18 @@ package translator
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
 
+	samerrors "github.com/lex00/aws-sam-translator-go/pkg/errors"
 	"github.com/lex00/aws-sam-translator-go/pkg/plugins"
+	"github.com/lex00/aws-sam-translator-go/pkg/sam"
 	"github.com/lex00/aws-sam-translator-go/pkg/types"
 )
 
@@ -40,6 +48,7 @@ func TestTransformEmptyTemplate(t *testing.T) {
 	tr := New()
 	template := &types.Template{
 		AWSTemplateFormatVersion: "2010-09-09",
+		Transform:                "AWS::Serverless-2016-10-31",
 		Resources:                make(map[string]types.Resource),
 	}
 
@@ -186,6 +195,7 @@ func TestTransformPluginLifecycle(t *testing.T) {
 
 	template := &types.Template{
 		AWSTemplateFormatVersion: "2010-09-09",
+		Transform:                "AWS::Serverless-2016-10-31",
 		Resources:                make(map[string]types.Resource),
 	}
 
@@ -232,6 +242,305 @@ func TestTransformErrorAggregation(t *testing.T) {
 	}
 }
 
+func TestTransformErrorAggregationMultipleResources(t *testing.T) {
+	tr := New()
+
+	template := &types.Template{
+		AWSTemplateFormatVersion: "2010-09-09",
+		Transform:                "AWS::Serverless-2016-10-31",
+		Resources: map[string]types.Resource{
+			"BadFunction1": {
+				Type:       "AWS::Serverless::Function",
+				Properties: map[string]interface{}{},
+			},
+			"BadFunction2": {
+				Type:       "AWS::Serverless::Function",
+				Properties: map[string]interface{}{},
+			},
+			"GoodTopic": {
+				Type: "AWS::SNS::Topic",
+			},
+		},
+	}
+
+	_, err := tr.Transform(template)
+	if err == nil {
+		t.Fatal("expected error for invalid functions")
+	}
+
+	var transformErr *TransformError
+	if !errors.As(err, &transformErr) {
+		t.Fatalf("expected *TransformError, got %T", err)
+	}
+	if len(transformErr.Errors) != 2 {
+		t.Fatalf("expected 2 errors, got %d: %v", len(transformErr.Errors), transformErr.Errors)
+	}
+	for _, id := range []string{"BadFunction1", "BadFunction2"} {
+		if !strings.Contains(err.Error(), id) {
+			t.Errorf("error should mention resource %s: %v", id, err)
+		}
+	}
+}
+
+func TestTransformLogicalIDCollision(t *testing.T) {
+	tr := New()
+
+	// Foo's SQS event "Bar" generates FooBar, the same ID as the function FooBar
+	template := &types.Template{
+		AWSTemplateFormatVersion: "2010-09-09",
+		Transform:                "AWS::Serverless-2016-10-31",
+		Resources: map[string]types.Resource{
+			"Foo": {
+				Type: "AWS::Serverless::Function",
+				Properties: map[string]interface{}{
+					"Handler": "index.handler",
+					"Runtime": "nodejs18.x",
+					"CodeUri": "s3://bucket/key",
+					"Events": map[string]interface{}{
+						"Bar": map[string]interface{}{
+							"Type": "SQS",
+							"Properties": map[string]interface{}{
+								"Queue": "arn:aws:sqs:us-east-1:123456789012:queue",
+							},
+						},
+					},
+				},
+			},
+			"FooBar": {
+				Type: "AWS::Serverless::Function",
+				Properties: map[string]interface{}{
+					"Handler": "index.handler",
+					"Runtime": "nodejs18.x",
+					"CodeUri": "s3://bucket/key",
+				},
+			},
+		},
+	}
+
+	_, err := tr.Transform(template)
+	if err == nil {
+		t.Fatal("expected logical ID collision error")
+	}
+	if !strings.Contains(err.Error(), "generated logical ID 'FooBar' collides") {
+		t.Errorf("expected collision error naming FooBar, got %v", err)
+	}
+}
+
+func TestTransformSharedDeploymentApplication(t *testing.T) {
+	tr := New()
+
+	function := func() types.Resource {
+		return types.Resource{
+			Type: "AWS::Serverless::Function",
+			Properties: map[string]interface{}{
+				"Handler":          "index.handler",
+				"Runtime":          "nodejs18.x",
+				"CodeUri":          "s3://bucket/key",
+				"AutoPublishAlias": "live",
+				"DeploymentPreference": map[string]interface{}{
+					"Type": "Linear10PercentEvery1Minute",
+				},
+			},
+		}
+	}
+
+	template := &types.Template{
+		AWSTemplateFormatVersion: "2010-09-09",
+		Transform:                "AWS::Serverless-2016-10-31",
+		Resources: map[string]types.Resource{
+			"FirstFunction":  function(),
+			"SecondFunction": function(),
+		},
+	}
+
+	result, err := tr.Transform(template)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+	if _, ok := result.Resources["ServerlessDeploymentApplication"]; !ok {
+		t.Error("expected shared ServerlessDeploymentApplication")
+	}
+}
+
+func TestTransformImplicitAndExplicitHttpApi(t *testing.T) {
+	tr := New()
+
+	template := &types.Template{
+		AWSTemplateFormatVersion: "2010-09-09",
+		Transform:                "AWS::Serverless-2016-10-31",
+		Resources: map[string]types.Resource{
+			"MyHttpApi": {
+				Type:       "AWS::Serverless::HttpApi",
+				Properties: map[string]interface{}{},
+			},
+			"MyFunction": {
+				Type: "AWS::Serverless::Function",
+				Properties: map[string]interface{}{
+					"Handler": "index.handler",
+					"Runtime": "nodejs18.x",
+					"CodeUri": "s3://bucket/key",
+					"Events": map[string]interface{}{
+						"Hello": map[string]interface{}{
+							"Type": "HttpApi",
+							"Properties": map[string]interface{}{
+								"Path":   "/hello",
+								"Method": "get",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	result, err := tr.Transform(template)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	for _, id := range []string{"MyHttpApi", "ServerlessHttpApi"} {
+		if result.Resources[id].Type != "AWS::ApiGatewayV2::Api" {
+			t.Errorf("expected %s to be an AWS::ApiGatewayV2::Api, got %q", id, result.Resources[id].Type)
+		}
+	}
+
+	explicitBody, _ := json.Marshal(result.Resources["MyHttpApi"].Properties["Body"])
+	if strings.Contains(string(explicitBody), "/hello") {
+		t.Errorf("explicit API should not receive implicit routes, got %s", explicitBody)
+	}
+	implicitBody, _ := json.Marshal(result.Resources["ServerlessHttpApi"].Properties["Body"])
+	if !strings.Contains(string(implicitBody), "/hello") {
+		t.Errorf("implicit API should contain the /hello route, got %s", implicitBody)
+	}
+
+	permission, _ := json.Marshal(result.Resources["MyFunctionHelloPermission"].Properties["SourceArn"])
+	if !strings.Contains(string(permission), `"Ref":"ServerlessHttpApi"`) {
+		t.Errorf("expected permission scoped to ServerlessHttpApi, got %s", permission)
+	}
+}
+
+func TestTransformApiTracingWithFunctionTracing(t *testing.T) {
+	tr := New()
+
+	template := &types.Template{
+		AWSTemplateFormatVersion: "2010-09-09",
+		Transform:                "AWS::Serverless-2016-10-31",
+		Globals: map[string]interface{}{
+			"Api": map[string]interface{}{
+				"TracingEnabled": true,
+			},
+		},
+		Resources: map[string]types.Resource{
+			"MyFunction": {
+				Type: "AWS::Serverless::Function",
+				Properties: map[string]interface{}{
+					"Handler": "index.handler",
+					"Runtime": "nodejs18.x",
+					"CodeUri": "s3://bucket/key",
+					"Tracing": "Active",
+					"Events": map[string]interface{}{
+						"Hello": map[string]interface{}{
+							"Type": "Api",
+							"Properties": map[string]interface{}{
+								"Path":   "/hello",
+								"Method": "get",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	result, err := tr.Transform(template)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	stage := result.Resources["ServerlessRestApiStage"]
+	if stage.Type != "AWS::ApiGateway::Stage" {
+		t.Fatalf("expected ServerlessRestApiStage stage, got %q", stage.Type)
+	}
+	if stage.Properties["TracingEnabled"] != true {
+		t.Errorf("expected stage TracingEnabled true, got %v", stage.Properties["TracingEnabled"])
+	}
+
+	tracingConfig, _ := json.Marshal(result.Resources["MyFunction"].Properties["TracingConfig"])
+	if string(tracingConfig) != `{"Mode":"Active"}` {
+		t.Errorf("expected function TracingConfig Mode Active, got %s", tracingConfig)
+	}
+}
+
+func TestTransformSNSEventDanglingTopicRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		topic   interface{}
+		wantErr bool
+	}{
+		{"topic defined in template", map[string]interface{}{"Ref": "MyTopic"}, false},
+		{"topic passed as parameter", map[string]interface{}{"Ref": "TopicArn"}, false},
+		{"topic ARN string", "arn:aws:sns:us-east-1:123456789012:topic", false},
+		{"dangling topic ref", map[string]interface{}{"Ref": "MissingTopic"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := &types.Template{
+				Transform: "AWS::Serverless-2016-10-31",
+				Parameters: map[string]types.Parameter{
+					"TopicArn": {Type: "String"},
+				},
+				Resources: map[string]types.Resource{
+					"MyTopic": {Type: "AWS::SNS::Topic"},
+					"MyFunction": {
+						Type: "AWS::Serverless::Function",
+						Properties: map[string]interface{}{
+							"Handler": "index.handler",
+							"Runtime": "nodejs18.x",
+							"CodeUri": "s3://bucket/key",
+							"Events": map[string]interface{}{
+								"Notify": map[string]interface{}{
+									"Type":       "SNS",
+									"Properties": map[string]interface{}{"Topic": tt.topic},
+								},
+							},
+						},
+					},
+				},
+			}
+
+			_, err := New().Transform(template)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected error for dangling topic Ref")
+			}
+			var eventErr *samerrors.InvalidEventException
+			if !errors.As(err, &eventErr) {
+				t.Fatalf("expected InvalidEventException, got %v", err)
+			}
+			if eventErr.EventID != "Notify" || !strings.Contains(eventErr.Message, "declare an AWS::SNS::Topic named 'MissingTopic'") {
+				t.Errorf("unexpected error: %v", eventErr)
+			}
+		})
+	}
+}
+
+func TestSupportedResourceTypesAreTransformed(t *testing.T) {
+	tr := New()
+
+	for _, resourceType := range SupportedResourceTypes() {
+		_, err := tr.transformSAMResource(&transformState{}, "MyResource", types.Resource{Type: resourceType, Properties: map[string]interface{}{}}, &sam.TransformContext{}, &types.Template{})
+		if err != nil && strings.Contains(err.Error(), "unknown SAM resource type") {
+			t.Errorf("%s is listed as supported but has no transform", resourceType)
+		}
+	}
+}
+
 func TestTransformMetadataPassthrough(t *testing.T) {
 	tr := NewWithOptions(Options{
 		PassThroughMetadata: true,
@@ -476,6 +785,131 @@ func TestTransformRemovesSAMTransform(t *testing.T) {
 	}
 }
 
+func TestTransformRemovesSAMTransformFromList(t *testing.T) {
+	tr := NewWithOptions(Options{TransformHeader: TransformHeaderRequire})
+
+	template := &types.Template{
+		AWSTemplateFormatVersion: "2010-09-09",
+		Transform:                []interface{}{"AWS::LanguageExtensions", "AWS::Serverless-2016-10-31"},
+		Resources:                map[string]types.Resource{},
+	}
+
+	result, err := tr.Transform(template)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if result.Transform != "AWS::LanguageExtensions" {
+		t.Errorf("expected only AWS::LanguageExtensions to remain, got %v", result.Transform)
+	}
+}
+
+func TestTransformPreservesCustomMacros(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform interface{}
+		expected  interface{}
+	}{
+		{
+			name:      "single macro",
+			transform: []interface{}{"AWS::Serverless-2016-10-31", "MyMacro"},
+			expected:  "MyMacro",
+		},
+		{
+			name:      "multiple macros keep order",
+			transform: []interface{}{"MacroA", "AWS::Serverless-2016-10-31", "MacroB"},
+			expected:  []interface{}{"MacroA", "MacroB"},
+		},
+		{
+			name:      "string list",
+			transform: []string{"AWS::Serverless-2016-10-31", "MyMacro"},
+			expected:  "MyMacro",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := &types.Template{
+				AWSTemplateFormatVersion: "2010-09-09",
+				Transform:                tt.transform,
+				Resources:                map[string]types.Resource{},
+			}
+
+			result, err := New().Transform(template)
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+			if !reflect.DeepEqual(result.Transform, tt.expected) {
+				t.Errorf("expected Transform %v, got %v", tt.expected, result.Transform)
+			}
+		})
+	}
+}
+
+func TestTransformMissingSAMTransform(t *testing.T) {
+	template := func() *types.Template {
+		return &types.Template{
+			AWSTemplateFormatVersion: "2010-09-09",
+			Resources: map[string]types.Resource{
+				"MyTable": {
+					Type: "AWS::Serverless::SimpleTable",
+				},
+			},
+		}
+	}
+
+	t.Run("required by default", func(t *testing.T) {
+		for _, tr := range []*Translator{New(), NewWithOptions(Options{TransformHeader: TransformHeaderRequire})} {
+			_, err := tr.Transform(template())
+			if err == nil {
+				t.Fatal("expected error for missing transform")
+			}
+			if !strings.Contains(err.Error(), "does not declare 'Transform: AWS::Serverless-2016-10-31'") {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}
+	})
+
+	t.Run("ignored", func(t *testing.T) {
+		result, err := NewWithOptions(Options{TransformHeader: TransformHeaderIgnore}).Transform(template())
+		if err != nil {
+			t.Fatalf("Transform failed: %v", err)
+		}
+		if result.Resources["MyTable"].Type != "AWS::DynamoDB::Table" {
+			t.Errorf("expected SimpleTable to be translated, got %v", result.Resources["MyTable"].Type)
+		}
+	})
+
+	t.Run("passed through", func(t *testing.T) {
+		result, err := NewWithOptions(Options{TransformHeader: TransformHeaderPassThrough}).Transform(template())
+		if err != nil {
+			t.Fatalf("Transform failed: %v", err)
+		}
+		if result.Resources["MyTable"].Type != "AWS::Serverless::SimpleTable" {
+			t.Errorf("expected template to be returned untouched, got %v", result.Resources["MyTable"].Type)
+		}
+	})
+}
+
+func TestHasSAMTransform(t *testing.T) {
+	tests := []struct {
+		transform interface{}
+		expected  bool
+	}{
+		{nil, false},
+		{"AWS::Serverless-2016-10-31", true},
+		{"AWS::LanguageExtensions", false},
+		{[]interface{}{"AWS::LanguageExtensions", "AWS::Serverless-2016-10-31"}, true},
+		{[]interface{}{"AWS::LanguageExtensions"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := HasSAMTransform(tt.transform); got != tt.expected {
+			t.Errorf("HasSAMTransform(%v) = %v, want %v", tt.transform, got, tt.expected)
+		}
+	}
+}
+
 func TestTransformStateMachine(t *testing.T) {
 	tr := New()
 
@@ -709,6 +1143,73 @@ Resources:
 	}
 }
 
+func TestTransformMap(t *testing.T) {
+	tr := New()
+
+	template := map[string]interface{}{
+		"AWSTemplateFormatVersion": "2010-09-09",
+		"Transform":                "AWS::Serverless-2016-10-31",
+		"Resources": map[string]interface{}{
+			"MyFunction": map[string]interface{}{
+				"Type": "AWS::Serverless::Function",
+				"Properties": map[string]interface{}{
+					"Handler":    "index.handler",
+					"Runtime":    "nodejs18.x",
+					"CodeUri":    "s3://bucket/code.zip",
+					"MemorySize": 256,
+				},
+			},
+		},
+	}
+
+	result, err := tr.TransformMap(template)
+	if err != nil {
+		t.Fatalf("TransformMap failed: %v", err)
+	}
+
+	if _, ok := result["Transform"]; ok {
+		t.Errorf("expected SAM transform to be removed, got %v", result["Transform"])
+	}
+
+	resources, ok := result["Resources"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected Resources map, got %T", result["Resources"])
+	}
+
+	fn, ok := resources["MyFunction"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected MyFunction in result")
+	}
+	if fn["Type"] != "AWS::Lambda::Function" {
+		t.Errorf("expected AWS::Lambda::Function, got %v", fn["Type"])
+	}
+	props := fn["Properties"].(map[string]interface{})
+	if props["MemorySize"] != float64(256) {
+		t.Errorf("expected MemorySize 256, got %v", props["MemorySize"])
+	}
+
+	if _, ok := resources["MyFunctionRole"]; !ok {
+		t.Error("expected MyFunctionRole in result")
+	}
+}
+
+func TestTransformMapError(t *testing.T) {
+	tr := New()
+
+	template := map[string]interface{}{
+		"Resources": map[string]interface{}{
+			"MyFunction": map[string]interface{}{
+				"Type":       "AWS::Serverless::Function",
+				"Properties": map[string]interface{}{},
+			},
+		},
+	}
+
+	if _, err := tr.TransformMap(template); err == nil {
+		t.Error("expected error for function without code")
+	}
+}
+
 func TestGetResourceOrder(t *testing.T) {
 	order := getResourceOrder()
 
@@ -766,3 +1267,974 @@ func (p *testPlugin) AfterTransform(t *types.Template) error {
 
 // Ensure testPlugin implements plugins.Plugin
 var _ plugins.Plugin = (*testPlugin)(nil)
+
+func TestTransformFunctionDependsOnEFSMountTargets(t *testing.T) {
+	tr := New()
+
+	template := &types.Template{
+		AWSTemplateFormatVersion: "2010-09-09",
+		Transform:                "AWS::Serverless-2016-10-31",
+		Resources: map[string]types.Resource{
+			"MountTargetB": {Type: "AWS::EFS::MountTarget", Properties: map[string]interface{}{}},
+			"MountTargetA": {Type: "AWS::EFS::MountTarget", Properties: map[string]interface{}{}},
+			"MyFunction": {
+				Type:      "AWS::Serverless::Function",
+				DependsOn: "MyBucket",
+				Properties: map[string]interface{}{
+					"Handler": "index.handler",
+					"Runtime": "nodejs18.x",
+					"CodeUri": "s3://bucket/key",
+					"VpcConfig": map[string]interface{}{
+						"SecurityGroupIds": []interface{}{"sg-12345678"},
+						"SubnetIds":        []interface{}{"subnet-12345678"},
+					},
+					"FileSystemConfigs": []interface{}{
+						map[string]interface{}{
+							"Arn":            "arn:aws:elasticfilesystem:us-east-1:123456789012:access-point/fsap-123",
+							"LocalMountPath": "/mnt/efs",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	result, err := tr.Transform(template)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	fn := result.Resources["MyFunction"]
+	expected := []interface{}{"MyBucket", "MountTargetA", "MountTargetB"}
+	if !reflect.DeepEqual(fn.DependsOn, expected) {
+		t.Errorf("expected DependsOn %v, got %v", expected, fn.DependsOn)
+	}
+}
+
+func TestTransformUseInlineBasicExecutionPolicy(t *testing.T) {
+	tr := NewWithOptions(Options{UseInlineBasicExecutionPolicy: true})
+
+	template := &types.Template{
+		Transform: "AWS::Serverless-2016-10-31",
+		Resources: map[string]types.Resource{
+			"MyFunction": {
+				Type: "AWS::Serverless::Function",
+				Properties: map[string]interface{}{
+					"Handler": "index.handler",
+					"Runtime": "nodejs18.x",
+					"CodeUri": "s3://bucket/key",
+				},
+			},
+		},
+	}
+
+	result, err := tr.Transform(template)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	roleProps := result.Resources["MyFunctionRole"].Properties
+	if managed, ok := roleProps["ManagedPolicyArns"]; ok {
+		t.Errorf("expected AWSLambdaBasicExecutionRole to be omitted, got %v", managed)
+	}
+	// The policy names the function's log group, so it is kept out of the role
+	policy, ok := result.Resources["MyFunctionBasicExecutionPolicy"]
+	if !ok || policy.Type != "AWS::IAM::Policy" {
+		t.Fatalf("expected MyFunctionBasicExecutionPolicy policy resource, got %v", result.Resources)
+	}
+	doc, _ := json.Marshal(policy.Properties["PolicyDocument"])
+	if !strings.Contains(string(doc), "log-group:/aws/lambda/${MyFunction}:*") {
+		t.Errorf("expected policy scoped to the function's log group, got %s", doc)
+	}
+}
+
+func TestTransformStrictEvents(t *testing.T) {
+	newTemplate := func() *types.Template {
+		return &types.Template{
+			Transform: "AWS::Serverless-2016-10-31",
+			Resources: map[string]types.Resource{
+				"MyFunction": {
+					Type: "AWS::Serverless::Function",
+					Properties: map[string]interface{}{
+						"Handler": "index.handler",
+						"Runtime": "nodejs18.x",
+						"CodeUri": "s3://bucket/key",
+						"Events": map[string]interface{}{
+							"Nightly": map[string]interface{}{
+								"Type":       "Schdule",
+								"Properties": map[string]interface{}{"Schedule": "rate(1 day)"},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("lenient by default", func(t *testing.T) {
+		result, err := New().Transform(newTemplate())
+		if err != nil {
+			t.Fatalf("Transform failed: %v", err)
+		}
+		for id := range result.Resources {
+			if strings.HasPrefix(id, "MyFunctionNightly") {
+				t.Errorf("expected unknown event to be skipped, got resource %s", id)
+			}
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		_, err := NewWithOptions(Options{StrictEvents: true}).Transform(newTemplate())
+		if err == nil {
+			t.Fatal("expected error for unknown event type")
+		}
+		for _, want := range []string{"'MyFunction'", "'Nightly'", "'Schdule'"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected error to contain %s, got %v", want, err)
+			}
+		}
+	})
+}
+
+func TestTransformRejectsRestApiOnlyPropertiesOnHttpApi(t *testing.T) {
+	tests := []struct {
+		name     string
+		property string
+		value    interface{}
+		want     string
+	}{
+		{
+			name:     "binary media types",
+			property: "BinaryMediaTypes",
+			value:    []interface{}{"image/png"},
+			want:     "BinaryMediaTypes is not supported on AWS::Serverless::HttpApi",
+		},
+		{
+			name:     "method settings",
+			property: "MethodSettings",
+			value:    []interface{}{map[string]interface{}{"HttpMethod": "*"}},
+			want:     "use DefaultRouteSettings or RouteSettings instead",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := &types.Template{
+				Transform: "AWS::Serverless-2016-10-31",
+				Resources: map[string]types.Resource{
+					"MyHttpApi": {
+						Type:       "AWS::Serverless::HttpApi",
+						Properties: map[string]interface{}{tt.property: tt.value},
+					},
+				},
+			}
+
+			_, err := New().Transform(template)
+			if err == nil {
+				t.Fatalf("expected %s to be rejected", tt.property)
+			}
+			var invalid *samerrors.InvalidResourceException
+			if !errors.As(err, &invalid) || invalid.ResourceID != "MyHttpApi" {
+				t.Errorf("expected InvalidResourceException for MyHttpApi, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestTransformIntrinsicTimeoutAndMemorySize(t *testing.T) {
+	tr := New()
+
+	template := &types.Template{
+		Transform: "AWS::Serverless-2016-10-31",
+		Parameters: map[string]types.Parameter{
+			"TimeoutParam":    {Type: "Number"},
+			"MemorySizeParam": {Type: "Number"},
+		},
+		Resources: map[string]types.Resource{
+			"MyFunction": {
+				Type: "AWS::Serverless::Function",
+				Properties: map[string]interface{}{
+					"Handler":    "index.handler",
+					"Runtime":    "nodejs18.x",
+					"CodeUri":    "s3://bucket/key",
+					"Timeout":    map[string]interface{}{"Ref": "TimeoutParam"},
+					"MemorySize": map[string]interface{}{"Ref": "MemorySizeParam"},
+				},
+			},
+		},
+	}
+
+	result, err := tr.Transform(template)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	props := result.Resources["MyFunction"].Properties
+	if !reflect.DeepEqual(props["Timeout"], map[string]interface{}{"Ref": "TimeoutParam"}) {
+		t.Errorf("expected Timeout Ref to be preserved, got %v", props["Timeout"])
+	}
+	if !reflect.DeepEqual(props["MemorySize"], map[string]interface{}{"Ref": "MemorySizeParam"}) {
+		t.Errorf("expected MemorySize Ref to be preserved, got %v", props["MemorySize"])
+	}
+}
+
+func TestTransformTemplateFormatVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		version string
+		want    string
+	}{
+		{name: "preserved", version: "2010-09-09", want: "2010-09-09"},
+		{name: "preserved with default", opts: Options{DefaultTemplateFormatVersion: true}, version: "2010-09-09", want: "2010-09-09"},
+		{name: "defaulted when missing", opts: Options{DefaultTemplateFormatVersion: true}, want: TemplateFormatVersion},
+		{name: "omitted without default", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := &types.Template{
+				AWSTemplateFormatVersion: tt.version,
+				Transform:                "AWS::Serverless-2016-10-31",
+				Resources: map[string]types.Resource{
+					"MyTable": {Type: "AWS::Serverless::SimpleTable"},
+				},
+			}
+
+			result, err := NewWithOptions(tt.opts).Transform(template)
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+			if result.AWSTemplateFormatVersion != tt.want {
+				t.Errorf("expected AWSTemplateFormatVersion %q, got %q", tt.want, result.AWSTemplateFormatVersion)
+			}
+		})
+	}
+}
+
+func TestTransformStandaloneAndEmbeddedConnectors(t *testing.T) {
+	input := `
+Transform: AWS::Serverless-2016-10-31
+Resources:
+  MyFunction:
+    Type: AWS::Serverless::Function
+    Connectors:
+      TableConnector:
+        Properties:
+          Destination:
+            Id: MyTable
+          Permissions:
+            - Read
+    Properties:
+      Handler: index.handler
+      Runtime: nodejs18.x
+      CodeUri: s3://bucket/key
+  MyTable:
+    Type: AWS::DynamoDB::Table
+  MyQueue:
+    Type: AWS::SQS::Queue
+  QueueConnector:
+    Type: AWS::Serverless::Connector
+    Properties:
+      Source:
+        Id: MyFunction
+      Destination:
+        Id: MyQueue
+      Permissions:
+        - Write
+`
+
+	tr := New()
+	output, err := tr.TransformBytes([]byte(input))
+	if err != nil {
+		t.Fatalf("TransformBytes failed: %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	resources := result["Resources"].(map[string]interface{})
+
+	for _, id := range []string{"MyFunctionTableConnectorPolicy", "QueueConnectorPolicy"} {
+		policy, ok := resources[id].(map[string]interface{})
+		if !ok {
+			t.Errorf("expected connector policy %s, got resources %v", id, resources)
+			continue
+		}
+		if policy["Type"] != "AWS::IAM::ManagedPolicy" {
+			t.Errorf("expected %s to be an AWS::IAM::ManagedPolicy, got %v", id, policy["Type"])
+		}
+	}
+
+	if _, ok := resources["QueueConnector"]; ok {
+		t.Error("expected the standalone connector resource to be removed")
+	}
+	function := resources["MyFunction"].(map[string]interface{})
+	if _, ok := function["Connectors"]; ok {
+		t.Error("expected embedded Connectors to be removed from the source resource")
+	}
+}
+
+func TestTransformStateMachineEmbeddedConnectors(t *testing.T) {
+	input := `
+Transform: AWS::Serverless-2016-10-31
+Resources:
+  MyStateMachine:
+    Type: AWS::Serverless::StateMachine
+    Connectors:
+      TableConnector:
+        Properties:
+          Destination:
+            Id: MyTable
+          Permissions:
+            - Read
+            - Write
+    Properties:
+      Definition:
+        StartAt: Done
+        States:
+          Done:
+            Type: Succeed
+  MyTable:
+    Type: AWS::Serverless::SimpleTable
+`
+
+	output, err := New().TransformBytes([]byte(input))
+	if err != nil {
+		t.Fatalf("TransformBytes failed: %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	resources := result["Resources"].(map[string]interface{})
+
+	policy, ok := resources["MyStateMachineTableConnectorPolicy"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected MyStateMachineTableConnectorPolicy, got resources %v", resources)
+	}
+	props := policy["Properties"].(map[string]interface{})
+	if !reflect.DeepEqual(props["Roles"], []interface{}{map[string]interface{}{"Ref": "MyStateMachineRole"}}) {
+		t.Errorf("expected Roles [MyStateMachineRole], got %v", props["Roles"])
+	}
+	if statements := props["PolicyDocument"].(map[string]interface{})["Statement"].([]interface{}); len(statements) != 2 {
+		t.Errorf("expected Read and Write statements, got %v", statements)
+	}
+	if _, ok := resources["MyStateMachine"].(map[string]interface{})["Connectors"]; ok {
+		t.Error("expected embedded Connectors to be removed from the state machine")
+	}
+}
+
+func TestTransformConnectorProfilesPath(t *testing.T) {
+	input := `
+Transform: AWS::Serverless-2016-10-31
+Resources:
+  MyFunction:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: index.handler
+      Runtime: nodejs18.x
+      CodeUri: s3://bucket/key
+  MyStore:
+    Type: AWS::Custom::Store
+  StoreConnector:
+    Type: AWS::Serverless::Connector
+    Properties:
+      Source:
+        Id: MyFunction
+      Destination:
+        Id: MyStore
+      Permissions:
+        - Write
+`
+	profiles := `{"Profiles": [{"Source": "AWS::Lambda::Function", "Destination": "AWS::Custom::Store", "Type": "AWS::IAM::ManagedPolicy", "Actions": {"Write": ["store:Put"]}}]}`
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	if err := os.WriteFile(path, []byte(profiles), 0o644); err != nil {
+		t.Fatalf("failed to write profiles: %v", err)
+	}
+
+	if _, err := New().TransformBytes([]byte(input)); err == nil || !strings.Contains(err.Error(), "no connector profile found") {
+		t.Fatalf("expected built-in profiles to reject the custom destination, got %v", err)
+	}
+
+	output, err := NewWithOptions(Options{ConnectorProfilesPath: path}).TransformBytes([]byte(input))
+	if err != nil {
+		t.Fatalf("TransformBytes failed: %v", err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	policy, ok := result["Resources"].(map[string]interface{})["StoreConnectorPolicy"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected StoreConnectorPolicy, got resources %v", result["Resources"])
+	}
+	if !strings.Contains(fmt.Sprint(policy["Properties"]), "store:Put") {
+		t.Errorf("expected store:Put action, got %v", policy["Properties"])
+	}
+
+	missing := NewWithOptions(Options{ConnectorProfilesPath: filepath.Join(t.TempDir(), "missing.json")})
+	if _, err := missing.TransformBytes([]byte(input)); err == nil || !strings.Contains(err.Error(), "failed to read connector profiles") {
+		t.Errorf("expected missing profiles file error, got %v", err)
+	}
+}
+
+func TestTransformApiDeploymentIDTracksRoutes(t *testing.T) {
+	newTemplate := func(events map[string]interface{}) *types.Template {
+		return &types.Template{
+			Transform: "AWS::Serverless-2016-10-31",
+			Resources: map[string]types.Resource{
+				"MyApi": {
+					Type: "AWS::Serverless::Api",
+					Properties: map[string]interface{}{
+						"StageName": "prod",
+					},
+				},
+				"MyFunction": {
+					Type: "AWS::Serverless::Function",
+					Properties: map[string]interface{}{
+						"Handler": "index.handler",
+						"Runtime": "nodejs18.x",
+						"CodeUri": "s3://bucket/key",
+						"Events":  events,
+					},
+				},
+			},
+		}
+	}
+	apiEvent := func(path, method string) map[string]interface{} {
+		return map[string]interface{}{
+			"Type": "Api",
+			"Properties": map[string]interface{}{
+				"RestApiId": map[string]interface{}{"Ref": "MyApi"},
+				"Path":      path,
+				"Method":    method,
+			},
+		}
+	}
+	deploymentID := func(template *types.Template) string {
+		result, err := New().Transform(template)
+		if err != nil {
+			t.Fatalf("Transform failed: %v", err)
+		}
+		for id, res := range result.Resources {
+			if res.Type == "AWS::ApiGateway::Deployment" {
+				return id
+			}
+		}
+		t.Fatal("expected an AWS::ApiGateway::Deployment resource")
+		return ""
+	}
+
+	oneRoute := deploymentID(newTemplate(map[string]interface{}{
+		"Get": apiEvent("/items", "get"),
+	}))
+	sameRoute := deploymentID(newTemplate(map[string]interface{}{
+		"Get": apiEvent("/items", "get"),
+	}))
+	twoRoutes := deploymentID(newTemplate(map[string]interface{}{
+		"Get":  apiEvent("/items", "get"),
+		"Post": apiEvent("/items", "post"),
+	}))
+
+	if oneRoute != sameRoute {
+		t.Errorf("expected the same routes to keep deployment ID %s, got %s", oneRoute, sameRoute)
+	}
+	if oneRoute == twoRoutes {
+		t.Errorf("expected adding a route to change deployment ID %s", oneRoute)
+	}
+}
+
+func TestTransformPartialDefinitionBodyGainsIntegration(t *testing.T) {
+	input := `
+Transform: AWS::Serverless-2016-10-31
+Resources:
+  MyApi:
+    Type: AWS::Serverless::Api
+    Properties:
+      StageName: prod
+      DefinitionBody:
+        swagger: "2.0"
+        info:
+          title: Items
+        paths:
+          /items:
+            get:
+              responses:
+                "200":
+                  description: Item list
+  MyFunction:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: index.handler
+      Runtime: nodejs18.x
+      CodeUri: s3://bucket/key
+      Events:
+        ListItems:
+          Type: Api
+          Properties:
+            RestApiId: !Ref MyApi
+            Path: /items
+            Method: get
+`
+
+	output, err := New().TransformBytes([]byte(input))
+	if err != nil {
+		t.Fatalf("TransformBytes failed: %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	api := result["Resources"].(map[string]interface{})["MyApi"].(map[string]interface{})
+	body := api["Properties"].(map[string]interface{})["Body"].(map[string]interface{})
+	getMethod := body["paths"].(map[string]interface{})["/items"].(map[string]interface{})["get"].(map[string]interface{})
+
+	expectedResponses := map[string]interface{}{"200": map[string]interface{}{"description": "Item list"}}
+	if !reflect.DeepEqual(getMethod["responses"], expectedResponses) {
+		t.Errorf("expected user responses to be preserved, got %v", getMethod["responses"])
+	}
+	integration, ok := getMethod["x-amazon-apigateway-integration"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected integration on the user operation, got %v", getMethod)
+	}
+	if !strings.Contains(fmt.Sprint(integration["uri"]), "MyFunction") {
+		t.Errorf("expected integration to target MyFunction, got %v", integration["uri"])
+	}
+}
+
+func TestTransformValidateRequestParameters(t *testing.T) {
+	input := `
+Transform: AWS::Serverless-2016-10-31
+Resources:
+  MyFunction:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: index.handler
+      Runtime: nodejs18.x
+      CodeUri: s3://bucket/key
+      Events:
+        GetItem:
+          Type: Api
+          Properties:
+            Path: /items/{id}
+            Method: get
+            RequestParameters:
+              - method.request.querystring.verbose:
+                  Required: true
+`
+
+	tests := []struct {
+		name          string
+		opts          Options
+		wantValidator bool
+	}{
+		{name: "default", opts: Options{}, wantValidator: false},
+		{name: "enabled", opts: Options{ValidateRequestParameters: true}, wantValidator: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := NewWithOptions(tt.opts).TransformBytes([]byte(input))
+			if err != nil {
+				t.Fatalf("TransformBytes failed: %v", err)
+			}
+
+			var result map[string]interface{}
+			if err := json.Unmarshal(output, &result); err != nil {
+				t.Fatalf("failed to parse output: %v", err)
+			}
+			api := result["Resources"].(map[string]interface{})["ServerlessRestApi"].(map[string]interface{})
+			body := api["Properties"].(map[string]interface{})["Body"].(map[string]interface{})
+			getMethod := body["paths"].(map[string]interface{})["/items/{id}"].(map[string]interface{})["get"].(map[string]interface{})
+
+			validator, hasValidator := getMethod["x-amazon-apigateway-request-validator"]
+			_, hasValidators := body["x-amazon-apigateway-request-validators"]
+			if !tt.wantValidator {
+				if hasValidator || hasValidators {
+					t.Errorf("expected no request validator, got %v", body)
+				}
+				return
+			}
+			if validator != "params-only" {
+				t.Errorf("expected params-only validator on the operation, got %v", validator)
+			}
+			expected := map[string]interface{}{
+				"params-only": map[string]interface{}{
+					"validateRequestBody":       false,
+					"validateRequestParameters": true,
+				},
+			}
+			if !reflect.DeepEqual(body["x-amazon-apigateway-request-validators"], expected) {
+				t.Errorf("expected params-only validator declaration, got %v", body["x-amazon-apigateway-request-validators"])
+			}
+		})
+	}
+}
+
+func TestTransformIntegratedDefinitionBodyIsKept(t *testing.T) {
+	input := `
+Transform: AWS::Serverless-2016-10-31
+Resources:
+  MyApi:
+    Type: AWS::Serverless::Api
+    Properties:
+      StageName: prod
+      DefinitionBody:
+        swagger: "2.0"
+        info:
+          title: Hello
+        paths:
+          /hello:
+            get:
+              x-amazon-apigateway-integration:
+                type: aws_proxy
+                httpMethod: POST
+                uri: !Sub arn:${AWS::Partition}:apigateway:${AWS::Region}:lambda:path/2015-03-31/functions/${MyFunction.Arn}/invocations
+  MyFunction:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: index.handler
+      Runtime: nodejs18.x
+      CodeUri: s3://bucket/key
+      Events:
+        Hello:
+          Type: Api
+          Properties:
+            RestApiId: !Ref MyApi
+            Path: /hello
+            Method: get
+`
+
+	output, err := New().TransformBytes([]byte(input))
+	if err != nil {
+		t.Fatalf("TransformBytes failed: %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	resources := result["Resources"].(map[string]interface{})
+	body := resources["MyApi"].(map[string]interface{})["Properties"].(map[string]interface{})["Body"].(map[string]interface{})
+	getMethod := body["paths"].(map[string]interface{})["/hello"].(map[string]interface{})["get"].(map[string]interface{})
+
+	expected := map[string]interface{}{
+		"type":       "aws_proxy",
+		"httpMethod": "POST",
+		"uri": map[string]interface{}{
+			"Fn::Sub": "arn:${AWS::Partition}:apigateway:${AWS::Region}:lambda:path/2015-03-31/functions/${MyFunction.Arn}/invocations",
+		},
+	}
+	if !reflect.DeepEqual(getMethod["x-amazon-apigateway-integration"], expected) {
+		t.Errorf("expected the provided integration to be kept, got %v", getMethod["x-amazon-apigateway-integration"])
+	}
+	if _, ok := resources["MyFunctionHelloPermission"]; !ok {
+		t.Error("expected the event's invoke permission")
+	}
+}
+
+func TestTransformIntegratedHttpApiDefinitionBodyIsKept(t *testing.T) {
+	newInput := func(events string) string {
+		return `
+Transform: AWS::Serverless-2016-10-31
+Resources:
+  MyHttpApi:
+    Type: AWS::Serverless::HttpApi
+    Properties:
+      DefinitionBody:
+        openapi: "3.0.1"
+        info:
+          title: Hello
+        paths:
+          /hello:
+            get:
+              x-amazon-apigateway-integration:
+                type: http_proxy
+                httpMethod: GET
+                uri: https://example.com/hello
+                payloadFormatVersion: "1.0"
+  MyFunction:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: index.handler
+      Runtime: nodejs18.x
+      CodeUri: s3://bucket/key
+      Events:` + events
+	}
+	event := func(name string) string {
+		return `
+        ` + name + `:
+          Type: HttpApi
+          Properties:
+            ApiId: !Ref MyHttpApi
+            Path: /hello
+            Method: get`
+	}
+
+	output, err := New().TransformBytes([]byte(newInput(event("Hello"))))
+	if err != nil {
+		t.Fatalf("TransformBytes failed: %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+	api := result["Resources"].(map[string]interface{})["MyHttpApi"].(map[string]interface{})
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(api["Properties"].(map[string]interface{})["Body"].(string)), &body); err != nil {
+		t.Fatalf("failed to parse Body: %v", err)
+	}
+	getMethod := body["paths"].(map[string]interface{})["/hello"].(map[string]interface{})["get"].(map[string]interface{})
+	integration := getMethod["x-amazon-apigateway-integration"].(map[string]interface{})
+	if integration["type"] != "http_proxy" || integration["uri"] != "https://example.com/hello" {
+		t.Errorf("expected the provided integration to be kept, got %v", integration)
+	}
+
+	// Two events on the same route are still a conflict
+	_, err = New().TransformBytes([]byte(newInput(event("Hello") + event("Again"))))
+	if err == nil || !strings.Contains(err.Error(), `API method "get" defined multiple times for path "/hello"`) {
+		t.Errorf("expected duplicate route error, got %v", err)
+	}
+}
+
+func TestTransformPolicyTemplatePseudoParameterArn(t *testing.T) {
+	template := &types.Template{
+		Transform: "AWS::Serverless-2016-10-31",
+		Resources: map[string]types.Resource{
+			"MyFunction": {
+				Type: "AWS::Serverless::Function",
+				Properties: map[string]interface{}{
+					"Handler": "index.handler",
+					"Runtime": "nodejs18.x",
+					"CodeUri": "s3://bucket/key",
+					"Policies": []interface{}{
+						map[string]interface{}{
+							"SSMParameterReadPolicy": map[string]interface{}{"ParameterName": "app/config"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	result, err := NewWithOptions(Options{Region: "eu-west-1", AccountID: "123456789012", Partition: "aws"}).Transform(template)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	encoded, err := json.Marshal(result.Resources["MyFunctionRole"])
+	if err != nil {
+		t.Fatalf("failed to marshal role: %v", err)
+	}
+	expectedArn := "arn:${AWS::Partition}:ssm:${AWS::Region}:${AWS::AccountId}:parameter/${parameterName}"
+	if !strings.Contains(string(encoded), expectedArn) {
+		t.Errorf("expected role policy to contain %q, got %s", expectedArn, encoded)
+	}
+	if strings.Contains(string(encoded), "eu-west-1") || strings.Contains(string(encoded), "123456789012") {
+		t.Errorf("expected pseudo parameters rather than context values, got %s", encoded)
+	}
+}
+
+func TestTransformGlobalsRolePathAndPermissionsBoundary(t *testing.T) {
+	input := `
+Transform: AWS::Serverless-2016-10-31
+Globals:
+  Function:
+    RolePath: /service/
+    PermissionsBoundary: arn:aws:iam::123456789012:policy/boundary
+Resources:
+  Generated:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: index.handler
+      Runtime: nodejs18.x
+      CodeUri: s3://bucket/key
+  Existing:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: index.handler
+      Runtime: nodejs18.x
+      CodeUri: s3://bucket/key
+      Role: arn:aws:iam::123456789012:role/existing
+`
+	output, err := New().TransformBytes([]byte(input))
+	if err != nil {
+		t.Fatalf("TransformBytes failed: %v", err)
+	}
+
+	var result struct {
+		Resources map[string]struct {
+			Properties map[string]interface{}
+		}
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("failed to parse output: %v", err)
+	}
+
+	role, ok := result.Resources["GeneratedRole"]
+	if !ok {
+		t.Fatal("expected GeneratedRole")
+	}
+	if role.Properties["Path"] != "/service/" {
+		t.Errorf("expected role Path /service/, got %v", role.Properties["Path"])
+	}
+	if role.Properties["PermissionsBoundary"] != "arn:aws:iam::123456789012:policy/boundary" {
+		t.Errorf("expected role PermissionsBoundary, got %v", role.Properties["PermissionsBoundary"])
+	}
+
+	if _, ok := result.Resources["ExistingRole"]; ok {
+		t.Error("expected no generated role for a function with Role")
+	}
+	if role := result.Resources["Existing"].Properties["Role"]; role != "arn:aws:iam::123456789012:role/existing" {
+		t.Errorf("expected the user role, got %v", role)
+	}
+}
+
+func TestTransformCreatedByTag(t *testing.T) {
+	input := `
+Transform: AWS::Serverless-2016-10-31
+Globals:
+  Function:
+    Tags:
+      Team: backend
+Resources:
+  MyFunction:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: index.handler
+      Runtime: nodejs18.x
+      CodeUri: s3://bucket/key
+      Tags:
+        Stage: prod
+`
+	tests := []struct {
+		name     string
+		opts     Options
+		wantTags []interface{}
+	}{
+		{
+			name: "default",
+			wantTags: []interface{}{
+				map[string]interface{}{"Key": "lambda:createdBy", "Value": "SAM"},
+				map[string]interface{}{"Key": "Stage", "Value": "prod"},
+				map[string]interface{}{"Key": "Team", "Value": "backend"},
+			},
+		},
+		{
+			name: "omitted",
+			opts: Options{OmitCreatedByTag: true},
+			wantTags: []interface{}{
+				map[string]interface{}{"Key": "Stage", "Value": "prod"},
+				map[string]interface{}{"Key": "Team", "Value": "backend"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := NewWithOptions(tt.opts).TransformBytes([]byte(input))
+			if err != nil {
+				t.Fatalf("TransformBytes failed: %v", err)
+			}
+
+			var result struct {
+				Resources map[string]struct {
+					Properties map[string]interface{}
+				}
+			}
+			if err := json.Unmarshal(output, &result); err != nil {
+				t.Fatalf("failed to parse output: %v", err)
+			}
+
+			for _, id := range []string{"MyFunction", "MyFunctionRole"} {
+				if tags := result.Resources[id].Properties["Tags"]; !reflect.DeepEqual(tags, tt.wantTags) {
+					t.Errorf("%s: expected Tags %v, got %v", id, tt.wantTags, tags)
+				}
+			}
+		})
+	}
+}
+
+func TestTransformConcurrentTranslators(t *testing.T) {
+	// Translators created concurrently share the built-in policy templates
+	// and connector profiles, and must still produce identical output
+	inputs := [][]byte{
+		[]byte(`
+Transform: AWS::Serverless-2016-10-31
+Resources:
+  MyFunction:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: index.handler
+      Runtime: nodejs18.x
+      CodeUri: s3://bucket/key
+      Policies:
+        - SQSPollerPolicy:
+            QueueName: my-queue
+`),
+		[]byte(`
+Transform: AWS::Serverless-2016-10-31
+Resources:
+  MyFunction:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: index.handler
+      Runtime: nodejs18.x
+      CodeUri: s3://bucket/key
+  MyTable:
+    Type: AWS::Serverless::SimpleTable
+  MyConnector:
+    Type: AWS::Serverless::Connector
+    Properties:
+      Source:
+        Id: MyFunction
+      Destination:
+        Id: MyTable
+      Permissions:
+        - Read
+        - Write
+`),
+	}
+
+	expected := make([][]byte, len(inputs))
+	for i, input := range inputs {
+		output, err := New().TransformBytes(input)
+		if err != nil {
+			t.Fatalf("TransformBytes failed: %v", err)
+		}
+		expected[i] = output
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8*len(inputs))
+	for n := 0; n < 8; n++ {
+		for i, input := range inputs {
+			wg.Add(1)
+			go func(i int, input []byte) {
+				defer wg.Done()
+				output, err := New().TransformBytes(input)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if string(output) != string(expected[i]) {
+					errs <- fmt.Errorf("output %d differs from sequential transform", i)
+				}
+			}(i, input)
+		}
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
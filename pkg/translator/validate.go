@@ -0,0 +1,293 @@
+package translator
+
+// This file contains Validate, which reports template problems as structured
+// diagnostics for editors and language servers instead of failing a transform.
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lex00/aws-sam-translator-go/pkg/parser"
+)
+
+// Severity is how serious a Diagnostic is.
+type Severity string
+
+const (
+	// SeverityError marks a problem that makes the template fail to transform
+	// or deploy.
+	SeverityError Severity = "error"
+
+	// SeverityWarning marks a problem the transform tolerates, such as an
+	// ignored property.
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic describes one problem found in a template.
+type Diagnostic struct {
+	// Severity is how serious the problem is.
+	Severity Severity `json:"severity"`
+
+	// LogicalID is the resource the problem belongs to, or empty for
+	// template-level problems.
+	LogicalID string `json:"logicalId,omitempty"`
+
+	// Path is the dot-separated location of the problem within the resource,
+	// such as Properties.Events.MyEvent.Properties.Queue, or within the
+	// template when LogicalID is empty. It is empty when unknown.
+	Path string `json:"path,omitempty"`
+
+	// Message describes the problem.
+	Message string `json:"message"`
+}
+
+// Validate checks a YAML or JSON SAM template and returns its problems as
+// diagnostics without producing output. It reports the template structure,
+// malformed intrinsic functions, references to undefined resources and
+// parameters, transform errors such as invalid enum values, and transform
+// warnings. The error is only non-nil when the template cannot be parsed.
+// Validate runs a transform, so it replaces the warnings from the previous
+// Transform.
+func (t *Translator) Validate(template []byte) ([]Diagnostic, error) {
+	raw, err := parser.New().ParseRaw(template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	if err := parser.ValidateTemplate(raw); err != nil {
+		message := err.Error()
+		var parseErr *parser.ParseError
+		if errors.As(err, &parseErr) {
+			message = parseErr.Message
+		}
+		return []Diagnostic{{Severity: SeverityError, Message: message}}, nil
+	}
+
+	output, state, transformErr := t.transformRaw(raw)
+	t.setWarnings(state)
+
+	refs := newReferenceSet(raw)
+	if output != nil {
+		for id := range output.Resources {
+			refs.resources[id] = true
+		}
+	}
+
+	resources, ok := raw["Resources"].(map[string]interface{})
+	if !ok {
+		return []Diagnostic{{Severity: SeverityError, Message: "'Resources' must be a mapping"}}, nil
+	}
+
+	var diagnostics []Diagnostic
+	for _, id := range sortedKeys(resources) {
+		diagnostics = append(diagnostics, refs.check(id, "", resources[id])...)
+	}
+	if outputs, ok := raw["Outputs"].(map[string]interface{}); ok {
+		diagnostics = append(diagnostics, refs.check("", "Outputs", outputs)...)
+	}
+
+	diagnostics = append(diagnostics, transformDiagnostics(transformErr)...)
+	return append(diagnostics, state.diagnostics...), nil
+}
+
+// transformDiagnostics converts a transform error into error diagnostics,
+// one per resource error.
+func transformDiagnostics(err error) []Diagnostic {
+	if err == nil {
+		return nil
+	}
+
+	errs := []error{err}
+	var transformErr *TransformError
+	if errors.As(err, &transformErr) {
+		errs = transformErr.Errors
+	}
+
+	diagnostics := make([]Diagnostic, 0, len(errs))
+	for _, err := range errs {
+		diagnostic := Diagnostic{Severity: SeverityError, Message: err.Error()}
+		var resErr *resourceError
+		if errors.As(err, &resErr) {
+			diagnostic.LogicalID = resErr.logicalID
+			diagnostic.Message = resErr.err.Error()
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
+
+// subVariablePattern matches the ${Name} and ${Name.Attribute} variables of
+// an Fn::Sub string. Escaped ${!Literal} sequences do not match.
+var subVariablePattern = regexp.MustCompile(`\$\{([^!}][^}]*)\}`)
+
+// implicitResourceIDs are generated resources a template may reference
+// without declaring them.
+var implicitResourceIDs = []string{"ServerlessRestApi", "ServerlessHttpApi"}
+
+// referenceSet holds the names that Ref, Fn::GetAtt and Fn::Sub may refer to.
+type referenceSet struct {
+	parameters map[string]bool
+	resources  map[string]bool
+
+	// samIDs are the SAM resources, whose generated resources are named by
+	// prefixing their logical ID, such as MyFunctionRole
+	samIDs []string
+}
+
+// newReferenceSet collects the parameters and resources declared in a raw template.
+func newReferenceSet(raw map[string]interface{}) *referenceSet {
+	refs := &referenceSet{
+		parameters: make(map[string]bool),
+		resources:  newPropertySet(implicitResourceIDs...),
+	}
+	if params, ok := raw["Parameters"].(map[string]interface{}); ok {
+		for name := range params {
+			refs.parameters[name] = true
+		}
+	}
+	resources, _ := raw["Resources"].(map[string]interface{})
+	for _, id := range sortedKeys(resources) {
+		refs.resources[id] = true
+		if res, ok := resources[id].(map[string]interface{}); ok {
+			if resType, _ := res["Type"].(string); isSAMResource(resType) {
+				refs.samIDs = append(refs.samIDs, id)
+			}
+		}
+	}
+	return refs
+}
+
+// hasResource reports whether id is a declared or generated resource.
+func (r *referenceSet) hasResource(id string) bool {
+	if r.resources[id] {
+		return true
+	}
+	for _, samID := range r.samIDs {
+		if strings.HasPrefix(id, samID) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasRef reports whether name can be the target of a Ref.
+func (r *referenceSet) hasRef(name string) bool {
+	return strings.HasPrefix(name, "AWS::") || r.parameters[name] || r.hasResource(name)
+}
+
+// check walks value, located at path within resource logicalID, and returns
+// diagnostics for malformed intrinsic functions and undefined references.
+func (r *referenceSet) check(logicalID, path string, value interface{}) []Diagnostic {
+	var diagnostics []Diagnostic
+	report := func(path, message string) {
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity:  SeverityError,
+			LogicalID: logicalID,
+			Path:      path,
+			Message:   message,
+		})
+	}
+
+	var walk func(path string, value interface{}, subVars map[string]bool)
+	walk = func(path string, value interface{}, subVars map[string]bool) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if name := parser.GetIntrinsicName(v); name != "" {
+				intrinsicPath := joinPath(path, name)
+				if err := parser.ValidateIntrinsicStructure(name, v[name]); err != nil {
+					report(intrinsicPath, err.Error())
+					return
+				}
+				r.checkReference(name, v[name], func(message string) { report(intrinsicPath, message) })
+				walk(intrinsicPath, v[name], subVariables(name, v[name]))
+				return
+			}
+			for _, key := range sortedKeys(v) {
+				walk(joinPath(path, key), v[key], nil)
+			}
+		case []interface{}:
+			for i, item := range v {
+				walk(fmt.Sprintf("%s[%d]", path, i), item, subVars)
+			}
+		case string:
+			if subVars == nil {
+				return
+			}
+			for _, match := range subVariablePattern.FindAllStringSubmatch(v, -1) {
+				name, _, _ := strings.Cut(match[1], ".")
+				if !subVars[name] && !r.hasRef(name) {
+					report(path, fmt.Sprintf("Fn::Sub variable '%s' does not refer to a parameter or resource", match[1]))
+				}
+			}
+		}
+	}
+
+	walk(path, value, nil)
+	return diagnostics
+}
+
+// checkReference reports a Ref or Fn::GetAtt whose target is undefined.
+func (r *referenceSet) checkReference(name string, value interface{}, report func(string)) {
+	switch name {
+	case "Ref":
+		if target, ok := value.(string); ok && !r.hasRef(target) {
+			report(fmt.Sprintf("Ref '%s' does not refer to a parameter or resource", target))
+		}
+	case "Fn::GetAtt":
+		var target string
+		switch v := value.(type) {
+		case string:
+			target, _, _ = strings.Cut(v, ".")
+		case []interface{}:
+			if len(v) > 0 {
+				target, _ = v[0].(string)
+			}
+		case []string:
+			if len(v) > 0 {
+				target = v[0]
+			}
+		}
+		if target != "" && !r.hasResource(target) {
+			report(fmt.Sprintf("Fn::GetAtt '%s' does not refer to a resource", target))
+		}
+	}
+}
+
+// subVariables returns the names an Fn::Sub string may use beyond parameters
+// and resources, namely the keys of its variable map, or nil when value is
+// not an Fn::Sub.
+func subVariables(name string, value interface{}) map[string]bool {
+	if name != "Fn::Sub" {
+		return nil
+	}
+	vars := make(map[string]bool)
+	if list, ok := value.([]interface{}); ok && len(list) == 2 {
+		if varMap, ok := list[1].(map[string]interface{}); ok {
+			for key := range varMap {
+				vars[key] = true
+			}
+		}
+	}
+	return vars
+}
+
+// joinPath appends key to a dot-separated path.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
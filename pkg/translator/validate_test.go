@@ -0,0 +1,118 @@
+package translator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	input := `
+Transform: AWS::Serverless-2016-10-31
+Parameters:
+  Env:
+    Type: String
+Resources:
+  MyFunction:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: index.handler
+      Runtime: python2.7
+      CodeUri: s3://bucket/key
+      Colour: blue
+      Role: !GetAtt MissingRole.Arn
+      Environment:
+        Variables:
+          TABLE: !Ref MissingTable
+          NAME: !Sub "${Env}-${AWS::Region}-${Suffix}-${MyFunctionRole.Arn}"
+          RAW: !Sub "${!Literal}"
+  MyStateMachine:
+    Type: AWS::Serverless::StateMachine
+    Properties:
+      Definition:
+        StartAt: Done
+        States:
+          Done:
+            Type: Succeed
+      Logging:
+        Level: VERBOSE
+Outputs:
+  RoleArn:
+    Value: !GetAtt MyFunctionRole.Arn
+  Bad:
+    Value: !Select [0]
+`
+
+	diagnostics, err := New().Validate([]byte(input))
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	expected := []Diagnostic{
+		{Severity: SeverityError, LogicalID: "MyFunction", Path: "Properties.Environment.Variables.NAME.Fn::Sub", Message: "Fn::Sub variable 'Suffix' does not refer to a parameter or resource"},
+		{Severity: SeverityError, LogicalID: "MyFunction", Path: "Properties.Environment.Variables.TABLE.Ref", Message: "Ref 'MissingTable' does not refer to a parameter or resource"},
+		{Severity: SeverityError, LogicalID: "MyFunction", Path: "Properties.Role.Fn::GetAtt", Message: "Fn::GetAtt 'MissingRole' does not refer to a resource"},
+		{Severity: SeverityError, Path: "Outputs.Bad.Value.Fn::Select", Message: "Fn::Select: Fn::Select array must have exactly 2 elements"},
+		{Severity: SeverityError, LogicalID: "MyStateMachine", Message: "Logging Level must be one of [ALL, ERROR, FATAL, OFF], got 'VERBOSE'"},
+		{Severity: SeverityWarning, LogicalID: "MyFunction", Path: "Properties.Colour", Message: "unrecognized property 'Colour' was ignored"},
+		{Severity: SeverityWarning, LogicalID: "MyFunction", Path: "Properties.Runtime", Message: "runtime 'python2.7' is deprecated; consider upgrading to a supported runtime"},
+	}
+	if !reflect.DeepEqual(diagnostics, expected) {
+		t.Errorf("unexpected diagnostics:\n got: %+v\nwant: %+v", diagnostics, expected)
+	}
+}
+
+func TestValidateValidTemplate(t *testing.T) {
+	input := `
+Transform: AWS::Serverless-2016-10-31
+Resources:
+  MyFunction:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: index.handler
+      Runtime: nodejs18.x
+      CodeUri: s3://bucket/key
+      Events:
+        GetItems:
+          Type: Api
+          Properties:
+            Path: /items
+            Method: get
+Outputs:
+  Endpoint:
+    Value: !Sub "https://${ServerlessRestApi}.execute-api.${AWS::Region}.amazonaws.com/Prod"
+`
+
+	diagnostics, err := New().Validate([]byte(input))
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if len(diagnostics) != 0 {
+		t.Errorf("expected no diagnostics, got %+v", diagnostics)
+	}
+}
+
+func TestValidateStructure(t *testing.T) {
+	diagnostics, err := New().Validate([]byte("Transform: AWS::Serverless-2016-10-31\nResources:\n  MyFunction:\n    Properties: {}\n"))
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	expected := []Diagnostic{{Severity: SeverityError, Message: "resource 'MyFunction' is missing required 'Type' property"}}
+	if !reflect.DeepEqual(diagnostics, expected) {
+		t.Errorf("expected %+v, got %+v", expected, diagnostics)
+	}
+
+	if _, err := New().Validate([]byte("Resources: [")); err == nil {
+		t.Error("expected an error for an unparseable template")
+	}
+
+	for _, resources := range []string{"[]", "1"} {
+		diagnostics, err := New().Validate([]byte("Transform: AWS::Serverless-2016-10-31\nResources: " + resources + "\n"))
+		if err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		expected := []Diagnostic{{Severity: SeverityError, Message: "'Resources' must be a mapping"}}
+		if !reflect.DeepEqual(diagnostics, expected) {
+			t.Errorf("Resources %s: expected %+v, got %+v", resources, expected, diagnostics)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package translator
+
+// This file contains detection of SAM properties that the parsers do not
+// consume, so they can be reported instead of being silently dropped.
+
+import (
+	"fmt"
+	"sort"
+)
+
+// functionProperties lists the AWS::Serverless::Function properties consumed by parseFunction.
+var functionProperties = newPropertySet(
+	"Handler", "Runtime", "CodeUri", "ImageUri", "PackageType", "Description",
+	"MemorySize", "Timeout", "Role", "Policies", "Environment", "Events", "Tags",
+	"Layers", "VpcConfig", "FunctionName", "Architectures", "AutoPublishAlias",
+	"AutoPublishCodeSha256", "DeploymentPreference", "ProvisionedConcurrencyConfig",
+	"ProvisionedConcurrencyAutoScaling", "ReservedConcurrentExecutions", "Tracing",
+	"DeadLetterQueue", "KmsKeyArn",
+	"EphemeralStorage", "SnapStart", "FileSystemConfigs", "ImageConfig",
+	"CodeSigningConfigArn", "RuntimeManagementConfig", "PermissionsBoundary", "RolePath",
+	"FunctionUrlConfig", "LoggingConfig", "RecursiveLoop", "Connectors",
+)
+
+// apiProperties lists the AWS::Serverless::Api properties consumed by parseApi.
+var apiProperties = newPropertySet(
+	"StageName", "Name", "Description", "DefinitionBody", "DefinitionUri",
+	"CacheClusterEnabled", "CacheClusterSize", "Variables", "EndpointConfiguration",
+	"MethodSettings", "BinaryMediaTypes", "MinimumCompressionSize", "Cors", "Auth",
+	"GatewayResponses", "AccessLogSetting", "CanarySetting", "TracingEnabled",
+	"OpenApiVersion", "Models", "Domain", "FailOnWarnings", "DisableExecuteApiEndpoint",
+	"Tags", "ApiKeySourceType", "Connectors",
+)
+
+// httpApiProperties lists the AWS::Serverless::HttpApi properties consumed by parseHttpApi.
+var httpApiProperties = newPropertySet(
+	"StageName", "Name", "Description", "DefinitionBody", "DefinitionUri",
+	"StageVariables", "CorsConfiguration", "Auth", "AccessLogSettings",
+	"DefaultRouteSettings", "RouteSettings", "Domain", "FailOnWarnings",
+	"DisableExecuteApiEndpoint", "Tags", "Connectors",
+)
+
+// DefaultDeprecatedRuntimes lists the Lambda runtimes that have reached
+// end of support and are reported with a warning.
+var DefaultDeprecatedRuntimes = []string{
+	"nodejs", "nodejs4.3", "nodejs4.3-edge", "nodejs6.10", "nodejs8.10",
+	"nodejs10.x", "nodejs12.x", "nodejs14.x", "nodejs16.x",
+	"python2.7", "python3.6", "python3.7", "python3.8",
+	"ruby2.5", "ruby2.7",
+	"java8",
+	"dotnetcore1.0", "dotnetcore2.0", "dotnetcore2.1", "dotnetcore3.1",
+	"dotnet5.0", "dotnet6", "dotnet7",
+	"go1.x", "provided",
+}
+
+// newPropertySet builds a lookup set from property names.
+func newPropertySet(names ...string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+// Warnings returns the warnings collected during the most recent Transform.
+// When a Translator is shared between goroutines, this is the most recent
+// Transform to complete.
+func (t *Translator) Warnings() []string {
+	t.warningsMu.Lock()
+	defer t.warningsMu.Unlock()
+	return append([]string(nil), t.warnings...)
+}
+
+// Diagnostics returns the warnings collected during the most recent
+// Transform as diagnostics, with the resource and property they refer to.
+func (t *Translator) Diagnostics() []Diagnostic {
+	t.warningsMu.Lock()
+	defer t.warningsMu.Unlock()
+	return append([]Diagnostic(nil), t.diagnostics...)
+}
+
+// setWarnings records the warnings of state returned by Warnings and Diagnostics.
+func (t *Translator) setWarnings(state *transformState) {
+	t.warningsMu.Lock()
+	defer t.warningsMu.Unlock()
+	t.warnings = state.warnings
+	t.diagnostics = state.diagnostics
+}
+
+// warn records a warning about a resource property, both as a message for
+// Warnings and as a diagnostic for Validate.
+func (s *transformState) warn(logicalID, path, message string) {
+	s.warnings = append(s.warnings, fmt.Sprintf("resource '%s': %s", logicalID, message))
+	s.diagnostics = append(s.diagnostics, Diagnostic{
+		Severity:  SeverityWarning,
+		LogicalID: logicalID,
+		Path:      path,
+		Message:   message,
+	})
+}
+
+// warnUnknownProperties records a warning for each property in props that is not in known.
+func (s *transformState) warnUnknownProperties(logicalID string, props map[string]interface{}, known map[string]bool) {
+	var unknown []string
+	for key := range props {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+
+	for _, key := range unknown {
+		s.warn(logicalID, "Properties."+key, fmt.Sprintf("unrecognized property '%s' was ignored", key))
+	}
+}
+
+// warnDeprecatedRuntime records a warning when runtime is a deprecated Lambda runtime.
+func (t *Translator) warnDeprecatedRuntime(state *transformState, logicalID, runtime string) {
+	if t.deprecatedRuntimes[runtime] {
+		state.warn(logicalID, "Properties.Runtime", fmt.Sprintf("runtime '%s' is deprecated; consider upgrading to a supported runtime", runtime))
+	}
+}
+
+// warnLayerTags records a warning when a layer sets Tags, which
+// AWS::Lambda::LayerVersion does not support.
+func (s *transformState) warnLayerTags(logicalID string, props map[string]interface{}) {
+	if _, ok := props["Tags"]; ok {
+		s.warn(logicalID, "Properties.Tags", "Tags are not supported on AWS::Serverless::LayerVersion and were ignored")
+	}
+}
@@ -0,0 +1,226 @@
+package translator
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/lex00/aws-sam-translator-go/pkg/types"
+)
+
+func TestTransformWarnsOnUnknownProperties(t *testing.T) {
+	tr := New()
+
+	template := &types.Template{
+		AWSTemplateFormatVersion: "2010-09-09",
+		Transform:                "AWS::Serverless-2016-10-31",
+		Resources: map[string]types.Resource{
+			"MyFunction": {
+				Type: "AWS::Serverless::Function",
+				Properties: map[string]interface{}{
+					"Handler":    "index.handler",
+					"Runtime":    "nodejs18.x",
+					"CodeUri":    "s3://bucket/key",
+					"MemorySzie": 256,
+				},
+			},
+			"MyApi": {
+				Type: "AWS::Serverless::Api",
+				Properties: map[string]interface{}{
+					"StageName":    "prod",
+					"StageVariabl": map[string]interface{}{},
+				},
+			},
+			"MyHttpApi": {
+				Type: "AWS::Serverless::HttpApi",
+				Properties: map[string]interface{}{
+					"CorsConfig": true,
+				},
+			},
+		},
+	}
+
+	result, err := tr.Transform(template)
+	if err != nil {
+		t.Fatalf("unknown properties should not fail the transform: %v", err)
+	}
+	if _, ok := result.Resources["MyFunction"]; !ok {
+		t.Error("expected MyFunction to be transformed")
+	}
+
+	warnings := strings.Join(tr.Warnings(), "\n")
+	for _, want := range []string{
+		"resource 'MyFunction': unrecognized property 'MemorySzie' was ignored",
+		"resource 'MyApi': unrecognized property 'StageVariabl' was ignored",
+		"resource 'MyHttpApi': unrecognized property 'CorsConfig' was ignored",
+	} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("expected warning %q, got:\n%s", want, warnings)
+		}
+	}
+}
+
+func TestTransformNoWarningsForKnownProperties(t *testing.T) {
+	tr := New()
+
+	template := &types.Template{
+		AWSTemplateFormatVersion: "2010-09-09",
+		Transform:                "AWS::Serverless-2016-10-31",
+		Resources: map[string]types.Resource{
+			"MyFunction": {
+				Type: "AWS::Serverless::Function",
+				Properties: map[string]interface{}{
+					"Handler":    "index.handler",
+					"Runtime":    "nodejs18.x",
+					"CodeUri":    "s3://bucket/key",
+					"MemorySize": 256,
+				},
+			},
+		},
+	}
+
+	if _, err := tr.Transform(template); err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+	if len(tr.Warnings()) != 0 {
+		t.Errorf("expected no warnings, got %v", tr.Warnings())
+	}
+}
+
+func TestTransformWarnsOnDeprecatedRuntime(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		runtime  string
+		wantWarn bool
+	}{
+		{name: "deprecated runtime", runtime: "nodejs12.x", wantWarn: true},
+		{name: "current runtime", runtime: "python3.12", wantWarn: false},
+		{name: "custom runtime al2023", runtime: "provided.al2023", wantWarn: false},
+		{name: "custom runtime al2", runtime: "provided.al2", wantWarn: false},
+		{name: "legacy custom runtime", runtime: "provided", wantWarn: true},
+		{name: "overridden list", opts: Options{DeprecatedRuntimes: []string{"python3.12"}}, runtime: "python3.12", wantWarn: true},
+		{name: "empty override", opts: Options{DeprecatedRuntimes: []string{}}, runtime: "nodejs12.x", wantWarn: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewWithOptions(tt.opts)
+
+			template := &types.Template{
+				AWSTemplateFormatVersion: "2010-09-09",
+				Transform:                "AWS::Serverless-2016-10-31",
+				Resources: map[string]types.Resource{
+					"MyFunction": {
+						Type: "AWS::Serverless::Function",
+						Properties: map[string]interface{}{
+							"Handler": "index.handler",
+							"Runtime": tt.runtime,
+							"CodeUri": "s3://bucket/key",
+						},
+					},
+				},
+			}
+
+			if _, err := tr.Transform(template); err != nil {
+				t.Fatalf("deprecated runtimes should not fail the transform: %v", err)
+			}
+
+			want := "resource 'MyFunction': runtime '" + tt.runtime + "' is deprecated"
+			got := strings.Contains(strings.Join(tr.Warnings(), "\n"), want)
+			if got != tt.wantWarn {
+				t.Errorf("expected warning=%v for %s, got %v", tt.wantWarn, tt.runtime, tr.Warnings())
+			}
+		})
+	}
+}
+
+func TestTransformWarnsOnLayerTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		props    map[string]interface{}
+		wantWarn bool
+	}{
+		{name: "tags set", props: map[string]interface{}{"ContentUri": "s3://bucket/layer.zip", "Tags": map[string]interface{}{"Team": "backend"}}, wantWarn: true},
+		{name: "no tags", props: map[string]interface{}{"ContentUri": "s3://bucket/layer.zip"}, wantWarn: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := New()
+
+			template := &types.Template{
+				AWSTemplateFormatVersion: "2010-09-09",
+				Transform:                "AWS::Serverless-2016-10-31",
+				Resources: map[string]types.Resource{
+					"MyLayer": {
+						Type:       "AWS::Serverless::LayerVersion",
+						Properties: tt.props,
+					},
+				},
+			}
+
+			result, err := tr.Transform(template)
+			if err != nil {
+				t.Fatalf("layer tags should not fail the transform: %v", err)
+			}
+
+			want := "resource 'MyLayer': Tags are not supported on AWS::Serverless::LayerVersion and were ignored"
+			got := strings.Contains(strings.Join(tr.Warnings(), "\n"), want)
+			if got != tt.wantWarn {
+				t.Errorf("expected warning=%v, got %v", tt.wantWarn, tr.Warnings())
+			}
+
+			for id, res := range result.Resources {
+				if _, ok := res.Properties["Tags"]; ok {
+					t.Errorf("expected no Tags on %s, got %v", id, res.Properties["Tags"])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateSharedTranslatorKeepsWarningsPerCall(t *testing.T) {
+	// Concurrent calls on one Translator must each report only their own warnings
+	tr := New()
+	template := func(runtime string) []byte {
+		return []byte(fmt.Sprintf(`
+Transform: AWS::Serverless-2016-10-31
+Resources:
+  MyFunction:
+    Type: AWS::Serverless::Function
+    Properties:
+      Handler: index.handler
+      Runtime: %s
+      CodeUri: s3://bucket/key
+`, runtime))
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 32)
+	for n := 0; n < 16; n++ {
+		runtime, wantWarnings := "nodejs18.x", 0
+		if n%2 == 0 {
+			runtime, wantWarnings = "python2.7", 1
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			diagnostics, err := tr.Validate(template(runtime))
+			if err != nil {
+				errs <- err
+				return
+			}
+			if len(diagnostics) != wantWarnings {
+				errs <- fmt.Errorf("runtime %s: expected %d diagnostics, got %+v", runtime, wantWarnings, diagnostics)
+			}
+			_ = tr.Warnings()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -39,6 +39,10 @@ type Resource struct {
 	Condition      string                 `json:"Condition,omitempty" yaml:"Condition,omitempty"`
 	DeletionPolicy string                 `json:"DeletionPolicy,omitempty" yaml:"DeletionPolicy,omitempty"`
 	UpdatePolicy   map[string]interface{} `json:"UpdatePolicy,omitempty" yaml:"UpdatePolicy,omitempty"`
+
+	// Connectors holds the SAM connectors embedded in the resource. They are
+	// consumed by the transform, so they are never serialized.
+	Connectors map[string]interface{} `json:"-" yaml:"-"`
 }
 
 // Output represents a CloudFormation output.